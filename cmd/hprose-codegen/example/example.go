@@ -0,0 +1,75 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| cmd/hprose-codegen/example/example.go                    |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+// Package example contains the structs for testing the code generated by hprose-codegen.
+package example
+
+//go:generate go run github.com/hprose/hprose-golang/v3/cmd/hprose-codegen
+
+import "time"
+
+// Status of user.
+type Status int
+
+// Base is embedded in User.
+type Base struct {
+	ID      int64     `json:"id"`
+	Created time.Time `hprose:"created"`
+}
+
+// Address of user.
+//
+//hprose:generate Addr
+type Address struct {
+	City   string
+	Street string
+	Zip    *string
+}
+
+// User is an example struct.
+//
+//hprose:generate
+type User struct {
+	Base
+	Name     string
+	Age      int
+	Score    *float64
+	Male     bool
+	Status   Status
+	Tags     []string
+	Extra    map[string]interface{}
+	Any      interface{}
+	Home     *Address
+	Work     Address
+	Friends  []*User
+	Password string `json:"-"`
+	Callback func()
+	secret   string
+}
+
+// Node is an example struct which can reference itself.
+//
+//hprose:generate
+type Node struct {
+	Name string
+	Next *Node
+}
+
+// Account is an example struct with the encrypt and redact options.
+//
+//hprose:generate
+type Account struct {
+	Name     string
+	Card     string `hprose:"card,encrypt"`
+	Password string `hprose:"password,redact"`
+}
@@ -0,0 +1,264 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| cmd/hprose-codegen/example/example_test.go               |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package example_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/hprose/hprose-golang/v3/cmd/hprose-codegen/example"
+	"github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+// reflectUser has the same fields as User, but it is encoded by the reflective encoder.
+type reflectUser User
+
+func init() {
+	io.RegisterName("ReflectUser", (*reflectUser)(nil))
+}
+
+// reflectAddress has the same fields as Address, but it is encoded by the reflective encoder.
+type reflectAddress Address
+
+func init() {
+	io.RegisterName("ReflectAddress", (*reflectAddress)(nil))
+}
+
+// asUser replaces the class name of reflectUser with the class name of User.
+func asUser(data []byte) string {
+	return strings.Replace(string(data), `c11"ReflectUser"`, `c4"User"`, 1)
+}
+
+func makeUser() *User {
+	score := 99.5
+	zip := "100000"
+	user := &User{
+		Base:   Base{ID: 1, Created: time.Date(2026, 10, 19, 12, 30, 0, 0, time.UTC)},
+		Name:   "Tom",
+		Age:    18,
+		Score:  &score,
+		Male:   true,
+		Status: 2,
+		Tags:   []string{"hello", "world"},
+		Extra:  map[string]interface{}{"level": 3},
+		Any:    "anything",
+		Home:   &Address{City: "Beijing", Street: "Main Street", Zip: &zip},
+		Work:   Address{City: "Shanghai", Street: "Second Street"},
+	}
+	user.Friends = []*User{{Name: "Jerry", Age: 17, Home: user.Home}, user}
+	return user
+}
+
+func TestGeneratedEncoderIsByteCompatible(t *testing.T) {
+	user := makeUser()
+	user.Friends = nil
+	data, err := io.Marshal(user)
+	assert.NoError(t, err)
+	expected, err := io.Marshal((*reflectUser)(user))
+	assert.NoError(t, err)
+	assert.Equal(t, asUser(expected), string(data))
+
+	data, err = io.Marshal(*user)
+	assert.NoError(t, err)
+	expected, err = io.Marshal(reflectUser(*user))
+	assert.NoError(t, err)
+	assert.Equal(t, asUser(expected), string(data))
+}
+
+func TestGeneratedEncoderWithReference(t *testing.T) {
+	user := makeUser()
+	user.Friends = nil
+	formatter := io.Formatter{}
+	data, err := formatter.Marshal(user)
+	assert.NoError(t, err)
+	expected, err := formatter.Marshal((*reflectUser)(user))
+	assert.NoError(t, err)
+	assert.Equal(t, asUser(expected), string(data))
+
+	user = makeUser()
+	data, err = formatter.Marshal(user)
+	assert.NoError(t, err)
+	var result *User
+	assert.NoError(t, formatter.Unmarshal(data, &result))
+	assert.Equal(t, user.Name, result.Name)
+	assert.Equal(t, user.Home, result.Friends[0].Home)
+	assert.Equal(t, user.Name, result.Friends[1].Name)
+	assert.Equal(t, user.Tags, result.Friends[1].Tags)
+}
+
+func TestGeneratedDecoderRoundTrip(t *testing.T) {
+	user := makeUser()
+	user.Friends = nil
+	user.Password = "secret"
+	data, err := io.Marshal(user)
+	assert.NoError(t, err)
+	var result User
+	assert.NoError(t, io.Unmarshal(data, &result))
+	user.Password = ""
+	assert.Equal(t, user.Created.Unix(), result.Created.Unix())
+	result.Created = user.Created
+	assert.Equal(t, *user, result)
+
+	data, err = io.Marshal(map[string]interface{}{"name": "Jerry", "age": 17, "unknown": true})
+	assert.NoError(t, err)
+	result = User{}
+	assert.NoError(t, io.Unmarshal(data, &result))
+	assert.Equal(t, User{Name: "Jerry", Age: 17}, result)
+}
+
+func BenchmarkGeneratedEncoder(b *testing.B) {
+	user := makeUser()
+	user.Friends = nil
+	enc := io.NewEncoder(nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = enc.Encode(user)
+		enc.ResetBuffer().Reset()
+	}
+}
+
+func BenchmarkReflectiveEncoder(b *testing.B) {
+	user := (*reflectUser)(makeUser())
+	user.Friends = nil
+	enc := io.NewEncoder(nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = enc.Encode(user)
+		enc.ResetBuffer().Reset()
+	}
+}
+
+func TestGeneratedEncoderWithCircularReference(t *testing.T) {
+	node := &Node{Name: "a"}
+	node.Next = node
+	_, err := io.Marshal(node)
+	assert.EqualError(t, err, "hprose/io: circular reference: *example.Node -> *example.Node")
+	assert.True(t, io.IsCircularReference(err))
+	data, err := io.Formatter{}.Marshal(node)
+	assert.NoError(t, err)
+	assert.Equal(t, `c4"Node"2{s4"name"s4"next"}o0{uar2;}`, string(data))
+	data, err = io.Formatter{Simple: true, SimpleFallback: true}.Marshal(node)
+	assert.NoError(t, err)
+	assert.Equal(t, `c4"Node"2{s4"name"s4"next"}o0{uar2;}`, string(data))
+}
+
+func BenchmarkGeneratedAddressEncoder(b *testing.B) {
+	zip := "100000"
+	address := &Address{City: "Beijing", Street: "Main Street", Zip: &zip}
+	enc := io.NewEncoder(nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = enc.Encode(address)
+		enc.ResetBuffer().Reset()
+	}
+}
+
+func BenchmarkReflectiveAddressEncoder(b *testing.B) {
+	zip := "100000"
+	address := &reflectAddress{City: "Beijing", Street: "Main Street", Zip: &zip}
+	enc := io.NewEncoder(nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = enc.Encode(address)
+		enc.ResetBuffer().Reset()
+	}
+}
+
+func BenchmarkGeneratedDecoder(b *testing.B) {
+	user := makeUser()
+	user.Friends = nil
+	data, _ := io.Marshal(user)
+	dec := io.NewDecoder(data)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var result User
+		dec.ResetBytes(data).Reset()
+		dec.Decode(&result)
+	}
+}
+
+func BenchmarkReflectiveDecoder(b *testing.B) {
+	user := makeUser()
+	user.Friends = nil
+	data, _ := io.Marshal(user)
+	dec := io.NewDecoder(data)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var result reflectUser
+		dec.ResetBytes(data).Reset()
+		dec.Decode(&result)
+	}
+}
+
+// reflectAccount has the same fields as Account, but it is encoded by the reflective encoder.
+type reflectAccount Account
+
+func init() {
+	io.RegisterName("ReflectAccount", (*reflectAccount)(nil))
+}
+
+type xorCipher byte
+
+func (c xorCipher) xor(data []byte) []byte {
+	result := make([]byte, len(data))
+	for i, b := range data {
+		result[i] = b ^ byte(c)
+	}
+	return result
+}
+
+func (c xorCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	return c.xor(plaintext), nil
+}
+
+func (c xorCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	return c.xor(ciphertext), nil
+}
+
+func TestGeneratedEncoderWithOptions(t *testing.T) {
+	account := &Account{Name: "Tom", Card: "6222000011112222", Password: "secret"}
+	asAccount := func(data []byte) string {
+		return strings.Replace(string(data), `c14"ReflectAccount"`, `c7"Account"`, 1)
+	}
+	for _, formatter := range []io.Formatter{{Cipher: xorCipher(0x5a)}, {Redact: true}} {
+		data, err := formatter.Marshal(account)
+		assert.NoError(t, err)
+		expected, err := formatter.Marshal((*reflectAccount)(account))
+		assert.NoError(t, err)
+		assert.Equal(t, asAccount(expected), string(data))
+	}
+
+	formatter := io.Formatter{Cipher: xorCipher(0x5a)}
+	data, err := formatter.Marshal(account)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "6222000011112222")
+	var result Account
+	assert.NoError(t, formatter.Unmarshal(data, &result))
+	assert.Equal(t, *account, result)
+
+	data, err = io.Formatter{Redact: true}.Marshal(account)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	result = Account{}
+	assert.NoError(t, io.Unmarshal(data, &result))
+	assert.Equal(t, Account{Name: "Tom", Card: "6222000011112222", Password: io.Redacted}, result)
+}
@@ -0,0 +1,486 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| cmd/hprose-codegen/generator.go                          |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultOutput = "hprose_gen.go"
+	annotation    = "//hprose:generate"
+)
+
+var defaultTags = []string{"hprose", "json"}
+
+// writeMethods maps the predeclared types to the Encoder methods.
+var writeMethods = map[string]string{
+	"bool":       "WriteBool",
+	"int":        "WriteInt",
+	"int8":       "WriteInt8",
+	"int16":      "WriteInt16",
+	"int32":      "WriteInt32",
+	"rune":       "WriteInt32",
+	"int64":      "WriteInt64",
+	"uint":       "WriteUint",
+	"uint8":      "WriteUint8",
+	"byte":       "WriteUint8",
+	"uint16":     "WriteUint16",
+	"uint32":     "WriteUint32",
+	"uint64":     "WriteUint64",
+	"float32":    "WriteFloat32",
+	"float64":    "WriteFloat64",
+	"complex64":  "WriteComplex64",
+	"complex128": "WriteComplex128",
+	"string":     "EncodeString",
+}
+
+type field struct {
+	path    string
+	alias   string
+	method  string
+	ptr     bool
+	handler int
+	// accessor is true if the field has the encrypt or redact option, it is
+	// written and read by the handlers of the reflective field accessor.
+	accessor bool
+}
+
+type structType struct {
+	name     string
+	alias    string
+	fields   []field
+	handlers []string
+}
+
+type generator struct {
+	pkg     string
+	tags    []string
+	types   map[string]ast.Expr
+	structs []*structType
+}
+
+// Generate returns the generated source code for the annotated structs in dir.
+// The file named output in dir is skipped.
+func Generate(dir string, tags []string, output string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		name := fi.Name()
+		return name != output && !strings.HasSuffix(name, "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+	g := &generator{tags: tags, types: map[string]ast.Expr{}}
+	var files []*ast.File
+	for name, pkg := range pkgs {
+		g.pkg = name
+		filenames := make([]string, 0, len(pkg.Files))
+		for filename := range pkg.Files {
+			filenames = append(filenames, filename)
+		}
+		sort.Strings(filenames)
+		for _, filename := range filenames {
+			files = append(files, pkg.Files[filename])
+		}
+	}
+	var specs []*ast.TypeSpec
+	var aliases []string
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range decl.Specs {
+				spec := spec.(*ast.TypeSpec)
+				g.types[spec.Name.Name] = spec.Type
+				doc := spec.Doc
+				if doc == nil && len(decl.Specs) == 1 {
+					doc = decl.Doc
+				}
+				if alias, ok := parseAnnotation(doc); ok {
+					if alias == "" {
+						alias = spec.Name.Name
+					}
+					specs = append(specs, spec)
+					aliases = append(aliases, alias)
+				}
+			}
+		}
+	}
+	if len(specs) == 0 {
+		return nil, errors.New("no struct annotated with " + annotation + " in " + dir)
+	}
+	for i, spec := range specs {
+		st, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a struct", spec.Name.Name)
+		}
+		s := &structType{name: spec.Name.Name, alias: aliases[i]}
+		if err := g.addFields(s, st, "", map[string]struct{}{}); err != nil {
+			return nil, err
+		}
+		g.structs = append(g.structs, s)
+	}
+	return g.generate()
+}
+
+func parseAnnotation(doc *ast.CommentGroup) (alias string, ok bool) {
+	if doc == nil {
+		return
+	}
+	for _, c := range doc.List {
+		if c.Text == annotation {
+			return "", true
+		}
+		if strings.HasPrefix(c.Text, annotation+" ") {
+			return strings.TrimSpace(c.Text[len(annotation):]), true
+		}
+	}
+	return
+}
+
+func (g *generator) underlying(t ast.Expr) ast.Expr {
+	for i := 0; i < 100; i++ {
+		switch e := t.(type) {
+		case *ast.ParenExpr:
+			t = e.X
+		case *ast.Ident:
+			u, ok := g.types[e.Name]
+			if !ok {
+				return t
+			}
+			t = u
+		default:
+			return t
+		}
+	}
+	return t
+}
+
+func embeddedName(t ast.Expr) string {
+	switch e := t.(type) {
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.Ident:
+		return e.Name
+	}
+	return ""
+}
+
+func (g *generator) tagNames() []string {
+	if len(g.tags) == 0 {
+		return defaultTags
+	}
+	return g.tags
+}
+
+func (g *generator) hasOption(tag *ast.BasicLit, option string) bool {
+	if tag == nil {
+		return false
+	}
+	s, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return false
+	}
+	for _, tagname := range g.tagNames() {
+		if tagname == "" {
+			continue
+		}
+		options := strings.Split(reflect.StructTag(s).Get(tagname), ",")
+		for _, o := range options[1:] {
+			if strings.Trim(o, " ") == option {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (g *generator) fieldAlias(tag *ast.BasicLit, name string) string {
+	if tag != nil {
+		if s, err := strconv.Unquote(tag.Value); err == nil {
+			for _, tagname := range g.tagNames() {
+				if tagname == "" {
+					continue
+				}
+				alias := reflect.StructTag(s).Get(tagname)
+				if i := strings.Index(alias, ","); i >= 0 {
+					alias = alias[:i]
+				}
+				if alias = strings.Trim(alias, " "); alias != "" {
+					return alias
+				}
+			}
+		}
+	}
+	if name[0] >= 'A' && name[0] <= 'Z' {
+		name = string(name[0]-'A'+'a') + name[1:]
+	}
+	return name
+}
+
+func (g *generator) addFields(s *structType, st *ast.StructType, prefix string, mapping map[string]struct{}) error {
+	for _, f := range st.Fields.List {
+		names := make([]string, 0, len(f.Names))
+		for _, name := range f.Names {
+			names = append(names, name.Name)
+		}
+		u := g.underlying(f.Type)
+		switch u := u.(type) {
+		case *ast.FuncType, *ast.ChanType:
+			continue
+		case *ast.SelectorExpr:
+			if x, ok := u.X.(*ast.Ident); ok && x.Name == "unsafe" && u.Sel.Name == "Pointer" {
+				continue
+			}
+		case *ast.StructType:
+			if len(names) == 0 {
+				if _, ok := f.Type.(*ast.StructType); ok {
+					return fmt.Errorf("%s: unsupported embedded field", s.name)
+				}
+				name := embeddedName(f.Type)
+				if err := g.addFields(s, u, prefix+name+".", mapping); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if len(names) == 0 {
+			name := embeddedName(f.Type)
+			if _, ok := f.Type.(*ast.SelectorExpr); ok {
+				return fmt.Errorf("%s: unsupported embedded field %s from other package", s.name, name)
+			}
+			names = append(names, name)
+		}
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				continue
+			}
+			alias := g.fieldAlias(f.Tag, name)
+			if alias == "-" {
+				continue
+			}
+			if _, ok := mapping[alias]; ok {
+				return fmt.Errorf("%s: ambiguous fields with the same name or alias: %s", s.name, alias)
+			}
+			mapping[alias] = struct{}{}
+			accessor := g.hasOption(f.Tag, "encrypt") || g.hasOption(f.Tag, "redact")
+			s.fields = append(s.fields, g.makeField(s, f.Type, prefix+name, alias, accessor))
+		}
+	}
+	return nil
+}
+
+func (g *generator) makeField(s *structType, t ast.Expr, path, alias string, accessor bool) field {
+	fd := field{path: path, alias: alias, handler: -1, accessor: accessor}
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+		fd.ptr = true
+	}
+	if ident, ok := t.(*ast.Ident); ok && !accessor {
+		if _, local := g.types[ident.Name]; !local {
+			if method, ok := writeMethods[ident.Name]; ok {
+				fd.method = method
+				return fd
+			}
+		}
+	}
+	fd.ptr = false
+	fd.handler = len(s.handlers)
+	s.handlers = append(s.handlers, path)
+	return fd
+}
+
+func (g *generator) generate() ([]byte, error) {
+	var b bytes.Buffer
+	p := func(format string, args ...interface{}) {
+		fmt.Fprintf(&b, format, args...)
+		b.WriteByte('\n')
+	}
+	p("// Code generated by hprose-codegen. DO NOT EDIT.")
+	p("")
+	p("package %s", g.pkg)
+	p("")
+	p("import (")
+	p("\t\"reflect\"")
+	if g.hasFields() {
+		p("\t\"unsafe\"")
+	}
+	p("")
+	p("\t\"github.com/hprose/hprose-golang/v3/io\"")
+	p(")")
+	for _, s := range g.structs {
+		g.generateStruct(p, s)
+	}
+	p("")
+	p("func init() {")
+	for _, s := range g.structs {
+		if g.hasDefaultTags() {
+			p("\tio.RegisterName(%q, (*%s)(nil))", s.alias, s.name)
+		} else {
+			p("\tio.RegisterName(%q, (*%s)(nil), %s)", s.alias, s.name, quoteAll(g.tags))
+		}
+		p("\tio.RegisterValueEncoder((*%s)(nil), hprose%sEncoder{})", s.name, s.name)
+		p("\tio.RegisterValueDecoder(%s{}, hprose%sDecoder{})", s.name, s.name)
+	}
+	tags := ""
+	if !g.hasDefaultTags() {
+		tags = ", " + quoteAll(g.tags)
+	}
+	for _, s := range g.structs {
+		for _, f := range s.fields {
+			switch {
+			case f.accessor:
+				p("\thprose%sEncodeHandlers[%d] = io.GetStructFieldEncodeHandler(hprose%sType, %q%s)", s.name, f.handler, s.name, f.alias, tags)
+			case f.handler >= 0:
+				p("\thprose%sEncodeHandlers[%d] = io.GetFieldEncodeHandler(reflect.TypeOf(&(&%s{}).%s).Elem())", s.name, f.handler, s.name, f.path)
+			}
+		}
+		for i, f := range s.fields {
+			p("\thprose%sFieldTypes[%d] = reflect.TypeOf(&(&%s{}).%s).Elem()", s.name, i, s.name, f.path)
+			if f.accessor {
+				p("\thprose%sDecodeHandlers[%d] = io.GetStructFieldDecodeHandler(hprose%sType, %q%s)", s.name, i, s.name, f.alias, tags)
+			} else {
+				p("\thprose%sDecodeHandlers[%d] = io.GetDecodeHandler(hprose%sFieldTypes[%d])", s.name, i, s.name, i)
+			}
+		}
+	}
+	p("}")
+	return format.Source(b.Bytes())
+}
+
+func (g *generator) hasFields() bool {
+	for _, s := range g.structs {
+		if len(s.fields) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *generator) hasDefaultTags() bool {
+	return strings.Join(g.tags, ",") == strings.Join(defaultTags, ",")
+}
+
+func quoteAll(s []string) string {
+	q := make([]string, len(s))
+	for i := range s {
+		q[i] = strconv.Quote(s[i])
+	}
+	return strings.Join(q, ", ")
+}
+
+func (g *generator) generateStruct(p func(format string, args ...interface{}), s *structType) {
+	name := s.name
+	aliases := make([]string, len(s.fields))
+	for i, f := range s.fields {
+		aliases[i] = strconv.Quote(f.alias)
+	}
+	p("")
+	p("var hprose%sType = reflect.TypeOf((*%s)(nil)).Elem()", name, name)
+	p("")
+	if len(aliases) > 0 {
+		p("var hprose%sMetadata = io.NewStructMetadata(hprose%sType, %q, %s)", name, name, s.alias, strings.Join(aliases, ", "))
+	} else {
+		p("var hprose%sMetadata = io.NewStructMetadata(hprose%sType, %q)", name, name, s.alias)
+	}
+	if len(s.handlers) > 0 {
+		p("")
+		p("var hprose%sEncodeHandlers [%d]io.FieldEncodeHandler", name, len(s.handlers))
+	}
+	if len(s.fields) > 0 {
+		p("")
+		p("var hprose%sFieldTypes [%d]reflect.Type", name, len(s.fields))
+		p("")
+		p("var hprose%sDecodeHandlers [%d]io.DecodeHandler", name, len(s.fields))
+	}
+	p("")
+	p("// hprose%sEncoder is the implementation of ValueEncoder for %s/*%s.", name, name, name)
+	p("type hprose%sEncoder struct{}", name)
+	p("")
+	p("func (valenc hprose%sEncoder) Encode(enc *io.Encoder, v interface{}) {", name)
+	p("\tenc.EncodeReference(valenc, v)")
+	p("}")
+	p("")
+	p("func (valenc hprose%sEncoder) Write(enc *io.Encoder, v interface{}) {", name)
+	if len(s.fields) == 0 {
+		p("\tif enc.WriteStructHead(hprose%sMetadata, v) == nil {", name)
+		p("\t\treturn")
+		p("\t}")
+	} else {
+		p("\tp := (*%s)(enc.WriteStructHead(hprose%sMetadata, v))", name, name)
+		p("\tif p == nil {")
+		p("\t\treturn")
+		p("\t}")
+		for _, f := range s.fields {
+			switch {
+			case f.handler >= 0:
+				p("\thprose%sEncodeHandlers[%d](enc, unsafe.Pointer(&p.%s))", name, f.handler, f.path)
+			case f.ptr:
+				p("\tif p.%s == nil {", f.path)
+				p("\t\tenc.WriteNil()")
+				p("\t} else {")
+				p("\t\tenc.%s(*p.%s)", f.method, f.path)
+				p("\t}")
+			default:
+				p("\tenc.%s(p.%s)", f.method, f.path)
+			}
+		}
+	}
+	p("\tenc.WriteStructFoot(v)")
+	p("}")
+	p("")
+	p("// hprose%sDecoder is the implementation of ValueDecoder for %s.", name, name)
+	p("type hprose%sDecoder struct{}", name)
+	p("")
+	p("func (valdec hprose%sDecoder) Decode(dec *io.Decoder, p interface{}, tag byte) {", name)
+	p("\tdec.DecodeStruct(hprose%sType, valdec, p, tag)", name)
+	p("}")
+	p("")
+	p("func (valdec hprose%sDecoder) DecodeField(dec *io.Decoder, p interface{}, name string) bool {", name)
+	if len(s.fields) == 0 {
+		p("\treturn false")
+		p("}")
+		return
+	}
+	p("\tv := p.(*%s)", name)
+	p("\tswitch name {")
+	for i, f := range s.fields {
+		p("\tcase %q:", f.alias)
+		p("\t\thprose%sDecodeHandlers[%d](dec, hprose%sFieldTypes[%d], unsafe.Pointer(&v.%s))", name, i, name, i, f.path)
+	}
+	p("\tdefault:")
+	p("\t\treturn false")
+	p("\t}")
+	p("\treturn true")
+	p("}")
+}
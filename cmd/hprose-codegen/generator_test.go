@@ -0,0 +1,68 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| cmd/hprose-codegen/generator_test.go                     |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate("example", defaultTags, defaultOutput)
+	assert.NoError(t, err)
+	expected, err := ioutil.ReadFile(filepath.Join("example", defaultOutput))
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(src))
+}
+
+func TestGenerateErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hprose-codegen")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	write := func(src string) {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.go"), []byte(src), 0o644))
+	}
+
+	write("package a\n\ntype A struct{}\n")
+	_, err = Generate(dir, defaultTags, defaultOutput)
+	assert.EqualError(t, err, "no struct annotated with //hprose:generate in "+dir)
+
+	write("package a\n\n//hprose:generate\ntype A int\n")
+	_, err = Generate(dir, defaultTags, defaultOutput)
+	assert.EqualError(t, err, "A is not a struct")
+
+	write("package a\n\n//hprose:generate\ntype A struct {\n\tX int\n\tY int `json:\"x\"`\n}\n")
+	_, err = Generate(dir, defaultTags, defaultOutput)
+	assert.EqualError(t, err, "A: ambiguous fields with the same name or alias: x")
+
+	write("package a\n\nimport \"time\"\n\n//hprose:generate\ntype A struct {\n\ttime.Time\n}\n")
+	_, err = Generate(dir, defaultTags, defaultOutput)
+	assert.EqualError(t, err, "A: unsupported embedded field Time from other package")
+}
+
+func TestGenerateWithTags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hprose-codegen")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	src := "package a\n\n//hprose:generate\ntype A struct {\n\tX int `xml:\"x1\" json:\"x2\"`\n}\n"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.go"), []byte(src), 0o644))
+	result, err := Generate(dir, []string{"xml"}, defaultOutput)
+	assert.NoError(t, err)
+	assert.Contains(t, string(result), `io.NewStructMetadata(hproseAType, "A", "x1")`)
+	assert.Contains(t, string(result), `io.RegisterName("A", (*A)(nil), "xml")`)
+}
@@ -0,0 +1,64 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| cmd/hprose-codegen/main.go                               |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+// hprose-codegen generates hprose ValueEncoder and ValueDecoder implementations
+// for the structs annotated with the //hprose:generate comment.
+//
+// Usage:
+//
+//	hprose-codegen [-output file] [-tags hprose,json] [dir]
+//
+// The comment can be followed by the registered name of the struct:
+//
+//	//hprose:generate User
+//	type UserDTO struct { ... }
+//
+// The generated code registers the struct by io.RegisterName, io.RegisterValueEncoder
+// and io.RegisterValueDecoder in init, and is byte-compatible with the reflective encoder.
+// The fields of the predeclared types are written by the typed Encoder methods, and
+// the other fields are written and read by the handlers resolved in init, so the
+// field accessors and the field lookups of the reflective path are skipped. It does
+// not change the cost of the values themselves, such as strings, slices and maps.
+// The fields with the encrypt or redact option are written and read by the handlers
+// of the reflective field accessors, so they are encrypted and redacted as usual.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	output := flag.String("output", "", "output file name; default <dir>/hprose_gen.go")
+	tags := flag.String("tags", "hprose,json", "comma-separated list of struct tags for field alias")
+	flag.Parse()
+	dir := "."
+	if flag.NArg() > 0 {
+		dir = flag.Arg(0)
+	}
+	if *output == "" {
+		*output = filepath.Join(dir, defaultOutput)
+	}
+	src, err := Generate(dir, strings.Split(*tags, ","), filepath.Base(*output))
+	if err == nil {
+		err = ioutil.WriteFile(*output, src, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "hprose-codegen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,56 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| cmd/hprose-dump/main.go                                  |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+// hprose-dump reads hprose data from stdin and prints it as indented
+// annotated text.
+//
+// Usage:
+//
+//	hprose-dump [-resolve] [-indent string] < data
+//
+// The trailing newlines of the input are ignored.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	hio "github.com/hprose/hprose-golang/v3/io"
+)
+
+func dump(r io.Reader, w io.Writer, printer hio.Printer) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s, err := printer.Format(bytes.TrimRight(data, "\r\n"))
+	if _, e := io.WriteString(w, s); err == nil {
+		err = e
+	}
+	return err
+}
+
+func main() {
+	resolve := flag.Bool("resolve", false, "resolve the references inline")
+	indent := flag.String("indent", "  ", "the string for each level of indentation")
+	flag.Parse()
+	printer := hio.Printer{Indent: *indent, ResolveReferences: *resolve}
+	if err := dump(os.Stdin, os.Stdout, printer); err != nil {
+		fmt.Fprintln(os.Stderr, "hprose-dump:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,40 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| cmd/hprose-dump/main_test.go                             |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	hio "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDump(t *testing.T) {
+	sb := &strings.Builder{}
+	err := dump(strings.NewReader("Cs3\"sum\"a2{12}z\n"), sb, hio.Printer{Indent: "  "})
+	assert.NoError(t, err)
+	assert.Equal(t, `call
+  string "sum" #0
+  list(2) #0 [
+    int 1
+    int 2
+  ]
+end
+`, sb.String())
+	sb.Reset()
+	err = dump(strings.NewReader(`Ra1{`), sb, hio.Printer{Indent: "  "})
+	assert.EqualError(t, err, "hprose/io: unexpected end of data at offset 4")
+	assert.Equal(t, "result\n  list(1) #0 []\n", sb.String())
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | io/array_decoder.go                                      |
 |                                                          |
-| LastModified: Mar 5, 2022                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -35,8 +35,11 @@ func (valdec arrayDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 	case TagNull, TagEmpty:
 		valdec.at.UnsafeSet(reflect2.PtrOf(p), valdec.empty)
 	case TagList:
+		if !dec.enter() {
+			return
+		}
 		length := valdec.at.Len()
-		count := dec.ReadInt()
+		count := dec.readCount(0)
 		array := reflect2.PtrOf(p)
 		dec.AddReference(p)
 		n := length
@@ -58,6 +61,7 @@ func (valdec arrayDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 				valdec.decodeElem(dec, et, temp)
 			}
 		}
+		dec.leave()
 		dec.Skip()
 	default:
 		dec.defaultDecode(valdec.at.Type1(), p, tag)
@@ -96,7 +100,7 @@ func (valdec byteArrayDecoder) copy(p interface{}, data []byte) {
 func (valdec byteArrayDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 	switch tag {
 	case TagBytes:
-		data := dec.UnsafeNext(dec.ReadInt())
+		data := dec.UnsafeNext(dec.readLength())
 		dec.Skip()
 		valdec.copy(p, data)
 		dec.AddReference(p)
@@ -105,7 +109,7 @@ func (valdec byteArrayDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 		valdec.copy(p, data)
 	case TagString:
 		if dec.IsSimple() {
-			data, _ := dec.readStringAsBytes(dec.ReadInt())
+			data, _ := dec.readStringAsBytes(dec.readLength())
 			dec.Skip()
 			valdec.copy(p, data)
 		} else {
@@ -6,7 +6,7 @@
 |                                                          |
 | io/bytes_decoder.go                                      |
 |                                                          |
-| LastModified: Mar 5, 2022                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -21,13 +21,16 @@ import (
 )
 
 func (dec *Decoder) readUnsafeBytes() []byte {
-	bytes := dec.UnsafeNext(dec.ReadInt())
+	bytes := dec.UnsafeNext(dec.readLength())
 	dec.Skip()
 	return bytes
 }
 
 func (dec *Decoder) readBytes() []byte {
-	bytes := dec.Next(dec.ReadInt())
+	bytes, safe := dec.next(dec.readLength())
+	if !safe {
+		bytes = dec.arenaCopy(bytes)
+	}
 	dec.Skip()
 	return bytes
 }
@@ -35,19 +38,21 @@ func (dec *Decoder) readBytes() []byte {
 // ReadBytes reads bytes and add reference.
 func (dec *Decoder) ReadBytes() []byte {
 	bytes := dec.readBytes()
-	if !dec.IsSimple() {
-		dec.refer.Add(bytes)
-	}
+	dec.AddReference(bytes)
 	return bytes
 }
 
 func (dec *Decoder) readUint8Slice(et reflect.Type) []byte {
-	count := dec.ReadInt()
+	if !dec.enter() {
+		return nil
+	}
+	count := dec.readCount(1)
 	slice := make([]byte, count)
 	dec.AddReference(slice)
 	for i := 0; i < count; i++ {
 		dec.decodeUint8(et, dec.NextByte(), &slice[i])
 	}
+	dec.leave()
 	dec.Skip()
 	return slice
 }
@@ -0,0 +1,205 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/canonical.go                                          |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// Canonical sets the encoder to canonical mode or not.
+//
+// In canonical mode the same value is always encoded to the same bytes: the
+// map entries are sorted by their keys, negative zero is written as zero,
+// and time.Time is written in UTC.
+//
+// The map keys are ordered by nil, bool (false before true), numbers by
+// their values, strings by their UTF-8 bytes, and the other keys by their
+// canonical encoding. The keys of different types which are equal are
+// ordered by their canonical encoding, and then by their type names.
+func (enc *Encoder) Canonical(canonical bool) *Encoder {
+	enc.canonical = canonical
+	return enc
+}
+
+// IsCanonical returns the encoder is in canonical mode or not.
+func (enc *Encoder) IsCanonical() bool {
+	return enc.canonical
+}
+
+const (
+	rankNil = iota
+	rankBool
+	rankNumber
+	rankString
+	rankOther
+)
+
+func keyRank(k reflect.Value) int {
+	switch k.Kind() {
+	case reflect.Invalid:
+		return rankNil
+	case reflect.Bool:
+		return rankBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return rankNumber
+	case reflect.String:
+		return rankString
+	}
+	return rankOther
+}
+
+func isSigned(k reflect.Value) bool {
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUnsigned(k reflect.Value) bool {
+	switch k.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func toFloat(k reflect.Value) float64 {
+	switch {
+	case isSigned(k):
+		return float64(k.Int())
+	case isUnsigned(k):
+		return float64(k.Uint())
+	}
+	return k.Float()
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareNumber(a, b reflect.Value) int {
+	switch {
+	case isSigned(a) && isSigned(b):
+		x, y := a.Int(), b.Int()
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case isUnsigned(a) && isUnsigned(b):
+		return compareUint(a.Uint(), b.Uint())
+	case isSigned(a) && isUnsigned(b):
+		if a.Int() < 0 {
+			return -1
+		}
+		return compareUint(uint64(a.Int()), b.Uint())
+	case isUnsigned(a) && isSigned(b):
+		return -compareNumber(b, a)
+	}
+	x, y := toFloat(a), toFloat(b)
+	switch {
+	case x != x && y != y:
+		return 0
+	case x != x, x < y:
+		return -1
+	case y != y, x > y:
+		return 1
+	}
+	return 0
+}
+
+type mapEntry struct {
+	key   interface{}
+	value interface{}
+	data  []byte
+}
+
+// encoded returns the canonical encoding of the key.
+func (e *mapEntry) encoded() []byte {
+	if e.data == nil {
+		enc := new(Encoder).Simple(true).Canonical(true)
+		enc.encode(e.key)
+		e.data = enc.buf
+	}
+	return e.data
+}
+
+func compareMapEntry(a, b *mapEntry) int {
+	x, y := reflect.ValueOf(a.key), reflect.ValueOf(b.key)
+	r := keyRank(x)
+	if c := r - keyRank(y); c != 0 {
+		return c
+	}
+	var c int
+	switch r {
+	case rankNil:
+		return 0
+	case rankBool:
+		if x.Bool() != y.Bool() {
+			if x.Bool() {
+				return 1
+			}
+			return -1
+		}
+	case rankNumber:
+		c = compareNumber(x, y)
+	case rankString:
+		c = strings.Compare(x.String(), y.String())
+	}
+	if c == 0 {
+		c = bytes.Compare(a.encoded(), b.encoded())
+	}
+	if c == 0 {
+		c = strings.Compare(x.Type().String(), y.Type().String())
+	}
+	return c
+}
+
+// writeCanonicalMapBody writes the map entries sorted by their keys.
+func (enc *Encoder) writeCanonicalMapBody(v interface{}) {
+	mapType := reflect2.TypeOf(v).(*reflect2.UnsafeMapType)
+	p := reflect2.PtrOf(v)
+	iter := mapType.UnsafeIterate(unsafe.Pointer(&p))
+	kt := mapType.Key()
+	vt := mapType.Elem()
+	entries := make([]mapEntry, 0, reflect.ValueOf(v).Len())
+	for iter.HasNext() {
+		kp, vp := iter.UnsafeNext()
+		entries = append(entries, mapEntry{key: kt.UnsafeIndirect(kp), value: vt.UnsafeIndirect(vp)})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return compareMapEntry(&entries[i], &entries[j]) < 0
+	})
+	for i := range entries {
+		enc.encode(entries[i].key)
+		enc.encode(entries[i].value)
+	}
+}
@@ -0,0 +1,91 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/canonical_test.go                                     |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func canonical(v interface{}) string {
+	enc := io.GetEncoder().Simple(true).Canonical(true)
+	defer io.FreeEncoder(enc)
+	enc.Encode(v)
+	return string(enc.Bytes())
+}
+
+func TestCanonicalStringKeys(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3, "ab": 4}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, `m4{ua1s2"ab"4ub2uc3}`, canonical(m))
+	}
+}
+
+func TestCanonicalNumberKeys(t *testing.T) {
+	m := map[int]string{10: "x", -1: "y", 2: "z", 0: "w"}
+	assert.Equal(t, `m4{i-1;uy0uw2uzi10;ux}`, canonical(m))
+	f := map[float64]int{1.5: 1, math.Inf(-1): 2, 0.5: 3}
+	assert.Equal(t, `m3{I-2d0.5;3d1.5;1}`, canonical(f))
+}
+
+func TestCanonicalInterfaceKeys(t *testing.T) {
+	m := map[interface{}]int{
+		"a":        1,
+		nil:        2,
+		true:       3,
+		false:      4,
+		uint8(200): 5,
+		-3:         6,
+		2.5:        7,
+	}
+	assert.Equal(t, `m7{n2f4t3i-3;6d2.5;7i200;5ua1}`, canonical(m))
+}
+
+func TestCanonicalNestedMap(t *testing.T) {
+	m := map[string]map[string]int{
+		"y": {"b": 1, "a": 2},
+		"x": {"d": 3, "c": 4},
+	}
+	assert.Equal(t, `m2{uxm2{uc4ud3}uym2{ua2ub1}}`, canonical(m))
+}
+
+func TestCanonicalFloat(t *testing.T) {
+	assert.Equal(t, `d0;`, canonical(math.Copysign(0, -1)))
+	assert.Equal(t, `d0;`, canonical(float32(math.Copysign(0, -1))))
+	enc := io.GetEncoder().Simple(true)
+	defer io.FreeEncoder(enc)
+	enc.Encode(math.Copysign(0, -1))
+	assert.Equal(t, `d-0;`, string(enc.Bytes()))
+}
+
+func TestCanonicalTime(t *testing.T) {
+	utc := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	local := utc.In(time.FixedZone("UTC+8", 8*3600))
+	assert.Equal(t, canonical(utc), canonical(local))
+	assert.Equal(t, `D20200102T030405Z`, canonical(local))
+}
+
+func TestFormatterCanonical(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1}
+	data, err := io.Formatter{Simple: true, Canonical: true}.Marshal(m)
+	assert.NoError(t, err)
+	assert.Equal(t, `m2{ua1ub2}`, string(data))
+	enc := io.GetEncoder().Canonical(true)
+	io.FreeEncoder(enc)
+	assert.False(t, io.GetEncoder().IsCanonical())
+}
@@ -0,0 +1,102 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/cipher.go                                             |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Cipher encrypts and decrypts the fields with the encrypt option.
+//
+// A field with the encrypt option, for example:
+//
+//	Card string `hprose:"card,encrypt"`
+//
+// is encoded alone, then encrypted by the Cipher of Encoder and written as
+// bytes. The Decoder reads the bytes, decrypts them by its Cipher, and then
+// decodes the plaintext into the field. The fields are written and read as
+// usual if Cipher is nil.
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+func encryptEncodeHandler(encode EncodeHandler) EncodeHandler {
+	return func(enc *Encoder, v interface{}) {
+		if enc.Cipher == nil {
+			encode(enc, v)
+			return
+		}
+		plain := GetEncoder().Canonical(enc.canonical).TypedUnion(enc.typedUnion)
+		defer FreeEncoder(plain)
+		plain.TimeZoneMode = enc.TimeZoneMode
+		plain.Cipher = enc.Cipher
+		encode(plain, v)
+		err := plain.Error
+		var ciphertext []byte
+		if err == nil {
+			ciphertext, err = enc.Cipher.Encrypt(plain.Bytes())
+		}
+		if err != nil {
+			if enc.Error == nil {
+				enc.Error = err
+			}
+			enc.WriteNil()
+			return
+		}
+		if ciphertext == nil {
+			ciphertext = []byte{}
+		}
+		enc.AddReferenceCount(1)
+		enc.buf = appendBytes(enc.buf, ciphertext)
+	}
+}
+
+func encryptDecodeHandler(decode DecodeHandler) DecodeHandler {
+	return func(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
+		if dec.Cipher == nil {
+			decode(dec, t, p)
+			return
+		}
+		tag := dec.NextByte()
+		if tag != TagBytes {
+			dec.Decode(reflect.NewAt(t, p).Interface(), tag)
+			return
+		}
+		plaintext, err := dec.Cipher.Decrypt(dec.ReadBytes())
+		if err != nil {
+			if dec.Error == nil {
+				dec.Error = err
+			}
+			return
+		}
+		plain := GetDecoder().ResetBytes(plaintext)
+		defer FreeDecoder(plain)
+		plain.LongType = dec.LongType
+		plain.RealType = dec.RealType
+		plain.MapType = dec.MapType
+		plain.StructType = dec.StructType
+		plain.ListType = dec.ListType
+		plain.MergeMode = dec.MergeMode
+		plain.Limits = dec.Limits
+		plain.Location = dec.Location
+		plain.Interner = dec.Interner
+		plain.Cipher = dec.Cipher
+		decode(plain, t, p)
+		if plain.Error != nil && dec.Error == nil {
+			dec.Error = plain.Error
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/cipher_test.go                                        |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+type xorCipher byte
+
+func (c xorCipher) xor(data []byte) []byte {
+	result := make([]byte, len(data))
+	for i, b := range data {
+		result[i] = b ^ byte(c)
+	}
+	return result
+}
+
+func (c xorCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	return c.xor(plaintext), nil
+}
+
+func (c xorCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) == 0 {
+		return nil, errors.New("empty ciphertext")
+	}
+	return c.xor(ciphertext), nil
+}
+
+type cipherAccount struct {
+	Name  string   `hprose:"name"`
+	Card  string   `hprose:"card,encrypt"`
+	Codes []int    `hprose:"codes,encrypt"`
+	Token *string  `hprose:"token,encrypt,redact"`
+	Tags  []string `hprose:"tags"`
+}
+
+func TestCipher(t *testing.T) {
+	token := "abc"
+	account := cipherAccount{"Tom", "1234", []int{1, 2}, &token, []string{"1234"}}
+	f := io.Formatter{Cipher: xorCipher(0x5a)}
+	data, err := f.Marshal(account)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), `"1234"`))
+	assert.NotContains(t, string(data), `"abc"`)
+
+	var result cipherAccount
+	assert.NoError(t, f.Unmarshal(data, &result))
+	assert.Equal(t, account, result)
+
+	data, err = io.Formatter{Cipher: xorCipher(0x5a), Redact: true}.Marshal(account)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `s10"[REDACTED]"`)
+
+	data, err = io.Formatter{}.Marshal(account)
+	assert.NoError(t, err)
+	result = cipherAccount{}
+	assert.NoError(t, f.Unmarshal(data, &result))
+	assert.Equal(t, account, result)
+	result = cipherAccount{}
+	assert.NoError(t, io.Formatter{}.Unmarshal(data, &result))
+	assert.Equal(t, account, result)
+}
+
+func TestCipherError(t *testing.T) {
+	encrypted, err := io.Formatter{Cipher: xorCipher(0)}.Marshal(cipherAccount{Card: ""})
+	assert.NoError(t, err)
+	var result cipherAccount
+	assert.NoError(t, io.Formatter{Cipher: xorCipher(0)}.Unmarshal(encrypted, &result))
+
+	account := struct {
+		Card string `hprose:"card,encrypt"`
+	}{}
+	err = io.Formatter{Cipher: xorCipher(0)}.Unmarshal([]byte(`m1{s4"card"b""}`), &account)
+	assert.EqualError(t, err, "empty ciphertext")
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | io/decode_handler.go                                     |
 |                                                          |
-| LastModified: Mar 5, 2022                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -92,6 +92,10 @@ func complex128Decode(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
 }
 
 func interfaceDecode(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
+	if t.NumMethod() > 0 {
+		dec.decodeNonEmptyInterface(t, dec.NextByte(), p)
+		return
+	}
 	dec.decodeInterface(dec.NextByte(), (*interface{})(p))
 }
 
@@ -164,6 +168,10 @@ func complex128PtrDecode(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
 }
 
 func interfacePtrDecode(dec *Decoder, t reflect.Type, p unsafe.Pointer) {
+	if t.Elem().NumMethod() > 0 {
+		dec.decodeNonEmptyInterfacePtr(t, dec.NextByte(), p)
+		return
+	}
 	dec.decodeInterfacePtr(dec.NextByte(), (**interface{})(p))
 }
 
@@ -182,6 +190,16 @@ func getOtherDecodeHandler(t reflect.Type) DecodeHandler {
 	}
 }
 
+// GetStructFieldDecodeHandler for the field of struct t with alias, it decrypts
+// the field by the options of the field tag, and returns nil if the field is
+// not found. It is used by the generated ValueDecoder.
+func GetStructFieldDecodeHandler(t reflect.Type, alias string, tag ...string) DecodeHandler {
+	if field, ok := getField(t, alias, tag...); ok {
+		return field.Decode
+	}
+	return nil
+}
+
 // GetDecodeHandler for specified type.
 func GetDecodeHandler(t reflect.Type) DecodeHandler {
 	if getRegisteredValueDecoder(t) == nil {
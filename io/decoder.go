@@ -6,7 +6,7 @@
 |                                                          |
 | io/decoder.go                                            |
 |                                                          |
-| LastModified: Feb 18, 2024                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -19,6 +19,7 @@ import (
 	"math"
 	"math/big"
 	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -93,12 +94,30 @@ type Decoder struct {
 	simple bool
 	refer  decoderRefer
 	ref    []structInfo
+	depth  int
+	alloc  int
 	Error  error
+	// Location is the location of the decoded time without time zone,
+	// nil means time.Local.
+	Location *time.Location
+	// Interner interns the decoded strings if it is not nil, it can be shared
+	// by many decoders if it is safe for concurrent use, like StringTable.
+	Interner StringInterner
+	// Arena makes the decoder allocate the decoded strings and bytes from
+	// shared blocks to reduce allocations.
+	Arena bool
+	arena []byte
+	// Cipher decrypts the fields with the encrypt option if it is not nil.
+	Cipher Cipher
+	// present records the present fields in merge modes.
+	present map[presentKey][]string
 	LongType
 	RealType
 	MapType
 	StructType
 	ListType
+	MergeMode
+	Limits
 }
 
 // NewDecoder creates an Decoder instance from byte array.
@@ -301,6 +320,10 @@ func (dec *Decoder) IsSimple() bool {
 // AddReference adds o to the reference.
 func (dec *Decoder) AddReference(o interface{}) {
 	if !dec.IsSimple() {
+		if dec.MaxReferences > 0 && dec.refer.Last()+1 >= dec.MaxReferences {
+			dec.limitError(ErrTooManyReferences)
+			return
+		}
 		dec.refer.Add(o)
 	}
 }
@@ -315,14 +338,36 @@ func (dec *Decoder) SetReference(i int, o interface{}) {
 // LastReferenceIndex returns the last index of the reference.
 func (dec *Decoder) LastReferenceIndex() int {
 	if !dec.IsSimple() {
-		dec.refer.Last()
+		return dec.refer.Last()
+	}
+	return -1
+}
+
+// addReference adds o to the references and returns its index, it returns -1
+// if o is not added, such as in simple mode or when MaxReferences is reached.
+func (dec *Decoder) addReference(o interface{}) int {
+	last := dec.LastReferenceIndex()
+	dec.AddReference(o)
+	if i := dec.LastReferenceIndex(); i > last {
+		return i
 	}
 	return -1
 }
 
 // ReadReference to p.
 func (dec *Decoder) ReadReference(p interface{}) {
-	o := dec.refer.Read(dec.ReadInt())
+	i := dec.ReadInt()
+	if i < 0 || i > dec.refer.Last() {
+		if dec.Error == nil {
+			dec.Error = DecodeError("hprose/io: invalid reference index " + strconv.Itoa(i))
+		}
+		return
+	}
+	o := dec.refer.Read(i)
+	if v, ok := o.(*Value); ok {
+		dec.decodeDynamicReference(v, p)
+		return
+	}
 	src := reflect.TypeOf(o)
 	dest := reflect.TypeOf(p).Elem()
 	if conv := GetConverter(src, dest); conv != nil {
@@ -340,6 +385,8 @@ func (dec *Decoder) ResetReader(reader io.Reader) *Decoder {
 	dec.reader = reader
 	dec.head = 0
 	dec.tail = 0
+	dec.depth = 0
+	dec.alloc = 0
 	return dec
 }
 
@@ -349,6 +396,8 @@ func (dec *Decoder) ResetBytes(input []byte) *Decoder {
 	dec.buf = input
 	dec.head = 0
 	dec.tail = len(input)
+	dec.depth = 0
+	dec.alloc = 0
 	return dec
 }
 
@@ -361,12 +410,22 @@ func (dec *Decoder) ResetBuffer() *Decoder {
 	}
 	dec.head = 0
 	dec.tail = 0
+	dec.depth = 0
+	dec.alloc = 0
 	dec.Error = nil
 	dec.RealType = RealTypeFloat64
 	dec.LongType = LongTypeInt
 	dec.MapType = MapTypeIIMap
 	dec.StructType = StructTypePtr
 	dec.ListType = ListTypeISlice
+	dec.Limits = Limits{}
+	dec.MergeMode = MergeModeReplace
+	dec.Location = nil
+	dec.Interner = nil
+	dec.Arena = false
+	dec.arena = nil
+	dec.Cipher = nil
+	dec.present = nil
 	return dec
 }
 
@@ -538,6 +597,12 @@ func (dec *Decoder) defaultDecode(t reflect.Type, p interface{}, tag byte) {
 		dec.ReadStruct(t)
 		dec.Decode(p)
 		return
+	case TagObject:
+		dec.decodeUnion(t, p)
+		return
+	case TagTime, TagDate:
+		dec.convertTime(tag, p)
+		return
 	case TagError:
 		var s string
 		dec.decodeString(stringType, dec.NextByte(), &s)
@@ -0,0 +1,117 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/decoder_intern.go                                     |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"sync"
+
+	"github.com/hprose/hprose-golang/v3/internal/convert"
+)
+
+// StringInterner returns a string equal to data, the same string may be
+// returned for the equal data to avoid allocations.
+//
+// The data is only valid during the call, an implementation must copy it
+// before keeping it.
+type StringInterner interface {
+	Intern(data []byte) string
+}
+
+// StringTable is a bounded StringInterner. It is safe for concurrent use, so
+// it can be shared by many decoders.
+type StringTable struct {
+	mu        sync.RWMutex
+	table     map[string]string
+	maxCount  int
+	maxLength int
+}
+
+// NewStringTable returns a StringTable which keeps at most maxCount strings,
+// the strings longer than maxLength bytes are not kept. A zero value of
+// maxLength means no limit.
+func NewStringTable(maxCount int, maxLength int) *StringTable {
+	return &StringTable{
+		table:     make(map[string]string),
+		maxCount:  maxCount,
+		maxLength: maxLength,
+	}
+}
+
+// Intern returns the kept string equal to data, or keeps a copy of data if
+// the table is not full.
+func (t *StringTable) Intern(data []byte) string {
+	if t.maxLength > 0 && len(data) > t.maxLength {
+		return string(data)
+	}
+	t.mu.RLock()
+	s, ok := t.table[string(data)]
+	t.mu.RUnlock()
+	if ok {
+		return s
+	}
+	s = string(data)
+	t.mu.Lock()
+	if len(t.table) < t.maxCount {
+		if kept, ok := t.table[s]; ok {
+			s = kept
+		} else {
+			t.table[s] = s
+		}
+	}
+	t.mu.Unlock()
+	return s
+}
+
+// Len returns the count of the kept strings.
+func (t *StringTable) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.table)
+}
+
+const (
+	arenaBlockSize = 4096
+	arenaMaxSize   = arenaBlockSize / 8
+)
+
+// arenaCopy returns a copy of data. In arena mode, the small copies are
+// allocated from a shared block, the block is never reused, so the copies
+// are as safe as the ones allocated separately, but they keep the whole
+// block alive.
+func (dec *Decoder) arenaCopy(data []byte) []byte {
+	n := len(data)
+	if !dec.Arena || n > arenaMaxSize {
+		result := make([]byte, n)
+		copy(result, data)
+		return result
+	}
+	if n > cap(dec.arena)-len(dec.arena) {
+		dec.arena = make([]byte, 0, arenaBlockSize)
+	}
+	off := len(dec.arena)
+	dec.arena = append(dec.arena, data...)
+	return dec.arena[off : off+n : off+n]
+}
+
+// copyString returns a string copy of data, which is interned if the decoder
+// has an Interner.
+func (dec *Decoder) copyString(data []byte) string {
+	switch {
+	case dec.Interner != nil:
+		return dec.Interner.Intern(data)
+	case dec.Arena:
+		return convert.ToUnsafeString(dec.arenaCopy(data))
+	}
+	return string(data)
+}
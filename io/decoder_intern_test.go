@@ -0,0 +1,150 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/decoder_intern_test.go                                |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"unsafe"
+
+	"github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func stringData(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+func TestStringTable(t *testing.T) {
+	table := io.NewStringTable(2, 5)
+	a := table.Intern([]byte("hello"))
+	b := table.Intern([]byte("hello"))
+	assert.Equal(t, "hello", b)
+	assert.Equal(t, stringData(a), stringData(b))
+	long := table.Intern([]byte("hello world"))
+	assert.Equal(t, "hello world", long)
+	assert.Equal(t, 1, table.Len())
+	table.Intern([]byte("world"))
+	c := table.Intern([]byte("hprose"))
+	d := table.Intern([]byte("hprose"))
+	assert.Equal(t, "hprose", d)
+	assert.Equal(t, 2, table.Len())
+	assert.Equal(t, stringData(c), stringData(c))
+}
+
+func TestDecodeInternedStrings(t *testing.T) {
+	data := []byte(`m2{s4"name"s5"hello"s3"age"i18;}`)
+	table := io.NewStringTable(100, 0)
+	var m1, m2 map[string]interface{}
+	f := io.Formatter{Simple: true, Interner: table}
+	assert.NoError(t, f.Unmarshal(data, &m1))
+	assert.NoError(t, f.Unmarshal(data, &m2))
+	assert.Equal(t, m1, m2)
+	assert.Equal(t, 3, table.Len())
+	for k1 := range m1 {
+		for k2 := range m2 {
+			if k1 == k2 {
+				assert.Equal(t, stringData(k1), stringData(k2))
+			}
+		}
+	}
+	assert.Equal(t, stringData(m1["name"].(string)), stringData(m2["name"].(string)))
+}
+
+func TestDecodeInternedStructFields(t *testing.T) {
+	type Person struct {
+		Name string
+		Age  int
+	}
+	data, err := io.Marshal([]Person{{"Tom", 18}, {"Jerry", 6}})
+	assert.NoError(t, err)
+	table := io.NewStringTable(100, 0)
+	var persons []Person
+	assert.NoError(t, io.Formatter{Simple: true, Interner: table}.Unmarshal(data, &persons))
+	assert.Equal(t, []Person{{"Tom", 18}, {"Jerry", 6}}, persons)
+	assert.Equal(t, 5, table.Len())
+}
+
+func TestDecodeArena(t *testing.T) {
+	data, err := io.Marshal([]interface{}{"hello", []byte("world"), "hello", []byte("world")})
+	assert.NoError(t, err)
+	var result []interface{}
+	assert.NoError(t, io.Formatter{Simple: true, Arena: true}.Unmarshal(data, &result))
+	assert.Equal(t, []interface{}{"hello", []byte("world"), "hello", []byte("world")}, result)
+	b1 := result[1].([]byte)
+	b2 := result[3].([]byte)
+	assert.Equal(t, len(b1), cap(b1))
+	b1 = append(b1, '!')
+	b1[0] = 'W'
+	assert.Equal(t, []byte("world"), b2)
+	assert.Equal(t, "hello", result[0])
+	assert.Equal(t, "hello", result[2])
+}
+
+func TestFreeDecoderResetsInterner(t *testing.T) {
+	dec := io.GetDecoder()
+	dec.Interner = io.NewStringTable(10, 0)
+	dec.Arena = true
+	io.FreeDecoder(dec)
+	dec = io.GetDecoder()
+	assert.Nil(t, dec.Interner)
+	assert.False(t, dec.Arena)
+}
+
+func benchmarkVocabulary() []byte {
+	list := make([]map[string]interface{}, 100)
+	for i := range list {
+		list[i] = map[string]interface{}{
+			"id":     i,
+			"status": "active",
+			"region": "region-" + strconv.Itoa(i%4),
+			"data":   []byte("payload"),
+		}
+	}
+	data, _ := io.Marshal(list)
+	return data
+}
+
+func BenchmarkDecodeStrings(b *testing.B) {
+	data := benchmarkVocabulary()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var v []map[string]interface{}
+		_ = io.Unmarshal(data, &v)
+	}
+}
+
+func BenchmarkDecodeInternedStrings(b *testing.B) {
+	data := benchmarkVocabulary()
+	f := io.Formatter{Simple: true, Interner: io.NewStringTable(1024, 64)}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var v []map[string]interface{}
+		_ = f.Unmarshal(data, &v)
+	}
+}
+
+func BenchmarkDecodeArena(b *testing.B) {
+	data := benchmarkVocabulary()
+	f := io.Formatter{Simple: true, Interner: io.NewStringTable(1024, 64), Arena: true}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var v []map[string]interface{}
+		_ = f.Unmarshal(data, &v)
+	}
+}
@@ -0,0 +1,100 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/decoder_limits.go                                     |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"strconv"
+	"unsafe"
+)
+
+// Limits represents the resource limits of the Decoder.
+// A zero value of any field means no limit.
+type Limits struct {
+	// MaxCollectionLength is the maximum element count of list, map and struct definition.
+	MaxCollectionLength int
+	// MaxStringLength is the maximum length of string and bytes.
+	MaxStringLength int
+	// MaxDepth is the maximum nesting depth of list, map and object.
+	MaxDepth int
+	// MaxReferences is the maximum count of references.
+	MaxReferences int
+	// MaxAllocation is the maximum total size in bytes of decoded strings, bytes and collections.
+	MaxAllocation int
+}
+
+const interfaceSize = int(unsafe.Sizeof((interface{})(nil)))
+
+func (dec *Decoder) limitError(err error) {
+	if dec.Error == nil {
+		dec.Error = err
+	}
+}
+
+func (dec *Decoder) checkLength(n int, max int, err error) bool {
+	if n < 0 {
+		dec.limitError(DecodeError("hprose/io: invalid length " + strconv.Itoa(n)))
+		return false
+	}
+	if max > 0 && n > max {
+		dec.limitError(err)
+		return false
+	}
+	return true
+}
+
+func (dec *Decoder) allocate(n int, size int) bool {
+	if dec.MaxAllocation > 0 {
+		if size > 0 && n > (dec.MaxAllocation-dec.alloc)/size {
+			dec.limitError(ErrAllocationExceeded)
+			return false
+		}
+		dec.alloc += n * size
+	}
+	return true
+}
+
+func (dec *Decoder) readCount(size int) int {
+	count := dec.ReadInt()
+	if !dec.checkLength(count, dec.MaxCollectionLength, ErrCollectionTooLong) || !dec.allocate(count, size) {
+		return 0
+	}
+	return count
+}
+
+func (dec *Decoder) readLength() int {
+	length := dec.ReadInt()
+	if !dec.checkLength(length, dec.MaxStringLength, ErrStringTooLong) || !dec.allocate(length, 1) {
+		return 0
+	}
+	return length
+}
+
+// ReadCount reads the element count of list or map,
+// and checks it against MaxCollectionLength and MaxAllocation.
+func (dec *Decoder) ReadCount() int {
+	return dec.readCount(interfaceSize)
+}
+
+func (dec *Decoder) enter() bool {
+	if dec.MaxDepth > 0 && dec.depth >= dec.MaxDepth {
+		dec.limitError(ErrDepthExceeded)
+		return false
+	}
+	dec.depth++
+	return true
+}
+
+func (dec *Decoder) leave() {
+	dec.depth--
+}
@@ -0,0 +1,149 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/decoder_limits_test.go                                |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"testing"
+
+	"github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoderMaxCollectionLength(t *testing.T) {
+	dec := io.NewDecoder([]byte(`a3{123}`))
+	dec.MaxCollectionLength = 2
+	var v []int
+	dec.Decode(&v)
+	assert.Equal(t, io.ErrCollectionTooLong, dec.Error)
+
+	dec = io.NewDecoder([]byte(`m2{1213}`))
+	dec.MaxCollectionLength = 1
+	var m map[interface{}]interface{}
+	dec.Decode(&m)
+	assert.Equal(t, io.ErrCollectionTooLong, dec.Error)
+
+	dec = io.NewDecoder([]byte(`a2{12}`))
+	dec.MaxCollectionLength = 2
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []int{1, 2}, v)
+}
+
+func TestDecoderMaxStringLength(t *testing.T) {
+	dec := io.NewDecoder([]byte(`s5"hello"`))
+	dec.MaxStringLength = 4
+	var s string
+	dec.Decode(&s)
+	assert.Equal(t, io.ErrStringTooLong, dec.Error)
+
+	dec = io.NewDecoder([]byte(`b5"hello"`))
+	dec.MaxStringLength = 4
+	var b []byte
+	dec.Decode(&b)
+	assert.Equal(t, io.ErrStringTooLong, dec.Error)
+
+	dec = io.NewDecoder([]byte(`s5"hello"`))
+	dec.MaxStringLength = 5
+	dec.Decode(&s)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, "hello", s)
+}
+
+func TestDecoderMaxDepth(t *testing.T) {
+	dec := io.NewDecoder([]byte(`a1{a1{a1{1}}}`))
+	dec.MaxDepth = 2
+	var v interface{}
+	dec.Decode(&v)
+	assert.Equal(t, io.ErrDepthExceeded, dec.Error)
+
+	dec = io.NewDecoder([]byte(`a1{a1{a1{1}}}`))
+	dec.MaxDepth = 3
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []interface{}{[]interface{}{[]interface{}{1}}}, v)
+
+	dec = io.NewDecoder([]byte(`m1{s1"a"m1{s1"b"m1{s1"c"1}}}`))
+	dec.MaxDepth = 2
+	var m map[string]interface{}
+	dec.Decode(&m)
+	assert.Equal(t, io.ErrDepthExceeded, dec.Error)
+}
+
+func TestDecoderMaxReferences(t *testing.T) {
+	dec := io.NewDecoder([]byte(`a3{s1"a"s1"b"s1"c"}`)).Simple(false)
+	dec.MaxReferences = 3
+	var v []string
+	dec.Decode(&v)
+	assert.Equal(t, io.ErrTooManyReferences, dec.Error)
+
+	dec = io.NewDecoder([]byte(`a3{s1"a"s1"b"r1;}`)).Simple(false)
+	dec.MaxReferences = 3
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []string{"a", "b", "a"}, v)
+}
+
+func TestDecoderMaxReferencesKeepReferences(t *testing.T) {
+	dec := io.NewDecoder([]byte(`a2{m1{i1;s1"x"}r0;}`)).Simple(false)
+	dec.MaxReferences = 1
+	dec.MapType = io.MapTypeSIMap
+	var v []interface{}
+	dec.Decode(&v)
+	assert.Equal(t, io.ErrTooManyReferences, dec.Error)
+	assert.Equal(t, map[string]interface{}{"1": "x"}, v[0])
+	assert.IsType(t, (*[]interface{})(nil), v[1])
+}
+
+func TestDecoderMaxAllocation(t *testing.T) {
+	dec := io.NewDecoder([]byte(`a2{s5"hello"s5"world"}`))
+	dec.MaxAllocation = 40
+	var v []string
+	dec.Decode(&v)
+	assert.Equal(t, io.ErrAllocationExceeded, dec.Error)
+
+	dec = io.NewDecoder([]byte(`a2{s5"hello"s5"world"}`))
+	dec.MaxAllocation = 42
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, []string{"hello", "world"}, v)
+}
+
+func TestDecoderInvalidLength(t *testing.T) {
+	dec := io.NewDecoder([]byte(`a-1;{}`))
+	var v []int
+	dec.Decode(&v)
+	assert.EqualError(t, dec.Error, "hprose/io: invalid length -1")
+
+	dec = io.NewDecoder([]byte(`r5;`)).Simple(false)
+	var s string
+	dec.Decode(&s)
+	assert.EqualError(t, dec.Error, "hprose/io: invalid reference index 5")
+
+	dec = io.NewDecoder([]byte(`o0{}`))
+	var m map[string]interface{}
+	dec.Decode(&m)
+	assert.EqualError(t, dec.Error, "hprose/io: invalid struct index 0")
+}
+
+func TestFormatterLimits(t *testing.T) {
+	data, err := io.Marshal([]int{1, 2, 3})
+	assert.NoError(t, err)
+	formatter := io.Formatter{Simple: true}
+	formatter.MaxCollectionLength = 2
+	var v []int
+	assert.Equal(t, io.ErrCollectionTooLong, formatter.Unmarshal(data, &v))
+	formatter.MaxCollectionLength = 3
+	assert.NoError(t, formatter.Unmarshal(data, &v))
+	assert.Equal(t, []int{1, 2, 3}, v)
+}
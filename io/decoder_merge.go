@@ -0,0 +1,81 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/decoder_merge.go                                      |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// MergeMode represents how the decoder decodes into the existing values.
+type MergeMode int8
+
+const (
+	// MergeModeReplace represents the existing maps are replaced by the
+	// decoded maps. It is the default mode.
+	MergeModeReplace MergeMode = iota
+	// MergeModePatch represents the decoded entries are merged into the
+	// existing maps, the existing map values, structs and the values pointed
+	// to by the existing pointers are patched by the decoded values, and the
+	// present fields of the decoded structs are recorded.
+	MergeModePatch
+	// MergeModeAppend is the same as MergeModePatch, except that the decoded
+	// elements are appended to the existing slices.
+	MergeModeAppend
+)
+
+type presentKey struct {
+	p unsafe.Pointer
+	t reflect.Type
+}
+
+func (dec *Decoder) isMerging() bool {
+	return dec.MergeMode != MergeModeReplace
+}
+
+func (dec *Decoder) resetPresentFields(p interface{}) {
+	if dec.present == nil {
+		dec.present = make(map[presentKey][]string)
+	}
+	key := presentKey{reflect2.PtrOf(p), reflect.TypeOf(p)}
+	dec.present[key] = dec.present[key][:0]
+}
+
+func (dec *Decoder) addPresentField(p interface{}, name string) {
+	key := presentKey{reflect2.PtrOf(p), reflect.TypeOf(p)}
+	dec.present[key] = append(dec.present[key], name)
+}
+
+// PresentFields returns the names of the fields present in the last object
+// or map decoded to the struct that p points to. The names are the ones on
+// the wire, the fields unknown by the struct are not included. The present
+// fields are only recorded in MergeModePatch or MergeModeAppend.
+//
+// It returns nil if no object or map has been decoded to p.
+func (dec *Decoder) PresentFields(p interface{}) []string {
+	return dec.present[presentKey{reflect2.PtrOf(p), reflect.TypeOf(p)}]
+}
+
+// IsPresent returns true if the field named name is present in the last
+// object or map decoded to the struct that p points to.
+func (dec *Decoder) IsPresent(p interface{}, name string) bool {
+	for _, field := range dec.PresentFields(p) {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
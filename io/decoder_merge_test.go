@@ -0,0 +1,141 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/decoder_merge_test.go                                 |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"testing"
+
+	"github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+type mergeAddress struct {
+	City   string
+	Street string
+}
+
+type mergeUser struct {
+	Name    string
+	Age     int
+	Tags    []string
+	Attrs   map[string]int
+	Address *mergeAddress
+}
+
+func init() {
+	io.Register(mergeUser{})
+	io.Register(mergeAddress{})
+}
+
+func TestDecodePatchStruct(t *testing.T) {
+	address := &mergeAddress{City: "Beijing", Street: "Main"}
+	user := mergeUser{
+		Name:    "Tom",
+		Age:     18,
+		Tags:    []string{"a"},
+		Attrs:   map[string]int{"x": 1, "y": 2},
+		Address: address,
+	}
+	patch := map[string]interface{}{
+		"age":     19,
+		"attrs":   map[string]int{"y": 3, "z": 4},
+		"address": map[string]string{"street": "Second"},
+	}
+	data, err := io.Marshal(patch)
+	assert.NoError(t, err)
+
+	dec := io.NewDecoder(data)
+	dec.MergeMode = io.MergeModePatch
+	dec.Decode(&user)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, mergeUser{
+		Name:    "Tom",
+		Age:     19,
+		Tags:    []string{"a"},
+		Attrs:   map[string]int{"x": 1, "y": 3, "z": 4},
+		Address: &mergeAddress{City: "Beijing", Street: "Second"},
+	}, user)
+	assert.Same(t, address, user.Address)
+
+	assert.ElementsMatch(t, []string{"age", "attrs", "address"}, dec.PresentFields(&user))
+	assert.True(t, dec.IsPresent(&user, "age"))
+	assert.False(t, dec.IsPresent(&user, "name"))
+	assert.Equal(t, []string{"street"}, dec.PresentFields(user.Address))
+	assert.Nil(t, dec.PresentFields(&mergeUser{}))
+}
+
+func TestDecodePatchObject(t *testing.T) {
+	user := mergeUser{Name: "Tom", Age: 18}
+	data, err := io.Marshal(struct {
+		Age     int    `hprose:"age"`
+		Unknown string `hprose:"unknown"`
+	}{20, "?"})
+	assert.NoError(t, err)
+	assert.NoError(t, io.Formatter{Simple: true, MergeMode: io.MergeModePatch}.Unmarshal(data, &user))
+	assert.Equal(t, mergeUser{Name: "Tom", Age: 20}, user)
+}
+
+func TestDecodeMergeMap(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	m := map[string]point{"a": {1, 2}, "b": {3, 4}}
+	data, err := io.Marshal(map[string]map[string]int{"a": {"y": 5}, "c": {"x": 6}})
+	assert.NoError(t, err)
+	assert.NoError(t, io.Formatter{Simple: true, MergeMode: io.MergeModePatch}.Unmarshal(data, &m))
+	assert.Equal(t, map[string]point{"a": {1, 5}, "b": {3, 4}, "c": {6, 0}}, m)
+
+	m2 := map[string]int{"a": 1}
+	assert.NoError(t, io.Formatter{Simple: true, MergeMode: io.MergeModePatch}.Unmarshal([]byte(`e`), &m2))
+	assert.Equal(t, map[string]int{"a": 1}, m2)
+
+	m3 := map[string]int{"a": 1}
+	data, err = io.Marshal(map[string]int{"b": 2})
+	assert.NoError(t, err)
+	assert.NoError(t, io.Unmarshal(data, &m3))
+	assert.Equal(t, map[string]int{"b": 2}, m3)
+}
+
+func TestDecodeAppendSlice(t *testing.T) {
+	s := []string{"a", "b"}
+	data, err := io.Marshal([]string{"c", "d"})
+	assert.NoError(t, err)
+	assert.NoError(t, io.Formatter{Simple: true, MergeMode: io.MergeModeAppend}.Unmarshal(data, &s))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, s)
+
+	assert.NoError(t, io.Formatter{Simple: true, MergeMode: io.MergeModePatch}.Unmarshal(data, &s))
+	assert.Equal(t, []string{"c", "d"}, s)
+
+	users := make([]mergeUser, 1, 4)
+	users[0].Name = "Tom"
+	stale := users[:2]
+	stale[1].Age = 99
+	data, err = io.Marshal([]map[string]string{{"name": "Jerry"}})
+	assert.NoError(t, err)
+	assert.NoError(t, io.Formatter{Simple: true, MergeMode: io.MergeModeAppend}.Unmarshal(data, &users))
+	assert.Equal(t, []mergeUser{{Name: "Tom"}, {Name: "Jerry"}}, users)
+
+	user := mergeUser{Tags: []string{"a"}}
+	data, err = io.Marshal(map[string][]string{"tags": {"b"}})
+	assert.NoError(t, err)
+	assert.NoError(t, io.Formatter{Simple: true, MergeMode: io.MergeModeAppend}.Unmarshal(data, &user))
+	assert.Equal(t, []string{"a", "b"}, user.Tags)
+}
+
+func TestFreeDecoderResetsMergeMode(t *testing.T) {
+	dec := io.GetDecoder()
+	dec.MergeMode = io.MergeModeAppend
+	io.FreeDecoder(dec)
+	assert.Equal(t, io.MergeModeReplace, io.GetDecoder().MergeMode)
+}
@@ -0,0 +1,76 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/duration_decoder.go                                   |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/modern-go/reflect2"
+)
+
+// time.Duration is encoded as an integer of nanoseconds, which is the default
+// encoding of int64, so only the decoder is special here. It accepts numbers
+// of nanoseconds, and strings like "90" or "1h30m".
+
+func (dec *Decoder) stringToDuration(s string) time.Duration {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(i)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		dec.decodeStringError(s, "time.Duration")
+	}
+	return d
+}
+
+func (dec *Decoder) decodeDuration(t reflect.Type, tag byte, p *time.Duration) {
+	if i := intDigits[tag]; i != invalidDigit {
+		*p = time.Duration(i)
+		return
+	}
+	switch tag {
+	case TagNull, TagEmpty:
+		*p = 0
+	case TagInteger, TagLong:
+		*p = time.Duration(dec.ReadInt64())
+	case TagDouble:
+		*p = time.Duration(dec.ReadFloat64())
+	case TagUTF8Char:
+		*p = dec.stringToDuration(dec.readUnsafeString(1))
+	case TagString:
+		if dec.IsSimple() {
+			*p = dec.stringToDuration(dec.ReadUnsafeString())
+		} else {
+			*p = dec.stringToDuration(dec.ReadString())
+		}
+	default:
+		dec.defaultDecode(t, p, tag)
+	}
+}
+
+// durationDecoder is the implementation of ValueDecoder for time.Duration.
+type durationDecoder struct{}
+
+func (durationDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	dec.decodeDuration(reflect.TypeOf(p).Elem(), tag, (*time.Duration)(reflect2.PtrOf(p)))
+}
+
+func init() {
+	registerValueDecoder(durationType, durationDecoder{})
+	RegisterConverter(stringType, durationType, func(dec *Decoder, o interface{}, p interface{}) {
+		*(*time.Duration)(reflect2.PtrOf(p)) = dec.stringToDuration(*(*string)(reflect2.PtrOf(o)))
+	})
+}
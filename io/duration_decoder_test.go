@@ -0,0 +1,60 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/duration_decoder_test.go                              |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeDuration(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	enc.Encode(90 * time.Minute)
+	enc.Encode(5)
+	enc.Encode(1.5e9)
+	enc.Encode("1h30m")
+	enc.Encode("1h30m")
+	enc.Encode("90")
+	enc.Encode(nil)
+	enc.Encode("")
+	enc.Encode("bad")
+	assert.Equal(t, `l5400000000000;5d1.5e+09;s5"1h30m"r0;s2"90"ne`+`s3"bad"`, sb.String())
+	dec := NewDecoder(([]byte)(sb.String())).Simple(false)
+	var d time.Duration
+	dec.Decode(&d)
+	assert.Equal(t, 90*time.Minute, d)
+	dec.Decode(&d)
+	assert.Equal(t, time.Duration(5), d)
+	dec.Decode(&d)
+	assert.Equal(t, 1500*time.Millisecond, d)
+	dec.Decode(&d)
+	assert.Equal(t, 90*time.Minute, d)
+	var pd *time.Duration
+	dec.Decode(&pd)
+	assert.Equal(t, 90*time.Minute, *pd)
+	dec.Decode(&d)
+	assert.Equal(t, time.Duration(90), d)
+	dec.Decode(&pd)
+	assert.Nil(t, pd)
+	dec.Decode(&d)
+	assert.Equal(t, time.Duration(0), d)
+	assert.NoError(t, dec.Error)
+	dec.Decode(&d)
+	assert.EqualError(t, dec.Error, `hprose/io: can not parse "bad" to time.Duration`)
+}
@@ -0,0 +1,272 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/dynamic_decoder.go                                    |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/modern-go/reflect2"
+)
+
+// ReadValue reads the Value of tag.
+func (dec *Decoder) ReadValue(tag byte) *Value {
+	if i := intDigits[tag]; i != invalidDigit {
+		return &Value{kind: KindInt, tag: tag, text: string(tag)}
+	}
+	switch tag {
+	case TagNull:
+		return &Value{kind: KindNull}
+	case TagEmpty:
+		return &Value{kind: KindEmpty}
+	case TagTrue:
+		return &Value{kind: KindBool, text: "true"}
+	case TagFalse:
+		return &Value{kind: KindBool, text: "false"}
+	case TagInteger:
+		return &Value{kind: KindInt, tag: tag, text: string(dec.UnsafeUntil(TagSemicolon))}
+	case TagLong:
+		return &Value{kind: KindLong, text: string(dec.UnsafeUntil(TagSemicolon))}
+	case TagDouble:
+		return &Value{kind: KindDouble, text: string(dec.UnsafeUntil(TagSemicolon))}
+	case TagNaN:
+		return &Value{kind: KindDouble, text: "NaN"}
+	case TagInfinity:
+		if dec.NextByte() == TagNeg {
+			return &Value{kind: KindDouble, text: "-Inf"}
+		}
+		return &Value{kind: KindDouble, text: "+Inf"}
+	case TagUTF8Char:
+		return &Value{kind: KindChar, text: dec.readSafeString(1)}
+	case TagString:
+		v := &Value{kind: KindString, text: dec.ReadSafeString()}
+		dec.AddReference(v)
+		return v
+	case TagBytes:
+		v := &Value{kind: KindBytes, bytes: dec.readBytes()}
+		dec.AddReference(v)
+		return v
+	case TagGUID:
+		return dec.readDynamicGUID()
+	case TagDate, TagTime:
+		return dec.readDynamicTime(tag)
+	case TagList:
+		return dec.readDynamicList()
+	case TagMap:
+		return dec.readDynamicMap()
+	case TagClass:
+		dec.readDynamicClass()
+		return dec.ReadValue(dec.NextByte())
+	case TagObject:
+		return dec.readDynamicObject()
+	case TagRef:
+		return dec.readDynamicReference()
+	case TagError:
+		var s string
+		dec.decodeString(stringType, dec.NextByte(), &s)
+		dec.Error = DecodeError(s)
+	default:
+		if dec.Error == nil {
+			dec.Error = DecodeError(fmt.Sprintf("hprose/io: invalid tag '%s'(0x%x)", string(tag), tag))
+		}
+	}
+	return &Value{kind: KindNull}
+}
+
+func (dec *Decoder) readDynamicGUID() *Value {
+	v := &Value{kind: KindGUID}
+	u, err := uuid.ParseBytes(dec.UnsafeNext(38))
+	if err != nil && dec.Error == nil {
+		dec.Error = err
+	}
+	v.guid = u
+	dec.AddReference(v)
+	return v
+}
+
+// maxTimeLength is the max length of date or time, such as
+// D20201231T120000.123456789Z.
+const maxTimeLength = 27
+
+// readDynamicTime reads date or time Value and keeps its text, so the Value
+// is re-encoded byte-identically.
+func (dec *Decoder) readDynamicTime(tag byte) *Value {
+	v := &Value{kind: KindDate}
+	if tag == TagTime {
+		v.kind = KindTime
+	}
+	text := append(make([]byte, 0, maxTimeLength), tag)
+	for len(text) < maxTimeLength {
+		b := dec.NextByte()
+		if (b < '0' || b > '9') && b != TagTime && b != TagPoint && b != TagUTC && b != TagSemicolon {
+			if dec.Error == nil {
+				dec.Error = DecodeError("hprose/io: invalid " + v.kind.String() + " " + strconv.Quote(string(append(text, b))))
+			}
+			break
+		}
+		text = append(text, b)
+		if b == TagUTC || b == TagSemicolon {
+			break
+		}
+	}
+	v.text = string(text)
+	td := GetDecoder().ResetBytes(text[1:])
+	td.Location = dec.Location
+	if tag == TagTime {
+		td.readTime(&v.time)
+	} else {
+		td.readDateTime(&v.time)
+	}
+	FreeDecoder(td)
+	dec.AddReference(v)
+	return v
+}
+
+func (dec *Decoder) readDynamicList() *Value {
+	v := &Value{kind: KindList}
+	if !dec.enter() {
+		return v
+	}
+	count := dec.readCount(interfaceSize)
+	v.values = make([]*Value, count)
+	dec.AddReference(v)
+	for i := 0; i < count; i++ {
+		v.values[i] = dec.ReadValue(dec.NextByte())
+	}
+	dec.leave()
+	dec.Skip()
+	return v
+}
+
+func (dec *Decoder) readDynamicMap() *Value {
+	v := &Value{kind: KindMap}
+	if !dec.enter() {
+		return v
+	}
+	count := dec.readCount(interfaceSize * 2)
+	v.keys = make([]*Value, count)
+	v.values = make([]*Value, count)
+	dec.AddReference(v)
+	for i := 0; i < count; i++ {
+		v.keys[i] = dec.ReadValue(dec.NextByte())
+		v.values[i] = dec.ReadValue(dec.NextByte())
+	}
+	dec.leave()
+	dec.Skip()
+	return v
+}
+
+// readDynamicClass reads the class definition with the field names as Values,
+// so that the references to the field names are kept.
+func (dec *Decoder) readDynamicClass() {
+	name := dec.ReadSafeString()
+	count := dec.readCount(interfaceSize)
+	keys := make([]*Value, count)
+	names := make([]string, count)
+	for i := 0; i < count; i++ {
+		keys[i] = dec.ReadValue(dec.NextByte())
+		names[i] = keys[i].Text()
+	}
+	dec.Skip()
+	info := makeStructInfo(name, names, interfaceType)
+	info.keys = keys
+	dec.ref = append(dec.ref, info)
+}
+
+func (dec *Decoder) readDynamicObject() *Value {
+	v := &Value{kind: KindObject}
+	if !dec.enter() {
+		return v
+	}
+	index := dec.ReadInt()
+	info := dec.getStructInfo(index)
+	v.class = info.name
+	v.keys = info.keys
+	if v.keys == nil && info.names != nil {
+		// the class definition has been read by other decoders.
+		v.keys = make([]*Value, len(info.names))
+		for i, name := range info.names {
+			v.keys[i] = &Value{kind: KindString, text: name}
+		}
+		dec.ref[index].keys = v.keys
+	}
+	v.values = make([]*Value, len(v.keys))
+	dec.AddReference(v)
+	for i := range v.values {
+		v.values[i] = dec.ReadValue(dec.NextByte())
+	}
+	dec.leave()
+	dec.Skip()
+	return v
+}
+
+func (dec *Decoder) readDynamicReference() *Value {
+	i := dec.ReadInt()
+	if i < 0 || i > dec.refer.Last() {
+		if dec.Error == nil {
+			dec.Error = DecodeError("hprose/io: invalid reference index " + strconv.Itoa(i))
+		}
+		return &Value{kind: KindNull}
+	}
+	switch o := dec.refer.Read(i).(type) {
+	case *Value:
+		return o
+	default:
+		// the value has been read by other decoders.
+		v, err := ValueOf(o)
+		if err != nil {
+			if dec.Error == nil {
+				dec.Error = err
+			}
+			return &Value{kind: KindNull}
+		}
+		return v
+	}
+}
+
+// decodeDynamicReference decodes the Value read by ReadValue to p.
+func (dec *Decoder) decodeDynamicReference(v *Value, p interface{}) {
+	switch p := p.(type) {
+	case **Value:
+		*p = v
+	case *Value:
+		*p = *v
+	case *interface{}:
+		*p = v
+	default:
+		if err := v.Decode(p); err != nil && dec.Error == nil {
+			dec.Error = err
+		}
+	}
+}
+
+// dynamicDecoder is the implementation of ValueDecoder for Value.
+type dynamicDecoder struct{}
+
+func (dynamicDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	*(*Value)(reflect2.PtrOf(p)) = *dec.ReadValue(tag)
+}
+
+// dynamicPtrDecoder is the implementation of ValueDecoder for *Value.
+type dynamicPtrDecoder struct{}
+
+func (dynamicPtrDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	*(**Value)(reflect2.PtrOf(p)) = dec.ReadValue(tag)
+}
+
+func init() {
+	registerValueDecoder(dynamicType, dynamicDecoder{})
+	registerValueDecoder(dynamicPtrType, dynamicPtrDecoder{})
+}
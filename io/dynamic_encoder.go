@@ -0,0 +1,186 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/dynamic_encoder.go                                    |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// valueClass is the key of the class definitions written for object Values.
+type valueClass string
+
+func makeValueClass(v *Value) valueClass {
+	var sb strings.Builder
+	sb.WriteString(v.class)
+	for _, key := range v.keys {
+		sb.WriteByte(0)
+		sb.WriteByte(byte(key.Kind()))
+		sb.WriteString(key.Text())
+	}
+	return valueClass(sb.String())
+}
+
+// dynamicEncoder is the implementation of ValueEncoder for Value/*Value.
+type dynamicEncoder struct{}
+
+func (valenc dynamicEncoder) Encode(enc *Encoder, v interface{}) {
+	valenc.Write(enc, v)
+}
+
+func (dynamicEncoder) Write(enc *Encoder, v interface{}) {
+	if value, ok := v.(Value); ok {
+		enc.writeDynamic(&value)
+	} else {
+		enc.writeDynamic(v.(*Value))
+	}
+}
+
+// writeDynamic writes v, the referenceable values are written as references
+// if the same *Value has been written.
+func (enc *Encoder) writeDynamic(v *Value) {
+	switch v.Kind() {
+	case KindNull:
+		enc.buf = append(enc.buf, TagNull)
+	case KindEmpty:
+		enc.buf = append(enc.buf, TagEmpty)
+	case KindBool:
+		if v.text == "true" {
+			enc.buf = append(enc.buf, TagTrue)
+		} else {
+			enc.buf = append(enc.buf, TagFalse)
+		}
+	case KindInt:
+		if v.tag == TagInteger {
+			enc.writeNumber(TagInteger, v.text)
+		} else {
+			enc.buf = append(enc.buf, v.text...)
+		}
+	case KindLong:
+		enc.writeNumber(TagLong, v.text)
+	case KindDouble:
+		switch v.text {
+		case "NaN":
+			enc.buf = append(enc.buf, TagNaN)
+		case "+Inf":
+			enc.buf = append(enc.buf, TagInfinity, TagPos)
+		case "-Inf":
+			enc.buf = append(enc.buf, TagInfinity, TagNeg)
+		default:
+			enc.writeNumber(TagDouble, v.text)
+		}
+	case KindChar:
+		enc.buf = append(enc.buf, TagUTF8Char)
+		enc.buf = append(enc.buf, v.text...)
+	default:
+		if !enc.WriteReference(v) {
+			enc.writeDynamicReferenceable(v)
+		}
+	}
+}
+
+func (enc *Encoder) writeNumber(tag byte, text string) {
+	enc.buf = append(enc.buf, tag)
+	enc.buf = append(enc.buf, text...)
+	enc.buf = append(enc.buf, TagSemicolon)
+}
+
+func (enc *Encoder) writeDynamicReferenceable(v *Value) {
+	switch v.kind {
+	case KindList, KindMap, KindObject:
+		if !enc.enter(unsafe.Pointer(v), dynamicPtrType) {
+			enc.WriteNil()
+			return
+		}
+		defer enc.leave(unsafe.Pointer(v), dynamicPtrType)
+	}
+	switch v.kind {
+	case KindString:
+		enc.setReference(v)
+		enc.buf = appendString(enc.buf, v.text, utf16Length(v.text))
+	case KindBytes:
+		enc.setReference(v)
+		enc.buf = append(enc.buf, TagBytes)
+		enc.buf = appendBinary(enc.buf, v.bytes, len(v.bytes))
+	case KindGUID:
+		enc.setReference(v)
+		enc.buf = append(enc.buf, TagGUID, TagOpenbrace)
+		enc.buf = append(enc.buf, v.guid.String()...)
+		enc.buf = append(enc.buf, TagClosebrace)
+	case KindDate, KindTime:
+		enc.setReference(v)
+		if v.text != "" {
+			enc.buf = append(enc.buf, v.text...)
+		} else {
+			enc.writeDynamicTime(v)
+		}
+	case KindList:
+		enc.setReference(v)
+		enc.WriteListHead(len(v.values))
+		for _, item := range v.values {
+			enc.writeDynamic(item)
+		}
+		enc.WriteFoot()
+	case KindMap:
+		enc.setReference(v)
+		enc.WriteMapHead(len(v.values))
+		for i, key := range v.keys {
+			enc.writeDynamic(key)
+			enc.writeDynamic(v.values[i])
+		}
+		enc.WriteFoot()
+	case KindObject:
+		r := enc.writeClass(makeValueClass(v), func() {
+			enc.buf = append(enc.buf, TagClass)
+			enc.buf = appendName(enc.buf, v.class, "class name")
+			if n := len(v.keys); n > 0 {
+				enc.buf = AppendUint64(enc.buf, uint64(n))
+			}
+			enc.buf = append(enc.buf, TagOpenbrace)
+			for _, key := range v.keys {
+				enc.writeDynamic(key)
+			}
+			enc.buf = append(enc.buf, TagClosebrace)
+		})
+		enc.setReference(v)
+		enc.WriteObjectHead(r)
+		for _, value := range v.values {
+			enc.writeDynamic(value)
+		}
+		enc.WriteFoot()
+	}
+}
+
+func (enc *Encoder) writeDynamicTime(v *Value) {
+	t := v.time
+	hour, min, sec := t.Clock()
+	nsec := t.Nanosecond()
+	if v.kind == KindDate {
+		year, month, day := t.Date()
+		enc.writeDatePart(year, int(month), day)
+	}
+	if v.kind == KindTime || hour != 0 || min != 0 || sec != 0 || nsec != 0 {
+		enc.writeTimePart(hour, min, sec, nsec)
+	}
+	loc := TagSemicolon
+	if t.Location() == time.UTC {
+		loc = TagUTC
+	}
+	enc.buf = append(enc.buf, loc)
+}
+
+func init() {
+	RegisterValueEncoder((*Value)(nil), dynamicEncoder{})
+}
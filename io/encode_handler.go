@@ -6,7 +6,7 @@
 |                                                          |
 | io/encode_handler.go                                     |
 |                                                          |
-| LastModified: Mar 5, 2022                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,6 +15,7 @@ package io
 
 import (
 	"reflect"
+	"unsafe"
 
 	"github.com/modern-go/reflect2"
 )
@@ -22,6 +23,31 @@ import (
 // EncodeHandler is an encode handler.
 type EncodeHandler func(enc *Encoder, v interface{})
 
+// FieldEncodeHandler is an encode handler for the struct field that p points to.
+type FieldEncodeHandler func(enc *Encoder, p unsafe.Pointer)
+
+// GetFieldEncodeHandler for specified field type, it is used by the generated ValueEncoder.
+func GetFieldEncodeHandler(t reflect.Type) FieldEncodeHandler {
+	handler := GetEncodeHandler(t)
+	t2 := reflect2.Type2(t)
+	return func(enc *Encoder, p unsafe.Pointer) {
+		handler(enc, t2.UnsafeIndirect(p))
+	}
+}
+
+// GetStructFieldEncodeHandler for the field of struct t with alias, it encrypts
+// or redacts the field by the options of the field tag, and returns nil if
+// the field is not found. It is used by the generated ValueEncoder.
+func GetStructFieldEncodeHandler(t reflect.Type, alias string, tag ...string) FieldEncodeHandler {
+	field, ok := getField(t, alias, tag...)
+	if !ok {
+		return nil
+	}
+	return func(enc *Encoder, p unsafe.Pointer) {
+		field.Encode(enc, field.Type.UnsafeIndirect(p))
+	}
+}
+
 // GetEncodeHandler for specified type.
 func GetEncodeHandler(t reflect.Type) (handler EncodeHandler) {
 	if handler = getOtherEncodeHandler(t); handler == nil {
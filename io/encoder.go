@@ -6,7 +6,7 @@
 |                                                          |
 | io/encoder.go                                            |
 |                                                          |
-| LastModified: Feb 7, 2024                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -28,11 +28,21 @@ type Encoder struct {
 	buf    []byte
 	off    int
 	simple bool
+	// canonical mode writes the same value to the same bytes.
+	canonical bool
+	// typedUnion mode writes the values of the union types with their names.
+	typedUnion bool
+	// redact mode writes the fields with the redact option as Redacted.
+	redact bool
 	refer  encoderRefer
-	ref    map[reflect.Type]int
+	cycle  encoderCycle
+	ref    map[interface{}]int
 	last   int
 	Writer io.Writer
 	Error  error
+	// Cipher encrypts the fields with the encrypt option if it is not nil.
+	Cipher Cipher
+	TimeZoneMode
 }
 
 // NewEncoder create an encoder object.
@@ -120,6 +130,17 @@ func (enc *Encoder) writeValue(v interface{}, encode func(m ValueEncoder, v inte
 			return
 		}
 	}
+	if enc.typedUnion {
+		if info := getUnionInfo(t); info != nil {
+			enc.writeUnion(info, v, encode)
+			return
+		}
+	}
+	enc.writeOtherValue(t, v, encode)
+}
+
+func (enc *Encoder) writeOtherValue(t reflect.Type, v interface{}, encode func(m ValueEncoder, v interface{})) {
+	kind := t.Kind()
 	if valenc := getOtherEncoder(t); valenc != nil {
 		encode(valenc, v)
 		return
@@ -285,16 +306,22 @@ func (enc *Encoder) AddReferenceCount(n int) {
 
 // WriteStructType of t to stream with action.
 func (enc *Encoder) WriteStructType(t reflect.Type, action func()) (r int) {
+	return enc.writeClass(t, action)
+}
+
+// writeClass writes the class definition of key with action if it has not
+// been written, and returns the class index.
+func (enc *Encoder) writeClass(key interface{}, action func()) (r int) {
 	if enc.ref == nil {
-		enc.ref = make(map[reflect.Type]int)
+		enc.ref = make(map[interface{}]int)
 	}
-	if r, ok := enc.ref[t]; ok {
+	if r, ok := enc.ref[key]; ok {
 		return r
 	}
 	action()
 	r = enc.last
 	enc.last++
-	enc.ref[t] = r
+	enc.ref[key] = r
 	return
 }
 
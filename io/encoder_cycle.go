@@ -0,0 +1,96 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/encoder_cycle.go                                      |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// startDetectingCyclesAfter is the nesting level of containers after which
+// the encoder starts to detect circular references. The references stop the
+// cycles unless the encoder is in simple mode, so the detection is deferred
+// to keep the common case fast.
+const startDetectingCyclesAfter = 1000
+
+// A CircularReferenceError is returned by Encoder when the value can not be
+// written because it contains itself. It happens in simple mode, or when a
+// map or slice which is not addressable contains itself.
+type CircularReferenceError struct {
+	// Path is the types of the containers from the value to itself.
+	Path []reflect.Type
+}
+
+func (e CircularReferenceError) Error() string {
+	path := make([]string, len(e.Path))
+	for i, t := range e.Path {
+		path[i] = t.String()
+	}
+	return "hprose/io: circular reference: " + strings.Join(path, " -> ")
+}
+
+// IsCircularReference returns true if err is a CircularReferenceError.
+func IsCircularReference(err error) bool {
+	var e CircularReferenceError
+	return errors.As(err, &e)
+}
+
+type cycleKey struct {
+	p unsafe.Pointer
+	t reflect.Type
+}
+
+type encoderCycle struct {
+	level int
+	seen  map[cycleKey]int
+	path  []reflect.Type
+}
+
+// enter is called before writing the container of type t at p. It returns
+// false and sets CircularReferenceError if the container is being written,
+// then the container should be written as nil and leave should not be called.
+func (enc *Encoder) enter(p unsafe.Pointer, t reflect.Type) bool {
+	c := &enc.cycle
+	c.level++
+	if c.level <= startDetectingCyclesAfter {
+		return true
+	}
+	key := cycleKey{p, t}
+	if i, ok := c.seen[key]; ok {
+		c.level--
+		if enc.Error == nil {
+			path := append([]reflect.Type{}, c.path[i:]...)
+			enc.Error = CircularReferenceError{append(path, t)}
+		}
+		return false
+	}
+	if c.seen == nil {
+		c.seen = make(map[cycleKey]int)
+	}
+	c.seen[key] = len(c.path)
+	c.path = append(c.path, t)
+	return true
+}
+
+// leave is called after writing the container of type t at p.
+func (enc *Encoder) leave(p unsafe.Pointer, t reflect.Type) {
+	c := &enc.cycle
+	if c.level > startDetectingCyclesAfter {
+		delete(c.seen, cycleKey{p, t})
+		c.path = c.path[:len(c.path)-1]
+	}
+	c.level--
+}
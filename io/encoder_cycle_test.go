@@ -0,0 +1,82 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/encoder_cycle_test.go                                 |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"container/list"
+	"testing"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+type cycleNode struct {
+	Name     string
+	Next     *cycleNode
+	Children []interface{}
+}
+
+func TestSimpleCircularStruct(t *testing.T) {
+	node := &cycleNode{Name: "a"}
+	node.Next = &cycleNode{Name: "b", Next: node}
+	_, err := Marshal(node)
+	assert.EqualError(t, err, "hprose/io: circular reference: *io_test.cycleNode -> *io_test.cycleNode -> *io_test.cycleNode")
+	assert.True(t, IsCircularReference(err))
+	data, err := Formatter{}.Marshal(node)
+	assert.NoError(t, err)
+	assert.Equal(t, `c9"cycleNode"3{s4"name"s4"next"s8"children"}o0{uao0{ubr3;n}n}`, string(data))
+	data, err = Formatter{Simple: true, SimpleFallback: true}.Marshal(node)
+	assert.NoError(t, err)
+	assert.Equal(t, `c9"cycleNode"3{s4"name"s4"next"s8"children"}o0{uao0{ubr3;n}n}`, string(data))
+}
+
+func TestSimpleCircularSlice(t *testing.T) {
+	node := &cycleNode{Name: "a"}
+	node.Children = []interface{}{node}
+	_, err := Marshal(node)
+	assert.EqualError(t, err, "hprose/io: circular reference: *io_test.cycleNode -> []interface {} -> *io_test.cycleNode")
+}
+
+func TestCircularMap(t *testing.T) {
+	m := map[string]interface{}{}
+	m["self"] = m
+	_, err := Marshal(m)
+	assert.EqualError(t, err, "hprose/io: circular reference: map[string]interface {} -> map[string]interface {}")
+	_, err = Formatter{}.Marshal(m)
+	assert.True(t, IsCircularReference(err))
+}
+
+func TestSimpleCircularList(t *testing.T) {
+	l := list.New()
+	l.PushBack(l)
+	_, err := Marshal(l)
+	assert.EqualError(t, err, "hprose/io: circular reference: *list.List -> *list.List")
+}
+
+func TestSimpleCircularValue(t *testing.T) {
+	v := NewList()
+	v.Append(v)
+	_, err := Marshal(v)
+	assert.EqualError(t, err, "hprose/io: circular reference: *io.Value -> *io.Value")
+	data, err := Formatter{}.Marshal(v)
+	assert.NoError(t, err)
+	assert.Equal(t, `a1{r0;}`, string(data))
+}
+
+func TestSimpleSharedValue(t *testing.T) {
+	node := &cycleNode{Name: "a"}
+	data, err := Marshal([]*cycleNode{node, node})
+	assert.NoError(t, err)
+	assert.Equal(t, `a2{c9"cycleNode"3{s4"name"s4"next"s8"children"}o0{uann}o0{uann}}`, string(data))
+}
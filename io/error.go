@@ -6,7 +6,7 @@
 |                                                          |
 | io/error.go                                              |
 |                                                          |
-| LastModified: Apr 25, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -53,3 +53,12 @@ type DecodeError string
 func (e DecodeError) Error() string {
 	return string(e)
 }
+
+// Errors returned by Decoder when the data exceeds the Limits.
+var (
+	ErrCollectionTooLong  = errors.New("hprose/io: collection length exceeds the limit")
+	ErrStringTooLong      = errors.New("hprose/io: string or bytes length exceeds the limit")
+	ErrDepthExceeded      = errors.New("hprose/io: nesting depth exceeds the limit")
+	ErrTooManyReferences  = errors.New("hprose/io: reference count exceeds the limit")
+	ErrAllocationExceeded = errors.New("hprose/io: allocation exceeds the limit")
+)
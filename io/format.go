@@ -0,0 +1,526 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/format.go                                             |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Printer formats hprose data as indented annotated text for debugging.
+type Printer struct {
+	// Indent is the string written for each level of indentation.
+	Indent string
+	// ResolveReferences renders the referenced values inline instead of
+	// only their reference indexes. Each referenced value is rendered inline
+	// once, the later references to it are rendered as their indexes.
+	ResolveReferences bool
+	// Limits of the parser, only MaxDepth is used.
+	Limits
+}
+
+const defaultFormatDepth = 1000
+
+var defaultPrinter = Printer{Indent: "  ", Limits: Limits{MaxDepth: defaultFormatDepth}}
+
+// Format returns the indented annotated text of hprose data, such as
+//
+//	call
+//	  string "sum" #0
+//	  list(2) #0 [
+//	    int 1
+//	    int 2
+//	  ]
+//	end
+//
+// for `Cs3"sum"a2{12}z`. The referenceable values are annotated with their
+// reference indexes (#n) and the class definitions with their class
+// indexes (@n). The nesting depth of data is limited to 1000.
+func Format(data []byte) (string, error) {
+	return defaultPrinter.Format(data)
+}
+
+// Format returns the indented annotated text of hprose data. If data is
+// malformed, it returns the text formatted so far with the error.
+func (p Printer) Format(data []byte) (string, error) {
+	parser := dumpParser{data: data, maxDepth: p.MaxDepth}
+	err := parser.parse()
+	w := dumpWriter{
+		Printer:  p,
+		active:   make(map[*dumpNode]bool),
+		resolved: make(map[*dumpNode]bool),
+	}
+	for _, node := range parser.nodes {
+		w.write(node, 0, true)
+		w.sb.WriteByte('\n')
+	}
+	return w.sb.String(), err
+}
+
+type dumpNode struct {
+	head   string
+	str    string
+	index  int
+	target int
+	ref    *dumpNode
+	open   string
+	close  string
+	class  *dumpNode
+	items  []dumpItem
+}
+
+type dumpItem struct {
+	name  string
+	key   *dumpNode
+	value *dumpNode
+}
+
+func newDumpNode(head string) *dumpNode {
+	return &dumpNode{head: head, index: -1, target: -1}
+}
+
+type dumpClass struct {
+	name   string
+	fields []string
+}
+
+type dumpParser struct {
+	data     []byte
+	pos      int
+	nodes    []*dumpNode
+	refs     []*dumpNode
+	classes  []dumpClass
+	depth    int
+	maxDepth int
+}
+
+func (p *dumpParser) parse() (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			if e == ErrDepthExceeded {
+				err = ErrDepthExceeded
+				return
+			}
+			de, ok := e.(DecodeError)
+			if !ok {
+				panic(e)
+			}
+			err = de
+		}
+	}()
+	for p.pos < len(p.data) {
+		p.nodes = append(p.nodes, nil)
+		p.parseTop(&p.nodes[len(p.nodes)-1])
+	}
+	return
+}
+
+func (p *dumpParser) fail(msg string) {
+	panic(DecodeError("hprose/io: " + msg + " at offset " + strconv.Itoa(p.pos)))
+}
+
+func (p *dumpParser) enter() {
+	if p.maxDepth > 0 && p.depth >= p.maxDepth {
+		panic(ErrDepthExceeded)
+	}
+	p.depth++
+}
+
+func (p *dumpParser) leave() {
+	p.depth--
+}
+
+func (p *dumpParser) next() byte {
+	if p.pos >= len(p.data) {
+		p.fail("unexpected end of data")
+	}
+	b := p.data[p.pos]
+	p.pos++
+	return b
+}
+
+func (p *dumpParser) peek() byte {
+	if p.pos >= len(p.data) {
+		return 0
+	}
+	return p.data[p.pos]
+}
+
+func (p *dumpParser) expect(b byte) {
+	if p.next() != b {
+		p.pos--
+		p.fail("expected '" + string(b) + "'")
+	}
+}
+
+func (p *dumpParser) take(n int) []byte {
+	if n > len(p.data)-p.pos {
+		p.fail("unexpected end of data")
+	}
+	data := p.data[p.pos : p.pos+n]
+	p.pos += n
+	return data
+}
+
+func (p *dumpParser) until(delim byte) string {
+	i := bytes.IndexByte(p.data[p.pos:], delim)
+	if i < 0 {
+		p.pos = len(p.data)
+		p.fail("unexpected end of data")
+	}
+	s := string(p.data[p.pos : p.pos+i])
+	p.pos += i + 1
+	return s
+}
+
+func (p *dumpParser) readInt(delim byte) int {
+	s := p.until(delim)
+	if s == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		p.fail("invalid number " + strconv.Quote(s))
+	}
+	return n
+}
+
+func (p *dumpParser) readUTF16(n int) string {
+	start := p.pos
+	for ; n > 0; n-- {
+		r, size := utf8.DecodeRune(p.data[p.pos:])
+		if size == 0 {
+			p.fail("unexpected end of data")
+		}
+		if r == utf8.RuneError && size == 1 {
+			p.fail("invalid UTF-8")
+		}
+		if r >= 0x10000 {
+			n--
+		}
+		p.pos += size
+	}
+	return string(p.data[start:p.pos])
+}
+
+func (p *dumpParser) readString() string {
+	s := p.readUTF16(p.readInt(TagQuote))
+	p.expect(TagQuote)
+	return s
+}
+
+func (p *dumpParser) readTime() string {
+	data := p.take(6)
+	s := string(data[0:2]) + ":" + string(data[2:4]) + ":" + string(data[4:6])
+	if p.peek() == TagPoint {
+		p.pos++
+		s += "."
+		for i := 0; i < 3 && p.peek() >= '0' && p.peek() <= '9'; i++ {
+			s += string(p.take(3))
+		}
+	}
+	return s
+}
+
+func (p *dumpParser) readZone() string {
+	switch p.next() {
+	case TagUTC:
+		return " UTC"
+	case TagSemicolon:
+		return ""
+	}
+	p.pos--
+	p.fail("invalid time zone")
+	return ""
+}
+
+func (p *dumpParser) reset() {
+	p.refs = nil
+	p.classes = nil
+}
+
+func (p *dumpParser) addReference(node *dumpNode) *dumpNode {
+	node.index = len(p.refs)
+	p.refs = append(p.refs, node)
+	return node
+}
+
+func (p *dumpParser) addItem(node *dumpNode) *dumpItem {
+	node.items = append(node.items, dumpItem{})
+	return &node.items[len(node.items)-1]
+}
+
+func (p *dumpParser) parseTop(dest **dumpNode) {
+	tag := p.next()
+	switch tag {
+	case TagHeader:
+		node := newDumpNode("header")
+		*dest = node
+		p.parseValue(p.next(), &p.addItem(node).value)
+		p.reset()
+	case TagCall:
+		node := newDumpNode("call")
+		*dest = node
+		p.parseValue(p.next(), &p.addItem(node).value)
+		p.reset()
+		if p.peek() == TagList {
+			p.parseValue(p.next(), &p.addItem(node).value)
+		}
+	case TagResult:
+		node := newDumpNode("result")
+		*dest = node
+		p.parseValue(p.next(), &p.addItem(node).value)
+	case TagEnd:
+		*dest = newDumpNode("end")
+	default:
+		p.parseValue(tag, dest)
+	}
+}
+
+func (p *dumpParser) parseValue(tag byte, dest **dumpNode) {
+	switch tag {
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		*dest = newDumpNode("int " + string(tag))
+	case TagInteger:
+		*dest = newDumpNode("int " + p.until(TagSemicolon))
+	case TagLong:
+		*dest = newDumpNode("long " + p.until(TagSemicolon))
+	case TagDouble:
+		*dest = newDumpNode("double " + p.until(TagSemicolon))
+	case TagNaN:
+		*dest = newDumpNode("double NaN")
+	case TagInfinity:
+		if p.next() == TagNeg {
+			*dest = newDumpNode("double -Inf")
+		} else {
+			*dest = newDumpNode("double +Inf")
+		}
+	case TagNull:
+		*dest = newDumpNode("null")
+	case TagEmpty:
+		*dest = newDumpNode("empty")
+	case TagTrue:
+		*dest = newDumpNode("true")
+	case TagFalse:
+		*dest = newDumpNode("false")
+	case TagUTF8Char:
+		s := p.readUTF16(1)
+		node := newDumpNode("char " + strconv.Quote(s))
+		node.str = s
+		*dest = node
+	case TagString:
+		s := p.readString()
+		node := newDumpNode("string " + strconv.Quote(s))
+		node.str = s
+		*dest = p.addReference(node)
+	case TagBytes:
+		n := p.readInt(TagQuote)
+		data := p.take(n)
+		p.expect(TagQuote)
+		*dest = p.addReference(newDumpNode("bytes(" + strconv.Itoa(n) + ") " + strconv.Quote(string(data))))
+	case TagGUID:
+		p.expect(TagOpenbrace)
+		id := string(p.take(36))
+		p.expect(TagClosebrace)
+		*dest = p.addReference(newDumpNode("guid " + id))
+	case TagDate:
+		data := p.take(8)
+		s := string(data[0:4]) + "-" + string(data[4:6]) + "-" + string(data[6:8])
+		if p.peek() == TagTime {
+			p.pos++
+			s += " " + p.readTime()
+		}
+		*dest = p.addReference(newDumpNode("date " + s + p.readZone()))
+	case TagTime:
+		s := p.readTime()
+		*dest = p.addReference(newDumpNode("time " + s + p.readZone()))
+	case TagList:
+		p.parseList(dest)
+	case TagMap:
+		p.parseMap(dest)
+	case TagClass:
+		class := p.parseClass()
+		p.enter()
+		p.parseValue(p.next(), dest)
+		p.leave()
+		if *dest != nil {
+			(*dest).class = class
+		}
+	case TagObject:
+		p.parseObject(dest)
+	case TagRef:
+		index := p.readInt(TagSemicolon)
+		node := newDumpNode("ref #" + strconv.Itoa(index))
+		node.target = index
+		if index < len(p.refs) {
+			node.ref = p.refs[index]
+			node.str = node.ref.str
+		}
+		*dest = node
+	case TagError:
+		node := newDumpNode("error")
+		*dest = node
+		p.enter()
+		p.parseValue(p.next(), &p.addItem(node).value)
+		p.leave()
+	default:
+		p.pos--
+		p.fail("unexpected tag " + strconv.QuoteRune(rune(tag)))
+	}
+}
+
+func (p *dumpParser) parseList(dest **dumpNode) {
+	p.enter()
+	defer p.leave()
+	count := p.readInt(TagOpenbrace)
+	node := p.addReference(newDumpNode("list(" + strconv.Itoa(count) + ")"))
+	node.open, node.close = "[", "]"
+	*dest = node
+	for i := 0; i < count; i++ {
+		p.parseValue(p.next(), &p.addItem(node).value)
+	}
+	p.expect(TagClosebrace)
+}
+
+func (p *dumpParser) parseMap(dest **dumpNode) {
+	p.enter()
+	defer p.leave()
+	count := p.readInt(TagOpenbrace)
+	node := p.addReference(newDumpNode("map(" + strconv.Itoa(count) + ")"))
+	node.open, node.close = "{", "}"
+	*dest = node
+	for i := 0; i < count; i++ {
+		item := p.addItem(node)
+		p.parseValue(p.next(), &item.key)
+		p.parseValue(p.next(), &item.value)
+	}
+	p.expect(TagClosebrace)
+}
+
+func (p *dumpParser) parseClass() *dumpNode {
+	name := p.readString()
+	count := p.readInt(TagOpenbrace)
+	node := newDumpNode("class @" + strconv.Itoa(len(p.classes)) + " " + strconv.Quote(name))
+	node.open, node.close = "{", "}"
+	fields := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		item := p.addItem(node)
+		p.parseValue(p.next(), &item.value)
+		fields = append(fields, item.value.str)
+	}
+	p.expect(TagClosebrace)
+	p.classes = append(p.classes, dumpClass{name, fields})
+	return node
+}
+
+func (p *dumpParser) parseObject(dest **dumpNode) {
+	p.enter()
+	defer p.leave()
+	index := p.readInt(TagOpenbrace)
+	if index >= len(p.classes) {
+		p.fail("invalid class index " + strconv.Itoa(index))
+	}
+	class := p.classes[index]
+	node := p.addReference(newDumpNode("object @" + strconv.Itoa(index) + " " + strconv.Quote(class.name)))
+	node.open, node.close = "{", "}"
+	*dest = node
+	for _, field := range class.fields {
+		item := p.addItem(node)
+		item.name = field
+		p.parseValue(p.next(), &item.value)
+	}
+	p.expect(TagClosebrace)
+}
+
+type dumpWriter struct {
+	Printer
+	sb        strings.Builder
+	active    map[*dumpNode]bool
+	resolved  map[*dumpNode]bool
+	resolving int
+}
+
+func (w *dumpWriter) newline(level int) {
+	w.sb.WriteByte('\n')
+	for i := 0; i < level; i++ {
+		w.sb.WriteString(w.Indent)
+	}
+}
+
+func (w *dumpWriter) write(node *dumpNode, level int, class bool) {
+	if node == nil {
+		return
+	}
+	if class && node.class != nil {
+		w.write(node.class, level, false)
+		w.newline(level)
+	}
+	if node.target >= 0 && w.ResolveReferences {
+		w.writeReference(node, level)
+		return
+	}
+	w.sb.WriteString(node.head)
+	if node.index >= 0 && w.resolving > 0 {
+		w.resolved[node] = true
+	}
+	if node.index >= 0 {
+		w.sb.WriteString(" #")
+		w.sb.WriteString(strconv.Itoa(node.index))
+	}
+	if node.open != "" {
+		w.sb.WriteByte(' ')
+		w.sb.WriteString(node.open)
+	}
+	w.active[node] = true
+	for _, item := range node.items {
+		if item.key == nil && item.value == nil {
+			continue
+		}
+		w.newline(level + 1)
+		if item.name != "" {
+			w.sb.WriteString(item.name)
+			w.sb.WriteString(": ")
+		} else if item.key != nil {
+			w.write(item.key, level+1, true)
+			w.sb.WriteString(": ")
+		}
+		w.write(item.value, level+1, true)
+	}
+	delete(w.active, node)
+	if node.close != "" {
+		if len(node.items) > 0 {
+			w.newline(level)
+		}
+		w.sb.WriteString(node.close)
+	}
+}
+
+func (w *dumpWriter) writeReference(node *dumpNode, level int) {
+	w.sb.WriteString(node.head)
+	switch {
+	case node.ref == nil:
+		w.sb.WriteString(" (invalid)")
+	case w.active[node.ref]:
+		w.sb.WriteString(" (circular)")
+	case w.resolved[node.ref]:
+		// the referenced value has been rendered inline.
+	default:
+		w.sb.WriteString(" -> ")
+		w.resolving++
+		w.write(node.ref, level, false)
+		w.resolving--
+	}
+}
@@ -0,0 +1,155 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/format_test.go                                        |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCall(t *testing.T) {
+	s, err := Format([]byte(`Hm1{s6"simple"t}Cs3"sum"a2{1i10;}z`))
+	assert.NoError(t, err)
+	assert.Equal(t, `header
+  map(1) #0 {
+    string "simple" #1: true
+  }
+call
+  string "sum" #0
+  list(2) #0 [
+    int 1
+    int 10
+  ]
+end
+`, s)
+}
+
+func TestFormatResult(t *testing.T) {
+	s, err := Format([]byte("Ra9{l123;d1.5;NI-nefuAb2\"\x00\xff\"}z"))
+	assert.NoError(t, err)
+	assert.Equal(t, `result
+  list(9) #0 [
+    long 123
+    double 1.5
+    double NaN
+    double -Inf
+    null
+    empty
+    false
+    char "A"
+    bytes(2) "\x00\xff" #1
+  ]
+end
+`, s)
+	s, err = Format([]byte(`Es5"error"z`))
+	assert.NoError(t, err)
+	assert.Equal(t, "error\n  string \"error\" #0\nend\n", s)
+}
+
+func TestFormatValues(t *testing.T) {
+	s, err := Format([]byte(`D20200102T030405.678ZT120000;g{7d444840-9dc0-11d1-b245-5ffdce74fad2}a{}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `date 2020-01-02 03:04:05.678 UTC #0
+time 12:00:00 #1
+guid 7d444840-9dc0-11d1-b245-5ffdce74fad2 #2
+list(0) #3 []
+`, s)
+}
+
+type formatUser struct {
+	Name string
+	Self *formatUser
+}
+
+func TestFormatReferences(t *testing.T) {
+	u := &formatUser{Name: "Tom"}
+	u.Self = u
+	enc := NewEncoder(nil).Simple(false)
+	assert.NoError(t, enc.Encode([]interface{}{u, "Tom"}))
+	s, err := Format(enc.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, `list(2) #0 [
+  class @0 "formatUser" {
+    string "name" #1
+    string "self" #2
+  }
+  object @0 "formatUser" #3 {
+    name: string "Tom" #4
+    self: ref #3
+  }
+  ref #4
+]
+`, s)
+	s, err = Printer{Indent: "\t", ResolveReferences: true}.Format(enc.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, `list(2) #0 [
+	class @0 "formatUser" {
+		string "name" #1
+		string "self" #2
+	}
+	object @0 "formatUser" #3 {
+		name: string "Tom" #4
+		self: ref #3 (circular)
+	}
+	ref #4 -> string "Tom" #4
+]
+`, s)
+}
+
+func TestFormatResolveReferencesOnce(t *testing.T) {
+	s, err := Printer{Indent: "  ", ResolveReferences: true}.Format([]byte(`a3{a1{1}r1;r1;}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `list(3) #0 [
+  list(1) #1 [
+    int 1
+  ]
+  ref #1 -> list(1) #1 [
+    int 1
+  ]
+  ref #1
+]
+`, s)
+	data := "a2{11}"
+	for i := 19; i >= 0; i-- {
+		data = "a2{" + data + "r" + strconv.Itoa(i+1) + ";}"
+	}
+	s, err = Printer{Indent: "  ", ResolveReferences: true}.Format([]byte(data))
+	assert.NoError(t, err)
+	assert.Less(t, len(s), 1<<16)
+}
+
+func TestFormatMaxDepth(t *testing.T) {
+	s, err := Printer{Indent: "  ", Limits: Limits{MaxDepth: 2}}.Format([]byte(`a1{a1{a1{}}}`))
+	assert.Equal(t, ErrDepthExceeded, err)
+	assert.Equal(t, "list(1) #0 [\n  list(1) #1 [\n  ]\n]\n", s)
+	_, err = Format([]byte(strings.Repeat("a1{", 100000)))
+	assert.Equal(t, ErrDepthExceeded, err)
+}
+
+func TestFormatError(t *testing.T) {
+	s, err := Format([]byte(`a2{1s3"ab`))
+	assert.EqualError(t, err, "hprose/io: unexpected end of data at offset 9")
+	assert.Equal(t, "list(2) #0 [\n  int 1\n]\n", s)
+	_, err = Format([]byte(`a1{x}`))
+	assert.EqualError(t, err, "hprose/io: unexpected tag 'x' at offset 3")
+	_, err = Format([]byte(`o0{}`))
+	assert.EqualError(t, err, "hprose/io: invalid class index 0 at offset 3")
+	s, err = Printer{ResolveReferences: true}.Format([]byte(`r1;`))
+	assert.NoError(t, err)
+	assert.Equal(t, "ref #1 (invalid)\n", s)
+}
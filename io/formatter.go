@@ -6,7 +6,7 @@
 |                                                          |
 | io/formatter.go                                          |
 |                                                          |
-| LastModified: Feb 27, 2022                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,17 +15,53 @@ package io
 
 import (
 	"io"
+	"time"
 )
 
 type Formatter struct {
 	Simple bool
+	// Canonical makes Marshal encode the same value to the same bytes.
+	Canonical bool
+	// TypedUnion makes Marshal write the values of the types registered by
+	// RegisterUnion with their type names.
+	TypedUnion bool
+	// SimpleFallback makes Marshal encode v with references again if Simple
+	// is true and v has circular references.
+	SimpleFallback bool
+	// Redact makes Marshal write the fields with the redact option as
+	// Redacted.
+	Redact bool
+	// Cipher encrypts and decrypts the fields with the encrypt option if it
+	// is not nil.
+	Cipher Cipher
+	// Location is the location of the decoded time without time zone,
+	// nil means time.Local.
+	Location *time.Location
+	// Interner interns the decoded strings if it is not nil.
+	Interner StringInterner
+	// Arena makes Unmarshal allocate the decoded strings and bytes from
+	// shared blocks.
+	Arena bool
+	TimeZoneMode
+	MergeMode
 	LongType
 	RealType
 	MapType
+	Limits
 }
 
 func (f Formatter) Marshal(v interface{}) ([]byte, error) {
-	encoder := GetEncoder().Simple(f.Simple)
+	data, err := f.marshal(v, f.Simple)
+	if f.Simple && f.SimpleFallback && IsCircularReference(err) {
+		return f.marshal(v, false)
+	}
+	return data, err
+}
+
+func (f Formatter) marshal(v interface{}, simple bool) ([]byte, error) {
+	encoder := GetEncoder().Simple(simple).Canonical(f.Canonical).TypedUnion(f.TypedUnion).Redact(f.Redact)
+	encoder.TimeZoneMode = f.TimeZoneMode
+	encoder.Cipher = f.Cipher
 	defer FreeEncoder(encoder)
 	if err := encoder.Encode(v); err != nil {
 		return nil, err
@@ -44,6 +80,12 @@ func (f Formatter) Unmarshal(data []byte, v interface{}) error {
 	decoder.LongType = f.LongType
 	decoder.RealType = f.RealType
 	decoder.MapType = f.MapType
+	decoder.Limits = f.Limits
+	decoder.Location = f.Location
+	decoder.Interner = f.Interner
+	decoder.Arena = f.Arena
+	decoder.MergeMode = f.MergeMode
+	decoder.Cipher = f.Cipher
 	decoder.Decode(v)
 	return decoder.Error
 }
@@ -54,6 +96,12 @@ func (f Formatter) UnmarshalFromReader(reader io.Reader, v interface{}) error {
 	decoder.LongType = f.LongType
 	decoder.RealType = f.RealType
 	decoder.MapType = f.MapType
+	decoder.Limits = f.Limits
+	decoder.Location = f.Location
+	decoder.Interner = f.Interner
+	decoder.Arena = f.Arena
+	decoder.MergeMode = f.MergeMode
+	decoder.Cipher = f.Cipher
 	decoder.Decode(v)
 	return decoder.Error
 }
@@ -0,0 +1,39 @@
+//go:build go1.21
+// +build go1.21
+
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/generic.go                                            |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import "io"
+
+// UnmarshalAs decodes data to a value of type T.
+func UnmarshalAs[T any](data []byte) (v T, err error) {
+	err = Unmarshal(data, &v)
+	return
+}
+
+// UnmarshalFromReaderAs decodes data from reader to a value of type T.
+func UnmarshalFromReaderAs[T any](reader io.Reader) (v T, err error) {
+	err = UnmarshalFromReader(reader, &v)
+	return
+}
+
+// ReadAs returns a value of type T from the Decoder.
+// It is the generic version of Decoder.Read, the built-in types are decoded
+// by the fast path without reflection.
+func ReadAs[T any](dec *Decoder, tag ...byte) (v T) {
+	dec.Decode(&v, tag...)
+	return
+}
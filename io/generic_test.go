@@ -0,0 +1,54 @@
+//go:build go1.21
+// +build go1.21
+
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/generic_test.go                                       |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalAs(t *testing.T) {
+	data, err := io.Marshal([]int{1, 2, 3})
+	assert.NoError(t, err)
+	v, err := io.UnmarshalAs[[]int](data)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, v)
+	s, err := io.UnmarshalFromReaderAs[[]string](bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, s)
+	_, err = io.UnmarshalAs[int](data)
+	assert.Error(t, err)
+}
+
+func TestReadAs(t *testing.T) {
+	type point struct {
+		X int
+		Y int
+	}
+	enc := new(io.Encoder)
+	enc.Encode(123)
+	enc.Encode("hello")
+	enc.Encode(point{1, 2})
+	dec := io.NewDecoder(enc.Bytes())
+	assert.Equal(t, int64(123), io.ReadAs[int64](dec))
+	assert.Equal(t, "hello", io.ReadAs[string](dec))
+	assert.Equal(t, &point{1, 2}, io.ReadAs[*point](dec))
+	assert.NoError(t, dec.Error)
+}
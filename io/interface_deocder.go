@@ -6,7 +6,7 @@
 |                                                          |
 | io/interface_decoder.go                                  |
 |                                                          |
-| LastModified: Feb 18, 2024                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -214,3 +214,17 @@ type interfacePtrDecoder struct{}
 func (valdec interfacePtrDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 	dec.decodeInterfacePtr(tag, (**interface{})(reflect2.PtrOf(p)))
 }
+
+func getInterfaceDecoder(t reflect.Type) ValueDecoder {
+	if t.NumMethod() > 0 {
+		return nonEmptyInterfaceDecoder{t}
+	}
+	return interfaceDecoder{}
+}
+
+func getInterfacePtrDecoder(t reflect.Type) ValueDecoder {
+	if t.Elem().NumMethod() > 0 {
+		return nonEmptyInterfacePtrDecoder{t}
+	}
+	return interfacePtrDecoder{}
+}
@@ -0,0 +1,71 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/ip_decoder.go                                         |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"net"
+	"reflect"
+
+	"github.com/modern-go/reflect2"
+)
+
+func (dec *Decoder) stringToIP(s string) net.IP {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		dec.decodeStringError(s, "net.IP")
+	}
+	return ip
+}
+
+func (dec *Decoder) bytesToIP(data []byte) net.IP {
+	switch len(data) {
+	case net.IPv4len, net.IPv6len:
+		ip := make(net.IP, len(data))
+		copy(ip, data)
+		return ip
+	}
+	return dec.stringToIP(string(data))
+}
+
+func (dec *Decoder) decodeIP(t reflect.Type, tag byte, p *net.IP) {
+	switch tag {
+	case TagNull:
+		*p = nil
+	case TagEmpty:
+		*p = net.IP{}
+	case TagBytes:
+		*p = dec.bytesToIP(dec.ReadBytes())
+	default:
+		if s, ok := dec.decodeText(t, tag, p); ok {
+			*p = dec.stringToIP(s)
+		}
+	}
+}
+
+// ipDecoder is the implementation of ValueDecoder for net.IP.
+type ipDecoder struct{}
+
+func (ipDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	dec.decodeIP(reflect.TypeOf(p).Elem(), tag, (*net.IP)(reflect2.PtrOf(p)))
+}
+
+func init() {
+	registerValueDecoder(ipType, ipDecoder{})
+	RegisterConverter(stringType, ipType, func(dec *Decoder, o interface{}, p interface{}) {
+		*(*net.IP)(reflect2.PtrOf(p)) = dec.stringToIP(*(*string)(reflect2.PtrOf(o)))
+	})
+	RegisterConverter(bytesType, ipType, func(dec *Decoder, o interface{}, p interface{}) {
+		*(*net.IP)(reflect2.PtrOf(p)) = dec.bytesToIP(*(*[]byte)(reflect2.PtrOf(o)))
+	})
+}
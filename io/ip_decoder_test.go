@@ -0,0 +1,66 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/ip_decoder_test.go                                    |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeIP(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	enc.Encode(net.ParseIP("192.0.2.1"))
+	enc.Encode("192.0.2.1")
+	enc.Encode([]byte{192, 0, 2, 1})
+	enc.Encode([]byte("2001:db8::68"))
+	enc.Encode(nil)
+	enc.Encode("")
+	enc.Encode("bad")
+	dec := NewDecoder(([]byte)(sb.String())).Simple(false)
+	var ip net.IP
+	dec.Decode(&ip)
+	assert.Equal(t, "192.0.2.1", ip.String())
+	dec.Decode(&ip)
+	assert.Equal(t, "192.0.2.1", ip.String())
+	dec.Decode(&ip)
+	assert.Equal(t, "192.0.2.1", ip.String())
+	dec.Decode(&ip)
+	assert.Equal(t, "2001:db8::68", ip.String())
+	dec.Decode(&ip)
+	assert.Nil(t, ip)
+	dec.Decode(&ip)
+	assert.Equal(t, net.IP{}, ip)
+	assert.NoError(t, dec.Error)
+	dec.Decode(&ip)
+	assert.EqualError(t, dec.Error, `hprose/io: can not parse "bad" to net.IP`)
+}
+
+func TestDecodeIPField(t *testing.T) {
+	type Host struct {
+		Primary   net.IP
+		Secondary *net.IP
+	}
+	ip := net.ParseIP("2001:db8::68")
+	data, err := Marshal(Host{ip, &ip})
+	assert.NoError(t, err)
+	var host Host
+	assert.NoError(t, Unmarshal(data, &host))
+	assert.Equal(t, ip, host.Primary)
+	assert.Equal(t, ip, *host.Secondary)
+}
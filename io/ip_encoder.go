@@ -0,0 +1,37 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/ip_encoder.go                                         |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"net"
+	"unsafe"
+)
+
+// net.IP is encoded as a string in its textual form, such as "192.0.2.1" or
+// "2001:db8::68".
+func ipToString(p unsafe.Pointer) (string, bool) {
+	ip := *(*net.IP)(p)
+	switch {
+	case ip == nil:
+		return "", false
+	case len(ip) == 0:
+		return "", true
+	default:
+		return ip.String(), true
+	}
+}
+
+func init() {
+	RegisterValueEncoder((*net.IP)(nil), textEncoder(ipToString))
+}
@@ -0,0 +1,38 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/ip_encoder_test.go                                    |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeIP(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	ip := net.ParseIP("192.0.2.1")
+	assert.NoError(t, enc.Encode(ip))
+	assert.NoError(t, enc.Encode(&ip))
+	assert.NoError(t, enc.Encode(net.ParseIP("2001:db8::68")))
+	assert.NoError(t, enc.Encode(net.IP{}))
+	assert.NoError(t, enc.Encode(net.IP(nil)))
+	assert.NoError(t, enc.Encode((*net.IP)(nil)))
+	assert.NoError(t, enc.Encode(struct{ IP net.IP }{ip}))
+	assert.Equal(t, `s9"192.0.2.1"r0;s12"2001:db8::68"enn`+
+		`m1{s2"iP"s9"192.0.2.1"}`, sb.String())
+}
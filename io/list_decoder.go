@@ -6,7 +6,7 @@
 |                                                          |
 | io/list_decoder.go                                       |
 |                                                          |
-| LastModified: Feb 20, 2022                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -30,17 +30,19 @@ func (valdec listDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 	case TagEmpty:
 		*plist = list.New()
 	case TagList:
-		count := dec.ReadInt()
+		if !dec.enter() {
+			return
+		}
+		count := dec.readCount(interfaceSize)
 		l := list.New()
 		*plist = l
-		if !dec.IsSimple() {
-			dec.refer.Add(l)
-		}
+		dec.AddReference(l)
 		for i := 0; i < count; i++ {
 			var e interface{}
 			dec.decodeInterface(dec.NextByte(), &e)
 			l.PushBack(e)
 		}
+		dec.leave()
 		dec.Skip()
 	default:
 		dec.defaultDecode(listType, p, tag)
@@ -6,7 +6,7 @@
 |                                                          |
 | io/list_encoder.go                                       |
 |                                                          |
-| LastModified: Apr 12, 2020                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,6 +15,7 @@ package io
 
 import (
 	"container/list"
+	"unsafe"
 
 	"github.com/modern-go/reflect2"
 )
@@ -37,11 +38,17 @@ func (enc *Encoder) writeList(lst *list.List) {
 		enc.buf = append(enc.buf, TagList, TagOpenbrace, TagClosebrace)
 		return
 	}
+	p, t := unsafe.Pointer(lst), listType
+	if !enc.enter(p, t) {
+		enc.WriteNil()
+		return
+	}
 	enc.WriteListHead(count)
 	for e := lst.Front(); e != nil; e = e.Next() {
 		enc.encode(e.Value)
 	}
 	enc.WriteFoot()
+	enc.leave(p, t)
 }
 
 // elementEncoder is the implementation of ValueEncoder for list.Element/*list.Element.
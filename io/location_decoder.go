@@ -0,0 +1,78 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/location_decoder.go                                   |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/modern-go/reflect2"
+)
+
+func (dec *Decoder) stringToLocation(s string) *time.Location {
+	loc, err := time.LoadLocation(s)
+	if err != nil {
+		if dec.Error == nil {
+			dec.Error = err
+		}
+		return time.UTC
+	}
+	return loc
+}
+
+func (dec *Decoder) decodeLocationPtr(t reflect.Type, tag byte, p **time.Location) {
+	switch tag {
+	case TagNull:
+		*p = nil
+	case TagEmpty:
+		*p = time.UTC
+	default:
+		if s, ok := dec.decodeText(t, tag, p); ok {
+			*p = dec.stringToLocation(s)
+		}
+	}
+}
+
+func (dec *Decoder) decodeLocation(t reflect.Type, tag byte, p *time.Location) {
+	var loc *time.Location
+	dec.decodeLocationPtr(reflect.PtrTo(t), tag, &loc)
+	if loc != nil {
+		*p = *loc
+	}
+}
+
+// locationDecoder is the implementation of ValueDecoder for time.Location.
+type locationDecoder struct{}
+
+func (locationDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	dec.decodeLocation(reflect.TypeOf(p).Elem(), tag, (*time.Location)(reflect2.PtrOf(p)))
+}
+
+// locationPtrDecoder is the implementation of ValueDecoder for *time.Location.
+type locationPtrDecoder struct{}
+
+func (locationPtrDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	dec.decodeLocationPtr(reflect.TypeOf(p).Elem(), tag, (**time.Location)(reflect2.PtrOf(p)))
+}
+
+func init() {
+	registerValueDecoder(locationType, locationDecoder{})
+	registerValueDecoder(locationPtrType, locationPtrDecoder{})
+	RegisterConverter(stringType, locationType, func(dec *Decoder, o interface{}, p interface{}) {
+		*(*time.Location)(reflect2.PtrOf(p)) = *dec.stringToLocation(*(*string)(reflect2.PtrOf(o)))
+	})
+	RegisterConverter(stringType, locationPtrType, func(dec *Decoder, o interface{}, p interface{}) {
+		*(**time.Location)(reflect2.PtrOf(p)) = dec.stringToLocation(*(*string)(reflect2.PtrOf(o)))
+	})
+}
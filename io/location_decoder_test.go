@@ -0,0 +1,50 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/location_decoder_test.go                              |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeLocation(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	enc.Encode(time.UTC)
+	enc.Encode(time.Local)
+	enc.Encode(time.UTC)
+	enc.Encode(nil)
+	enc.Encode("")
+	enc.Encode("Nowhere/Unknown")
+	dec := NewDecoder(([]byte)(sb.String())).Simple(false)
+	var loc *time.Location
+	dec.Decode(&loc)
+	assert.Equal(t, time.UTC, loc)
+	dec.Decode(&loc)
+	assert.Equal(t, time.Local, loc)
+	var l time.Location
+	dec.Decode(&l)
+	assert.Equal(t, "UTC", l.String())
+	dec.Decode(&loc)
+	assert.Nil(t, loc)
+	dec.Decode(&loc)
+	assert.Equal(t, time.UTC, loc)
+	assert.NoError(t, dec.Error)
+	dec.Decode(&loc)
+	assert.Error(t, dec.Error)
+}
@@ -0,0 +1,29 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/location_encoder.go                                   |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"time"
+	"unsafe"
+)
+
+// time.Location is encoded as a string of its IANA Time Zone name, such as
+// "UTC", "Local" or "America/New_York".
+func locationToString(p unsafe.Pointer) (string, bool) {
+	return (*time.Location)(p).String(), true
+}
+
+func init() {
+	RegisterValueEncoder((*time.Location)(nil), textEncoder(locationToString))
+}
@@ -0,0 +1,34 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/location_encoder_test.go                              |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeLocation(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	assert.NoError(t, enc.Encode(time.UTC))
+	assert.NoError(t, enc.Encode(time.Local))
+	assert.NoError(t, enc.Encode(time.FixedZone("UTC+8", 8*60*60)))
+	assert.NoError(t, enc.Encode(time.UTC))
+	assert.NoError(t, enc.Encode((*time.Location)(nil)))
+	assert.Equal(t, `s3"UTC"s5"Local"s5"UTC+8"r0;n`, sb.String())
+}
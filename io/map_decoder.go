@@ -6,7 +6,7 @@
 |                                                          |
 | io/map_decoder.go                                        |
 |                                                          |
-| LastModified: Feb 20, 2022                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -28,6 +28,9 @@ type mapDecoder struct {
 	vt          reflect2.Type
 	decodeKey   DecodeHandler
 	decodeValue DecodeHandler
+	// hashKey is true if the key type is interface{}, the decoded keys are
+	// converted to their hashable representations.
+	hashKey bool
 }
 
 func (valdec mapDecoder) canDecodeListAsMap() bool {
@@ -103,13 +106,20 @@ func (valdec mapDecoder) canDecodeObjectAsMap() bool {
 	return false
 }
 
+func (valdec mapDecoder) entrySize() int {
+	return int(valdec.kt.Type1().Size() + valdec.vt.Type1().Size())
+}
+
 func (valdec mapDecoder) decodeListAsMap(dec *Decoder, p interface{}, tag byte) {
 	if !valdec.canDecodeListAsMap() {
 		dec.decodeError(valdec.t.Type1(), tag)
 		return
 	}
+	if !dec.enter() {
+		return
+	}
 	mp := reflect2.PtrOf(p)
-	count := dec.ReadInt()
+	count := dec.readCount(valdec.entrySize())
 	valdec.t.UnsafeSet(mp, valdec.t.UnsafeMakeMap(count))
 	dec.AddReference(p)
 	kp := valdec.kt.UnsafeNew()
@@ -120,23 +130,47 @@ func (valdec mapDecoder) decodeListAsMap(dec *Decoder, p interface{}, tag byte)
 		valdec.decodeValue(dec, vt, vp)
 		valdec.t.UnsafeSetIndex(mp, kp, vp)
 	}
+	dec.leave()
 	dec.Skip()
 }
 
 func (valdec mapDecoder) decodeMap(dec *Decoder, p interface{}) {
+	if !dec.enter() {
+		return
+	}
 	mp := reflect2.PtrOf(p)
-	count := dec.ReadInt()
-	valdec.t.UnsafeSet(mp, valdec.t.UnsafeMakeMap(count))
+	count := dec.readCount(valdec.entrySize())
+	merging := dec.isMerging() && !valdec.t.UnsafeIsNil(mp)
+	if !merging {
+		valdec.t.UnsafeSet(mp, valdec.t.UnsafeMakeMap(count))
+	}
 	dec.AddReference(p)
 	kp := valdec.kt.UnsafeNew()
 	vp := valdec.vt.UnsafeNew()
 	kt := valdec.kt.Type1()
 	vt := valdec.vt.Type1()
+	var zero unsafe.Pointer
+	if merging {
+		zero = valdec.vt.UnsafeNew()
+	}
 	for i := 0; i < count; i++ {
 		valdec.decodeKey(dec, kt, kp)
+		if valdec.hashKey {
+			k := (*interface{})(kp)
+			*k = dec.hashableKey(*k)
+		}
+		if merging {
+			// the existing value is patched by the decoded value.
+			if ep := valdec.t.UnsafeGetIndex(mp, kp); ep != nil {
+				valdec.vt.UnsafeSet(vp, ep)
+			} else {
+				valdec.vt.UnsafeSet(vp, zero)
+			}
+		}
 		valdec.decodeValue(dec, vt, vp)
 		valdec.t.UnsafeSetIndex(mp, kp, vp)
 	}
+	dec.leave()
 	dec.Skip()
 }
 
@@ -145,6 +179,9 @@ func (valdec mapDecoder) decodeObjectAsMap(dec *Decoder, p interface{}, tag byte
 		dec.decodeError(valdec.t.Type1(), tag)
 		return
 	}
+	if !dec.enter() {
+		return
+	}
 	index := dec.ReadInt()
 	structInfo := dec.getStructInfo(index)
 	mp := reflect2.PtrOf(p)
@@ -153,10 +190,14 @@ func (valdec mapDecoder) decodeObjectAsMap(dec *Decoder, p interface{}, tag byte
 	dec.AddReference(p)
 	if fields := structInfo.fields; fields != nil {
 		for _, name := range structInfo.names {
-			field := fields[name]
-			vp := field.Type.UnsafeNew()
-			field.Decode(dec, field.Type.Type1(), vp)
-			v := field.Type.UnsafeIndirect(vp)
+			var v interface{}
+			if field, ok := fields[name]; ok {
+				vp := field.Type.UnsafeNew()
+				field.Decode(dec, field.Type.Type1(), vp)
+				v = field.Type.UnsafeIndirect(vp)
+			} else {
+				dec.decodeInterface(dec.NextByte(), &v)
+			}
 			valdec.t.UnsafeSetIndex(mp, reflect2.PtrOf(name), reflect2.PtrOf(&v))
 		}
 	} else {
@@ -166,6 +207,7 @@ func (valdec mapDecoder) decodeObjectAsMap(dec *Decoder, p interface{}, tag byte
 			valdec.t.UnsafeSetIndex(mp, reflect2.PtrOf(name), reflect2.PtrOf(&v))
 		}
 	}
+	dec.leave()
 	dec.Skip()
 }
 
@@ -179,7 +221,9 @@ func (valdec mapDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 	case TagMap:
 		valdec.decodeMap(dec, p)
 	case TagEmpty:
-		valdec.t.UnsafeSet(reflect2.PtrOf(p), valdec.t.UnsafeMakeMap(0))
+		if mp := reflect2.PtrOf(p); !dec.isMerging() || valdec.t.UnsafeIsNil(mp) {
+			valdec.t.UnsafeSet(mp, valdec.t.UnsafeMakeMap(0))
+		}
 	case TagList:
 		valdec.decodeListAsMap(dec, p, tag)
 	case TagObject:
@@ -200,7 +244,63 @@ func makeMapDecoder(t reflect.Type) mapDecoder {
 		reflect2.Type2(vt),
 		GetDecodeHandler(kt),
 		GetDecodeHandler(vt),
+		kt.Kind() == reflect.Interface && kt.NumMethod() == 0,
+	}
+}
+
+// hashableKey returns the hashable representation of the map key k:
+//
+//	[]byte is converted to string,
+//	slice is converted to array, its elements are converted recursively,
+//	pointer to comparable struct is converted to struct,
+//	the other comparable values are returned as they are.
+//
+// It reports an error if k can not be a map key, such as a map.
+func (dec *Decoder) hashableKey(k interface{}) interface{} {
+	if k == nil {
+		return nil
+	}
+	if b, ok := k.([]byte); ok {
+		return string(b)
+	}
+	v := reflect.ValueOf(k)
+	t := v.Type()
+	switch t.Kind() {
+	case reflect.Slice:
+		return dec.sliceToArray(v)
+	case reflect.Ptr:
+		if et := t.Elem(); et.Kind() == reflect.Struct && et.Comparable() && !v.IsNil() {
+			return v.Elem().Interface()
+		}
+		return k
+	}
+	if t.Comparable() {
+		return k
+	}
+	if dec.Error == nil {
+		dec.Error = DecodeError("hprose/io: unhashable map key type " + t.String())
+	}
+	return nil
+}
+
+// sliceToArray converts the list decoded as a map key to an array. The array
+// types are created at runtime and cached by reflect.ArrayOf, the length of
+// the list is limited by MaxCollectionLength when it is decoded.
+func (dec *Decoder) sliceToArray(v reflect.Value) interface{} {
+	n := v.Len()
+	et := v.Type().Elem()
+	if k := et.Kind(); k == reflect.Interface || k == reflect.Ptr || !et.Comparable() {
+		a := reflect.New(reflect.ArrayOf(n, interfaceType)).Elem()
+		for i := 0; i < n; i++ {
+			if e := dec.hashableKey(v.Index(i).Interface()); e != nil {
+				a.Index(i).Set(reflect.ValueOf(e))
+			}
+		}
+		return a.Interface()
 	}
+	a := reflect.New(reflect.ArrayOf(n, et)).Elem()
+	reflect.Copy(a, v)
+	return a.Interface()
 }
 
 func getMapDecoder(t reflect.Type) ValueDecoder {
@@ -6,7 +6,7 @@
 |                                                          |
 | io/map_decoder_test.go                                   |
 |                                                          |
-| LastModified: Mar 16, 2022                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -7214,3 +7214,111 @@ func BenchmarkJsonDecodeIntIntMap(b *testing.B) {
 		dec.Decode(&m)
 	}
 }
+
+type mapKeyPoint struct {
+	X, Y int
+}
+
+func init() {
+	Register(mapKeyPoint{})
+}
+
+func TestCompositeMapKeys(t *testing.T) {
+	id := [4]byte{1, 2, 3, 4}
+	data, err := Marshal(map[[4]byte]int{id: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "m1{b4\"\x01\x02\x03\x04\"1}", string(data))
+	var m1 map[[4]byte]int
+	assert.NoError(t, Unmarshal(data, &m1))
+	assert.Equal(t, map[[4]byte]int{id: 1}, m1)
+
+	data, err = Marshal(map[mapKeyPoint]string{{1, 2}: "a"})
+	assert.NoError(t, err)
+	var m2 map[mapKeyPoint]string
+	assert.NoError(t, Unmarshal(data, &m2))
+	assert.Equal(t, map[mapKeyPoint]string{{1, 2}: "a"}, m2)
+
+	data, err = Marshal(map[[2]int]int{{1, 2}: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, `m1{a2{12}3}`, string(data))
+	var m3 map[[2]int]int
+	assert.NoError(t, Unmarshal(data, &m3))
+	assert.Equal(t, map[[2]int]int{{1, 2}: 3}, m3)
+}
+
+func TestDecodeCompositeKeysToInterfaceMap(t *testing.T) {
+	data, err := Marshal(map[[4]byte]int{{1, 2, 3, 4}: 1})
+	assert.NoError(t, err)
+	var m1 map[interface{}]interface{}
+	assert.NoError(t, Unmarshal(data, &m1))
+	assert.Equal(t, map[interface{}]interface{}{"\x01\x02\x03\x04": 1}, m1)
+
+	data, err = Marshal(map[[2]int]int{{1, 2}: 3})
+	assert.NoError(t, err)
+	var m2 map[interface{}]interface{}
+	assert.NoError(t, Unmarshal(data, &m2))
+	assert.Equal(t, map[interface{}]interface{}{[2]interface{}{1, 2}: 3}, m2)
+	var m3 map[interface{}]interface{}
+	dec := NewDecoder(data)
+	dec.ListType = ListTypeSlice
+	dec.Decode(&m3)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, map[interface{}]interface{}{[2]int{1, 2}: 3}, m3)
+
+	data, err = Marshal(map[mapKeyPoint]int{{1, 2}: 3})
+	assert.NoError(t, err)
+	var m4 map[interface{}]interface{}
+	assert.NoError(t, Unmarshal(data, &m4))
+	assert.Equal(t, map[interface{}]interface{}{mapKeyPoint{1, 2}: 3}, m4)
+
+	data = []byte(`m1{a2{a2{12}s5"hello"}1}`)
+	var m5 map[interface{}]interface{}
+	assert.NoError(t, Unmarshal(data, &m5))
+	assert.Equal(t, map[interface{}]interface{}{[2]interface{}{[2]interface{}{1, 2}, "hello"}: 1}, m5)
+
+	var m6 map[interface{}]interface{}
+	assert.EqualError(t, Unmarshal([]byte(`m1{m1{uaub}1}`), &m6), "hprose/io: unhashable map key type map[interface {}]interface {}")
+
+	data, err = Marshal(map[[32]int]int{{31: 1}: 1})
+	assert.NoError(t, err)
+	var m7 map[interface{}]interface{}
+	dec = NewDecoder(data)
+	dec.ListType = ListTypeSlice
+	dec.Decode(&m7)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, map[interface{}]interface{}{[32]int{31: 1}: 1}, m7)
+	var m8 map[interface{}]interface{}
+	dec = NewDecoder(data)
+	dec.MaxCollectionLength = 16
+	dec.Decode(&m8)
+	assert.Equal(t, ErrCollectionTooLong, dec.Error)
+}
+
+func TestDecodeCompositeKeysToInterface(t *testing.T) {
+	data, err := Marshal(map[[2]int]int{{1, 2}: 3})
+	assert.NoError(t, err)
+	var v interface{}
+	assert.NoError(t, Unmarshal(data, &v))
+	assert.Equal(t, map[interface{}]interface{}{[2]interface{}{1, 2}: 3}, v)
+
+	var v2 interface{}
+	assert.NoError(t, Unmarshal([]byte(`m4{uaus1ubuta2{12}ucn}`), &v2))
+	assert.Equal(t, map[interface{}]interface{}{"a": "s", 1: "b", "t": []interface{}{1, 2}, "c": nil}, v2)
+
+	dec := NewDecoder([]byte(`a2{m2{a1{1}uaub1}r1;}`)).Simple(false)
+	var v3 []interface{}
+	dec.Decode(&v3)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, map[interface{}]interface{}{[1]interface{}{1}: "a", "b": 1}, v3[0])
+	assert.Equal(t, v3[0], *(v3[1].(*map[interface{}]interface{})))
+
+	f := Formatter{Simple: true, MapType: MapTypeSIMap}
+	var v4 interface{}
+	assert.NoError(t, f.Unmarshal([]byte(`m2{1s1"a"ubuc}`), &v4))
+	assert.Equal(t, map[string]interface{}{"1": "a", "b": "c"}, v4)
+	var v5 interface{}
+	assert.EqualError(t, f.Unmarshal(data, &v5), "hprose/io: can not cast []interface {} to string")
+
+	var m map[string]interface{}
+	assert.EqualError(t, Unmarshal(data, &m), "hprose/io: can not cast []interface {} to string")
+}
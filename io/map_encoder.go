@@ -6,7 +6,7 @@
 |                                                          |
 | io/map_encoder.go                                        |
 |                                                          |
-| LastModified: Feb 18, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -48,9 +48,19 @@ func (enc *Encoder) writeMap(v interface{}) {
 		enc.buf = append(enc.buf, TagMap, TagOpenbrace, TagClosebrace)
 		return
 	}
+	p, t := reflect2.PtrOf(v), reflect.TypeOf(v)
+	if !enc.enter(p, t) {
+		enc.WriteNil()
+		return
+	}
 	enc.WriteMapHead(count)
-	enc.writeMapBody(v)
+	if enc.canonical {
+		enc.writeCanonicalMapBody(v)
+	} else {
+		enc.writeMapBody(v)
+	}
 	enc.WriteFoot()
+	enc.leave(p, t)
 }
 
 //nolint
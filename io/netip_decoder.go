@@ -0,0 +1,71 @@
+//go:build go1.18
+// +build go1.18
+
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/netip_decoder.go                                      |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"net/netip"
+	"reflect"
+
+	"github.com/modern-go/reflect2"
+)
+
+var addrType = reflect.TypeOf((*netip.Addr)(nil)).Elem()
+
+func (dec *Decoder) stringToAddr(s string) netip.Addr {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		dec.decodeStringError(s, "netip.Addr")
+	}
+	return addr
+}
+
+func (dec *Decoder) bytesToAddr(data []byte) netip.Addr {
+	if addr, ok := netip.AddrFromSlice(data); ok {
+		return addr
+	}
+	return dec.stringToAddr(string(data))
+}
+
+func (dec *Decoder) decodeAddr(t reflect.Type, tag byte, p *netip.Addr) {
+	switch tag {
+	case TagNull, TagEmpty:
+		*p = netip.Addr{}
+	case TagBytes:
+		*p = dec.bytesToAddr(dec.ReadBytes())
+	default:
+		if s, ok := dec.decodeText(t, tag, p); ok {
+			*p = dec.stringToAddr(s)
+		}
+	}
+}
+
+// addrDecoder is the implementation of ValueDecoder for netip.Addr.
+type addrDecoder struct{}
+
+func (addrDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	dec.decodeAddr(reflect.TypeOf(p).Elem(), tag, (*netip.Addr)(reflect2.PtrOf(p)))
+}
+
+func init() {
+	registerValueDecoder(addrType, addrDecoder{})
+	RegisterConverter(stringType, addrType, func(dec *Decoder, o interface{}, p interface{}) {
+		*(*netip.Addr)(reflect2.PtrOf(p)) = dec.stringToAddr(*(*string)(reflect2.PtrOf(o)))
+	})
+	RegisterConverter(bytesType, addrType, func(dec *Decoder, o interface{}, p interface{}) {
+		*(*netip.Addr)(reflect2.PtrOf(p)) = dec.bytesToAddr(*(*[]byte)(reflect2.PtrOf(o)))
+	})
+}
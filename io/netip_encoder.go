@@ -0,0 +1,36 @@
+//go:build go1.18
+// +build go1.18
+
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/netip_encoder.go                                      |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"net/netip"
+	"unsafe"
+)
+
+// netip.Addr is encoded as a string in its textual form, the zero Addr is
+// encoded as null.
+func addrToString(p unsafe.Pointer) (string, bool) {
+	addr := *(*netip.Addr)(p)
+	if !addr.IsValid() {
+		return "", false
+	}
+	return addr.String(), true
+}
+
+func init() {
+	RegisterValueEncoder((*netip.Addr)(nil), textEncoder(addrToString))
+}
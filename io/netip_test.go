@@ -0,0 +1,60 @@
+//go:build go1.18
+// +build go1.18
+
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/netip_test.go                                         |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeAddr(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	addr := netip.MustParseAddr("192.0.2.1")
+	assert.NoError(t, enc.Encode(addr))
+	assert.NoError(t, enc.Encode(&addr))
+	assert.NoError(t, enc.Encode(netip.Addr{}))
+	assert.NoError(t, enc.Encode((*netip.Addr)(nil)))
+	assert.Equal(t, `s9"192.0.2.1"r0;nn`, sb.String())
+}
+
+func TestDecodeAddr(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	enc.Encode(netip.MustParseAddr("2001:db8::68"))
+	enc.Encode("2001:db8::68")
+	enc.Encode([]byte{192, 0, 2, 1})
+	enc.Encode(nil)
+	enc.Encode("bad")
+	dec := NewDecoder(([]byte)(sb.String())).Simple(false)
+	var addr netip.Addr
+	dec.Decode(&addr)
+	assert.Equal(t, netip.MustParseAddr("2001:db8::68"), addr)
+	dec.Decode(&addr)
+	assert.Equal(t, netip.MustParseAddr("2001:db8::68"), addr)
+	dec.Decode(&addr)
+	assert.Equal(t, netip.MustParseAddr("192.0.2.1"), addr)
+	dec.Decode(&addr)
+	assert.Equal(t, netip.Addr{}, addr)
+	assert.NoError(t, dec.Error)
+	dec.Decode(&addr)
+	assert.EqualError(t, dec.Error, `hprose/io: can not parse "bad" to netip.Addr`)
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | io/num_encoder.go                                        |
 |                                                          |
-| LastModified: May 9, 2021                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -106,6 +106,10 @@ func (enc *Encoder) WriteUint(i uint) {
 }
 
 func (enc *Encoder) writeFloat(f float64, bitSize int) {
+	if enc.canonical && f == 0 {
+		// normalizes negative zero.
+		f = 0
+	}
 	switch {
 	case f != f:
 		enc.buf = append(enc.buf, TagNaN)
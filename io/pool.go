@@ -6,7 +6,7 @@
 |                                                          |
 | io/pool.go                                               |
 |                                                          |
-| LastModified: Feb 27, 2022                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -23,7 +23,9 @@ func GetEncoder() *Encoder {
 }
 
 func FreeEncoder(encoder *Encoder) {
-	encoderPool.Put(encoder.Simple(false).ResetBuffer())
+	encoder.TimeZoneMode = TimeZoneLocal
+	encoder.Cipher = nil
+	encoderPool.Put(encoder.Simple(false).Canonical(false).TypedUnion(false).Redact(false).ResetBuffer())
 }
 
 func GetDecoder() *Decoder {
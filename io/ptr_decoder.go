@@ -6,7 +6,7 @@
 |                                                          |
 | io/ptr_decoder.go                                        |
 |                                                          |
-| LastModified: Mar 18, 2022                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -114,7 +114,7 @@ func init() {
 		reflect.Array:         getArrayPtrDecoder,
 		reflect.Chan:          invalidDecoder,
 		reflect.Func:          invalidDecoder,
-		reflect.Interface:     func(t reflect.Type) ValueDecoder { return interfacePtrDecoder{} },
+		reflect.Interface:     getInterfacePtrDecoder,
 		reflect.Map:           getMapPtrDecoder,
 		reflect.Ptr:           getPtrPtrDecoder,
 		reflect.Slice:         getSlicePtrDecoder,
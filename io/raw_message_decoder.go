@@ -0,0 +1,74 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/raw_message_decoder.go                                |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/modern-go/reflect2"
+)
+
+// marshalJSON converts the value which is not a string to its JSON text.
+func (dec *Decoder) marshalJSON(tag byte) json.RawMessage {
+	var v interface{}
+	mapType := dec.MapType
+	dec.MapType = MapTypeSIMap
+	dec.decode(&v, tag)
+	dec.MapType = mapType
+	if dec.Error != nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		dec.Error = err
+	}
+	return data
+}
+
+// decodeRawMessage decodes strings and bytes as JSON text, and other values
+// are converted to their JSON text.
+func (dec *Decoder) decodeRawMessage(t reflect.Type, tag byte, p *json.RawMessage) {
+	switch tag {
+	case TagNull:
+		*p = nil
+	case TagEmpty:
+		*p = json.RawMessage{}
+	case TagUTF8Char, TagString, TagBytes:
+		if s, ok := dec.decodeText(t, tag, p); ok {
+			*p = json.RawMessage(s)
+		}
+	case TagRef, TagError:
+		dec.defaultDecode(t, p, tag)
+	default:
+		*p = dec.marshalJSON(tag)
+	}
+}
+
+// rawMessageDecoder is the implementation of ValueDecoder for json.RawMessage.
+type rawMessageDecoder struct{}
+
+func (rawMessageDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	dec.decodeRawMessage(reflect.TypeOf(p).Elem(), tag, (*json.RawMessage)(reflect2.PtrOf(p)))
+}
+
+func init() {
+	registerValueDecoder(rawMessageType, rawMessageDecoder{})
+	RegisterConverter(stringType, rawMessageType, func(dec *Decoder, o interface{}, p interface{}) {
+		*(*json.RawMessage)(reflect2.PtrOf(p)) = json.RawMessage(*(*string)(reflect2.PtrOf(o)))
+	})
+	RegisterConverter(bytesType, rawMessageType, func(dec *Decoder, o interface{}, p interface{}) {
+		*(*json.RawMessage)(reflect2.PtrOf(p)) = *(*[]byte)(reflect2.PtrOf(o))
+	})
+}
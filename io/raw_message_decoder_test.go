@@ -0,0 +1,67 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/raw_message_decoder_test.go                           |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeRawMessage(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	enc.Encode(json.RawMessage(`{"a":1}`))
+	enc.Encode(json.RawMessage(`{"a":1}`))
+	enc.Encode([]byte(`[1,2]`))
+	enc.Encode(map[string]interface{}{"a": []int{1, 2}, "b": true})
+	enc.Encode(123)
+	enc.Encode(nil)
+	enc.Encode("")
+	dec := NewDecoder(([]byte)(sb.String())).Simple(false)
+	var raw json.RawMessage
+	dec.Decode(&raw)
+	assert.Equal(t, `{"a":1}`, string(raw))
+	dec.Decode(&raw)
+	assert.Equal(t, `{"a":1}`, string(raw))
+	dec.Decode(&raw)
+	assert.Equal(t, `[1,2]`, string(raw))
+	dec.Decode(&raw)
+	assert.Equal(t, `{"a":[1,2],"b":true}`, string(raw))
+	dec.Decode(&raw)
+	assert.Equal(t, `123`, string(raw))
+	dec.Decode(&raw)
+	assert.Nil(t, raw)
+	dec.Decode(&raw)
+	assert.Equal(t, json.RawMessage{}, raw)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, MapTypeIIMap, dec.MapType)
+}
+
+func TestDecodeRawMessageFromReference(t *testing.T) {
+	var v struct {
+		A string
+		B json.RawMessage
+	}
+	dec := NewDecoder([]byte(`c1"T"2{s1"a"s1"b"}o0{s7"{"a":1}"r3;}`)).Simple(false)
+	dec.Decode(&v)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, `{"a":1}`, v.A)
+	assert.Equal(t, `{"a":1}`, string(v.B))
+	v.B[0] = '['
+	assert.Equal(t, `{"a":1}`, v.A)
+}
@@ -0,0 +1,34 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/raw_message_encoder.go                                |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"encoding/json"
+	"unsafe"
+
+	"github.com/hprose/hprose-golang/v3/internal/convert"
+)
+
+// json.RawMessage is encoded as a string of its JSON text.
+func rawMessageToString(p unsafe.Pointer) (string, bool) {
+	data := *(*json.RawMessage)(p)
+	if data == nil {
+		return "", false
+	}
+	return convert.ToUnsafeString(data), true
+}
+
+func init() {
+	RegisterValueEncoder((*json.RawMessage)(nil), textEncoder(rawMessageToString))
+}
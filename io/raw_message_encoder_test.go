@@ -0,0 +1,34 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/raw_message_encoder_test.go                           |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeRawMessage(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	raw := json.RawMessage(`{"a":1}`)
+	assert.NoError(t, enc.Encode(raw))
+	assert.NoError(t, enc.Encode(&raw))
+	assert.NoError(t, enc.Encode(json.RawMessage{}))
+	assert.NoError(t, enc.Encode(json.RawMessage(nil)))
+	assert.Equal(t, `s7"{"a":1}"r0;en`, sb.String())
+}
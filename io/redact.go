@@ -0,0 +1,144 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/redact.go                                             |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Redacted is the placeholder of the fields with the redact option in
+// redaction mode.
+const Redacted = "[REDACTED]"
+
+// Redact sets the encoder to redaction mode or not.
+//
+// In redaction mode, the fields with the redact option, for example:
+//
+//	Password string `hprose:"password,redact"`
+//
+// are written as the string Redacted whatever their values are. The data
+// written in redaction mode is used for displaying, like logs, it may not
+// be decoded into the original types.
+func (enc *Encoder) Redact(redact bool) *Encoder {
+	enc.redact = redact
+	return enc
+}
+
+// IsRedact returns the encoder is in redaction mode or not.
+func (enc *Encoder) IsRedact() bool {
+	return enc.redact
+}
+
+func redactEncodeHandler(encode EncodeHandler) EncodeHandler {
+	return func(enc *Encoder, v interface{}) {
+		if enc.redact {
+			enc.EncodeString(Redacted)
+			return
+		}
+		encode(enc, v)
+	}
+}
+
+// Redact returns a copy of the hprose data, in which the values of the fields
+// with the redact option are replaced by Redacted.
+//
+// The data may be a value or a sequence of hprose rpc request or response.
+// The objects of the structs which are registered by Register or
+// RegisterName, or which have been encoded, are redacted, the other data is
+// copied as is.
+func Redact(data []byte) ([]byte, error) {
+	dec := NewDecoder(data).Simple(false)
+	enc := NewEncoder(nil).Simple(false)
+	for {
+		tag := dec.NextByte()
+		if dec.Error == io.EOF {
+			return enc.Bytes(), nil
+		}
+		switch tag {
+		case TagHeader, TagCall, TagResult, TagError, TagEnd:
+			enc.buf = append(enc.buf, tag)
+		default:
+			var v *Value
+			dec.Decode(&v, tag)
+			if dec.Error != nil {
+				return nil, dec.Error
+			}
+			redactValue(v, map[*Value]bool{})
+			if err := enc.Encode(v); err != nil {
+				return nil, err
+			}
+		}
+		dec.Reset()
+		enc.Reset()
+	}
+}
+
+func redactValue(v *Value, visited map[*Value]bool) {
+	if v == nil || visited[v] {
+		return
+	}
+	visited[v] = true
+	var fields map[string]bool
+	switch v.Kind() {
+	case KindObject:
+		fields = redactedFields(v.Class())
+	case KindList, KindMap:
+	default:
+		return
+	}
+	n := v.Len()
+	for i := 0; i < n; i++ {
+		if fields != nil && fields[v.Key(i).Text()] {
+			v.SetIndex(i, NewString(Redacted))
+		} else {
+			redactValue(v.Index(i), visited)
+		}
+	}
+}
+
+var (
+	redactedFieldMap  = make(map[string]map[string]bool)
+	redactedFieldLock sync.RWMutex
+)
+
+// registerRedactedFields records the aliases of the fields with the redact
+// option by the class name of the struct, so the objects of the structs
+// which are encoded but not registered can be redacted too. The fields of
+// the structs with the same class name are merged.
+func registerRedactedFields(class string, fields []FieldAccessor) {
+	redactedFieldLock.Lock()
+	defer redactedFieldLock.Unlock()
+	for _, field := range fields {
+		if field.redact {
+			if redactedFieldMap[class] == nil {
+				redactedFieldMap[class] = make(map[string]bool)
+			}
+			redactedFieldMap[class][field.Alias] = true
+		}
+	}
+}
+
+func redactedFields(class string) map[string]bool {
+	redactedFieldLock.RLock()
+	defer redactedFieldLock.RUnlock()
+	return redactedFieldMap[class]
+}
+
+// HasRedactOption returns true if the struct field tag has the redact option
+// in the hprose or json tag.
+func HasRedactOption(tag reflect.StructTag) bool {
+	return hasOption(tag, "redact", nil)
+}
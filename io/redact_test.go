@@ -0,0 +1,85 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/redact_test.go                                        |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"testing"
+
+	"github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+type redactUser struct {
+	Name     string `hprose:"name"`
+	Password string `hprose:"password,redact"`
+	Age      int    `json:",redact"`
+}
+
+func init() {
+	io.RegisterName("RedactUser", (*redactUser)(nil))
+}
+
+func TestEncoderRedact(t *testing.T) {
+	user := &redactUser{"Tom", "secret", 18}
+	data, err := io.Formatter{Redact: true}.Marshal(user)
+	assert.NoError(t, err)
+	assert.Equal(t, `c10"RedactUser"3{s4"name"s8"password"s3"age"}o0{s3"Tom"s10"[REDACTED]"r5;}`, string(data))
+
+	data, err = io.Formatter{Simple: true}.Marshal(user)
+	assert.NoError(t, err)
+	assert.Equal(t, `c10"RedactUser"3{s4"name"s8"password"s3"age"}o0{s3"Tom"s6"secret"i18;}`, string(data))
+
+	enc := new(io.Encoder).Redact(true)
+	assert.True(t, enc.IsRedact())
+	assert.False(t, enc.Redact(false).IsRedact())
+}
+
+func TestRedact(t *testing.T) {
+	user := &redactUser{"Tom", "secret", 18}
+	data, err := io.Marshal([]interface{}{user, user, "secret"})
+	assert.NoError(t, err)
+	redacted, err := io.Redact(data)
+	assert.NoError(t, err)
+	assert.Equal(t, `a3{c10"RedactUser"3{s4"name"s8"password"s3"age"}o0{s3"Tom"s10"[REDACTED]"s10"[REDACTED]"}o0{s3"Tom"s10"[REDACTED]"s10"[REDACTED]"}s6"secret"}`, string(redacted))
+
+	request := []byte(`Hm1{s6"simple"t}Cs5"login"a1{c10"RedactUser"3{s4"name"s8"password"s3"age"}o0{s3"Tom"s6"secret"i18;}}z`)
+	redacted, err = io.Redact(request)
+	assert.NoError(t, err)
+	assert.Equal(t, `Hm1{s6"simple"t}Cs5"login"a1{c10"RedactUser"3{s4"name"s8"password"s3"age"}o0{s3"Tom"s10"[REDACTED]"s10"[REDACTED]"}}z`, string(redacted))
+
+	redacted, err = io.Redact([]byte(`c10"redactUser"1{s8"password"}o0{s6"secret"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `c10"redactUser"1{s8"password"}o0{s6"secret"}`, string(redacted))
+
+	redacted, err = io.Redact([]byte(`m1{s8"password"s6"secret"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `m1{s8"password"s6"secret"}`, string(redacted))
+
+	_, err = io.Redact([]byte(`a1{`))
+	assert.Error(t, err)
+}
+
+type redactLogin struct {
+	User     string `hprose:"user"`
+	Password string `hprose:"password,redact"`
+}
+
+func TestRedactUnregistered(t *testing.T) {
+	data, err := io.Marshal(redactLogin{"Tom", "secret"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `s6"secret"`)
+	redacted, err := io.Redact(data)
+	assert.NoError(t, err)
+	assert.Equal(t, `c11"redactLogin"2{s4"user"s8"password"}o0{s3"Tom"s10"[REDACTED]"}`, string(redacted))
+}
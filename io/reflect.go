@@ -6,7 +6,7 @@
 |                                                          |
 | io/reflect.go                                            |
 |                                                          |
-| LastModified: Mar 5, 2022                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,7 +15,10 @@ package io
 
 import (
 	"container/list"
+	"encoding/json"
 	"math/big"
+	"net"
+	"net/url"
 	"reflect"
 	"time"
 	"unsafe"
@@ -106,8 +109,15 @@ var uuidType = reflect.TypeOf((*uuid.UUID)(nil)).Elem()
 var bigIntValueType = reflect.TypeOf((*big.Int)(nil)).Elem()
 var bigFloatValueType = reflect.TypeOf((*big.Float)(nil)).Elem()
 var bigRatValueType = reflect.TypeOf((*big.Rat)(nil)).Elem()
+var durationType = reflect.TypeOf((*time.Duration)(nil)).Elem()
+var locationType = reflect.TypeOf((*time.Location)(nil)).Elem()
+var ipType = reflect.TypeOf((*net.IP)(nil)).Elem()
+var urlType = reflect.TypeOf((*url.URL)(nil)).Elem()
+var rawMessageType = reflect.TypeOf((*json.RawMessage)(nil)).Elem()
+var dynamicType = reflect.TypeOf((*Value)(nil)).Elem()
 
 var boolPtrType = reflect.TypeOf((*bool)(nil))
+var dynamicPtrType = reflect.TypeOf((*Value)(nil))
 var intPtrType = reflect.TypeOf((*int)(nil))
 var int8PtrType = reflect.TypeOf((*int8)(nil))
 var int16PtrType = reflect.TypeOf((*int16)(nil))
@@ -127,6 +137,7 @@ var bytesPtrType = reflect.TypeOf((*[]byte)(nil))
 var stringPtrType = reflect.TypeOf((*string)(nil))
 var timePtrType = reflect.TypeOf((*time.Time)(nil))
 var uuidPtrType = reflect.TypeOf((*uuid.UUID)(nil))
+var locationPtrType = reflect.TypeOf((*time.Location)(nil))
 var bigIntType = reflect.TypeOf((*big.Int)(nil))
 var bigFloatType = reflect.TypeOf((*big.Float)(nil))
 var bigRatType = reflect.TypeOf((*big.Rat)(nil))
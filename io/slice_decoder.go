@@ -6,7 +6,7 @@
 |                                                          |
 | io/slice_decoder.go                                      |
 |                                                          |
-| LastModified: Jun 5, 2021                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -33,15 +33,33 @@ func (valdec sliceDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 	case TagNull:
 		valdec.t.UnsafeSetNil(reflect2.PtrOf(p))
 	case TagEmpty:
-		setSliceHeader(reflect2.PtrOf(p), valdec.empty, 0)
+		if dec.MergeMode != MergeModeAppend || valdec.t.UnsafeIsNil(reflect2.PtrOf(p)) {
+			setSliceHeader(reflect2.PtrOf(p), valdec.empty, 0)
+		}
 	case TagList:
-		count := dec.ReadInt()
+		if !dec.enter() {
+			return
+		}
+		count := dec.readCount(int(valdec.et.Size()))
 		slice := reflect2.PtrOf(p)
-		valdec.t.UnsafeGrow(slice, count)
+		n := 0
+		if dec.MergeMode == MergeModeAppend {
+			n = (*sliceHeader)(slice).Len
+			valdec.t.UnsafeGrow(slice, n+count)
+			// clears the elements beyond the old length before decoding.
+			et := reflect2.Type2(valdec.et)
+			zero := et.UnsafeNew()
+			for i := n; i < n+count; i++ {
+				et.UnsafeSet(valdec.t.UnsafeGetIndex(slice, i), zero)
+			}
+		} else {
+			valdec.t.UnsafeGrow(slice, count)
+		}
 		dec.AddReference(p)
-		for i := 0; i < count; i++ {
+		for i := n; i < n+count; i++ {
 			valdec.decodeElem(dec, valdec.et, valdec.t.UnsafeGetIndex(slice, i))
 		}
+		dec.leave()
 		dec.Skip()
 	default:
 		dec.defaultDecode(valdec.t.Type1(), p, tag)
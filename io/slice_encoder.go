@@ -6,7 +6,7 @@
 |                                                          |
 | io/slice_encoder.go                                      |
 |                                                          |
-| LastModified: Feb 18, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,6 +15,7 @@ package io
 
 import (
 	"reflect"
+	"unsafe"
 
 	"github.com/modern-go/reflect2"
 )
@@ -46,14 +47,21 @@ func (enc *Encoder) writeSlice(v interface{}) {
 		enc.buf = appendBytes(enc.buf, bytes)
 		return
 	}
-	count := (*reflect.SliceHeader)(reflect2.PtrOf(v)).Len
+	header := (*reflect.SliceHeader)(reflect2.PtrOf(v))
+	count := header.Len
 	if count == 0 {
 		enc.buf = append(enc.buf, TagList, TagOpenbrace, TagClosebrace)
 		return
 	}
+	p, t := unsafe.Pointer(header.Data), reflect.TypeOf(v)
+	if !enc.enter(p, t) {
+		enc.WriteNil()
+		return
+	}
 	enc.WriteListHead(count)
 	enc.writeSliceBody(v, count)
 	enc.WriteFoot()
+	enc.leave(p, t)
 }
 
 func (enc *Encoder) write2dSliceBody(v interface{}, n int) {
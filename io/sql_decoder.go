@@ -0,0 +1,81 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/sql_decoder.go                                        |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"database/sql"
+	"time"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// nullableDecoder is the implementation of ValueDecoder for the sql.Null*
+// types, null is decoded as an invalid value.
+type nullableDecoder func(dec *Decoder, p unsafe.Pointer, tag byte)
+
+func (valdec nullableDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	valdec(dec, reflect2.PtrOf(p), tag)
+}
+
+func init() {
+	RegisterValueDecoder(sql.NullBool{}, nullableDecoder(func(dec *Decoder, p unsafe.Pointer, tag byte) {
+		v := (*sql.NullBool)(p)
+		if v.Valid = tag != TagNull; v.Valid {
+			dec.decodeBool(boolType, tag, &v.Bool)
+		} else {
+			v.Bool = false
+		}
+	}))
+	RegisterValueDecoder(sql.NullInt32{}, nullableDecoder(func(dec *Decoder, p unsafe.Pointer, tag byte) {
+		v := (*sql.NullInt32)(p)
+		if v.Valid = tag != TagNull; v.Valid {
+			dec.decodeInt32(int32Type, tag, &v.Int32)
+		} else {
+			v.Int32 = 0
+		}
+	}))
+	RegisterValueDecoder(sql.NullInt64{}, nullableDecoder(func(dec *Decoder, p unsafe.Pointer, tag byte) {
+		v := (*sql.NullInt64)(p)
+		if v.Valid = tag != TagNull; v.Valid {
+			dec.decodeInt64(int64Type, tag, &v.Int64)
+		} else {
+			v.Int64 = 0
+		}
+	}))
+	RegisterValueDecoder(sql.NullFloat64{}, nullableDecoder(func(dec *Decoder, p unsafe.Pointer, tag byte) {
+		v := (*sql.NullFloat64)(p)
+		if v.Valid = tag != TagNull; v.Valid {
+			dec.decodeFloat64(float64Type, tag, &v.Float64)
+		} else {
+			v.Float64 = 0
+		}
+	}))
+	RegisterValueDecoder(sql.NullString{}, nullableDecoder(func(dec *Decoder, p unsafe.Pointer, tag byte) {
+		v := (*sql.NullString)(p)
+		if v.Valid = tag != TagNull; v.Valid {
+			dec.decodeString(stringType, tag, &v.String)
+		} else {
+			v.String = ""
+		}
+	}))
+	RegisterValueDecoder(sql.NullTime{}, nullableDecoder(func(dec *Decoder, p unsafe.Pointer, tag byte) {
+		v := (*sql.NullTime)(p)
+		if v.Valid = tag != TagNull; v.Valid {
+			dec.decodeTime(timeType, tag, &v.Time)
+		} else {
+			v.Time = time.Time{}
+		}
+	}))
+}
@@ -0,0 +1,81 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/sql_decoder_test.go                                   |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeSQLNull(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	date := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	enc.Encode(true)
+	enc.Encode(32)
+	enc.Encode(64)
+	enc.Encode(1.5)
+	enc.Encode("hello")
+	enc.Encode("hello")
+	enc.Encode(date)
+	enc.Encode(date)
+	enc.Encode(nil)
+	enc.Encode("")
+	dec := NewDecoder(([]byte)(sb.String())).Simple(false)
+	var b sql.NullBool
+	dec.Decode(&b)
+	assert.Equal(t, sql.NullBool{Bool: true, Valid: true}, b)
+	var i32 sql.NullInt32
+	dec.Decode(&i32)
+	assert.Equal(t, sql.NullInt32{Int32: 32, Valid: true}, i32)
+	var i64 sql.NullInt64
+	dec.Decode(&i64)
+	assert.Equal(t, sql.NullInt64{Int64: 64, Valid: true}, i64)
+	var f sql.NullFloat64
+	dec.Decode(&f)
+	assert.Equal(t, sql.NullFloat64{Float64: 1.5, Valid: true}, f)
+	var s sql.NullString
+	dec.Decode(&s)
+	assert.Equal(t, sql.NullString{String: "hello", Valid: true}, s)
+	var ps *sql.NullString
+	dec.Decode(&ps)
+	assert.Equal(t, &sql.NullString{String: "hello", Valid: true}, ps)
+	var tm sql.NullTime
+	dec.Decode(&tm)
+	assert.Equal(t, sql.NullTime{Time: date, Valid: true}, tm)
+	dec.Decode(&tm)
+	assert.Equal(t, sql.NullTime{Time: date, Valid: true}, tm)
+	dec.Decode(&s)
+	assert.Equal(t, sql.NullString{}, s)
+	dec.Decode(&s)
+	assert.Equal(t, sql.NullString{Valid: true}, s)
+	assert.NoError(t, dec.Error)
+}
+
+func TestSQLNullField(t *testing.T) {
+	type Row struct {
+		Name sql.NullString
+		Age  sql.NullInt64
+	}
+	data, err := Marshal(Row{Name: sql.NullString{String: "Tom", Valid: true}})
+	assert.NoError(t, err)
+	var row Row
+	assert.NoError(t, Unmarshal(data, &row))
+	assert.Equal(t, Row{Name: sql.NullString{String: "Tom", Valid: true}}, row)
+}
@@ -0,0 +1,84 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/sql_encoder.go                                        |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"database/sql"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// nullableEncoder is the implementation of ValueEncoder for the sql.Null*
+// types, the valid values are encoded as their underlying values and the
+// invalid values are encoded as null.
+type nullableEncoder func(enc *Encoder, p unsafe.Pointer)
+
+func (valenc nullableEncoder) Encode(enc *Encoder, v interface{}) {
+	if isNil(v) {
+		enc.WriteNil()
+	} else {
+		valenc(enc, reflect2.PtrOf(v))
+	}
+}
+
+func (valenc nullableEncoder) Write(enc *Encoder, v interface{}) {
+	valenc.Encode(enc, v)
+}
+
+func init() {
+	RegisterValueEncoder((*sql.NullBool)(nil), nullableEncoder(func(enc *Encoder, p unsafe.Pointer) {
+		if v := (*sql.NullBool)(p); v.Valid {
+			enc.WriteBool(v.Bool)
+		} else {
+			enc.WriteNil()
+		}
+	}))
+	RegisterValueEncoder((*sql.NullInt32)(nil), nullableEncoder(func(enc *Encoder, p unsafe.Pointer) {
+		if v := (*sql.NullInt32)(p); v.Valid {
+			enc.WriteInt32(v.Int32)
+		} else {
+			enc.WriteNil()
+		}
+	}))
+	RegisterValueEncoder((*sql.NullInt64)(nil), nullableEncoder(func(enc *Encoder, p unsafe.Pointer) {
+		if v := (*sql.NullInt64)(p); v.Valid {
+			enc.WriteInt64(v.Int64)
+		} else {
+			enc.WriteNil()
+		}
+	}))
+	RegisterValueEncoder((*sql.NullFloat64)(nil), nullableEncoder(func(enc *Encoder, p unsafe.Pointer) {
+		if v := (*sql.NullFloat64)(p); v.Valid {
+			enc.WriteFloat64(v.Float64)
+		} else {
+			enc.WriteNil()
+		}
+	}))
+	RegisterValueEncoder((*sql.NullString)(nil), nullableEncoder(func(enc *Encoder, p unsafe.Pointer) {
+		if v := (*sql.NullString)(p); v.Valid {
+			enc.EncodeString(v.String)
+		} else {
+			enc.WriteNil()
+		}
+	}))
+	RegisterValueEncoder((*sql.NullTime)(nil), nullableEncoder(func(enc *Encoder, p unsafe.Pointer) {
+		if v := (*sql.NullTime)(p); v.Valid {
+			enc.AddReferenceCount(1)
+			enc.writeTime(v.Time)
+		} else {
+			enc.WriteNil()
+		}
+	}))
+}
@@ -0,0 +1,40 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/sql_encoder_test.go                                   |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeSQLNull(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	assert.NoError(t, enc.Encode(sql.NullBool{Bool: true, Valid: true}))
+	assert.NoError(t, enc.Encode(sql.NullInt32{Int32: 32, Valid: true}))
+	assert.NoError(t, enc.Encode(sql.NullInt64{Int64: 64, Valid: true}))
+	assert.NoError(t, enc.Encode(sql.NullFloat64{Float64: 1.5, Valid: true}))
+	assert.NoError(t, enc.Encode(sql.NullString{String: "hello", Valid: true}))
+	assert.NoError(t, enc.Encode(&sql.NullString{String: "hello", Valid: true}))
+	assert.NoError(t, enc.Encode(sql.NullTime{Time: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true}))
+	assert.NoError(t, enc.Encode(sql.NullString{String: "ignored"}))
+	assert.NoError(t, enc.Encode(sql.NullInt64{}))
+	assert.NoError(t, enc.Encode((*sql.NullInt64)(nil)))
+	assert.Equal(t, `ti32;l64;d1.5;s5"hello"r0;D20200102Znnn`, sb.String())
+}
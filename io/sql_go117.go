@@ -0,0 +1,55 @@
+//go:build go1.17
+// +build go1.17
+
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/sql_go117.go                                          |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"database/sql"
+	"unsafe"
+)
+
+func init() {
+	RegisterValueEncoder((*sql.NullByte)(nil), nullableEncoder(func(enc *Encoder, p unsafe.Pointer) {
+		if v := (*sql.NullByte)(p); v.Valid {
+			enc.WriteUint8(v.Byte)
+		} else {
+			enc.WriteNil()
+		}
+	}))
+	RegisterValueEncoder((*sql.NullInt16)(nil), nullableEncoder(func(enc *Encoder, p unsafe.Pointer) {
+		if v := (*sql.NullInt16)(p); v.Valid {
+			enc.WriteInt16(v.Int16)
+		} else {
+			enc.WriteNil()
+		}
+	}))
+	RegisterValueDecoder(sql.NullByte{}, nullableDecoder(func(dec *Decoder, p unsafe.Pointer, tag byte) {
+		v := (*sql.NullByte)(p)
+		if v.Valid = tag != TagNull; v.Valid {
+			dec.decodeUint8(uint8Type, tag, &v.Byte)
+		} else {
+			v.Byte = 0
+		}
+	}))
+	RegisterValueDecoder(sql.NullInt16{}, nullableDecoder(func(dec *Decoder, p unsafe.Pointer, tag byte) {
+		v := (*sql.NullInt16)(p)
+		if v.Valid = tag != TagNull; v.Valid {
+			dec.decodeInt16(int16Type, tag, &v.Int16)
+		} else {
+			v.Int16 = 0
+		}
+	}))
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | io/string_decoder.go                                     |
 |                                                          |
-| LastModified: Mar 5, 2022                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -30,7 +30,8 @@ func (dec *Decoder) checkUTF8String(buf []byte, off, utf16Length int) (int, int,
 	case 14:
 		off += 3
 	case 15:
-		if b&8 == 8 {
+		// a surrogate pair needs two utf16 units.
+		if b&8 == 8 || utf16Length < 2 {
 			if dec.Error == nil {
 				dec.Error = ErrInvalidUTF8
 			}
@@ -110,14 +111,12 @@ func (dec *Decoder) readStringAsSafeBytes(utf16Length int) []byte {
 	if safe {
 		return data
 	}
-	result := make([]byte, len(data))
-	copy(result, data)
-	return result
+	return dec.arenaCopy(data)
 }
 
 // ReadStringAsBytes reads string as bytes.
 func (dec *Decoder) ReadStringAsBytes() (data []byte) {
-	data = dec.readStringAsSafeBytes(dec.ReadInt())
+	data = dec.readStringAsSafeBytes(dec.readLength())
 	dec.Skip()
 	return
 }
@@ -135,22 +134,22 @@ func (dec *Decoder) readSafeString(utf16Length int) (s string) {
 	if data == nil {
 		return
 	}
-	if safe {
+	if safe && dec.Interner == nil {
 		return convert.ToUnsafeString(data)
 	}
-	return string(data)
+	return dec.copyString(data)
 }
 
 // ReadUnsafeString reads unsafe string.
 func (dec *Decoder) ReadUnsafeString() (s string) {
-	s = dec.readUnsafeString(dec.ReadInt())
+	s = dec.readUnsafeString(dec.readLength())
 	dec.Skip()
 	return
 }
 
 // ReadSafeString reads safe string.
 func (dec *Decoder) ReadSafeString() (s string) {
-	s = dec.readSafeString(dec.ReadInt())
+	s = dec.readSafeString(dec.readLength())
 	dec.Skip()
 	return
 }
@@ -158,12 +157,29 @@ func (dec *Decoder) ReadSafeString() (s string) {
 // ReadString reads safe string and add reference.
 func (dec *Decoder) ReadString() (s string) {
 	s = dec.ReadSafeString()
-	if !dec.IsSimple() {
-		dec.refer.Add(s)
-	}
+	dec.AddReference(s)
 	return
 }
 
+// decodeText reads the value of the types which are encoded as strings, it
+// returns false if tag is not a string or bytes tag and has been handled.
+func (dec *Decoder) decodeText(t reflect.Type, tag byte, p interface{}) (s string, ok bool) {
+	switch tag {
+	case TagUTF8Char:
+		return dec.readSafeString(1), true
+	case TagString:
+		if dec.IsSimple() {
+			return dec.ReadSafeString(), true
+		}
+		return dec.ReadString(), true
+	case TagBytes:
+		return convert.ToUnsafeString(dec.ReadBytes()), true
+	default:
+		dec.defaultDecode(t, p, tag)
+		return "", false
+	}
+}
+
 func (dec *Decoder) decodeString(t reflect.Type, tag byte, p *string) {
 	switch tag {
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
@@ -190,10 +206,8 @@ func (dec *Decoder) decodeString(t reflect.Type, tag byte, p *string) {
 		*p = dec.ReadString()
 	case TagBytes:
 		*p = convert.ToUnsafeString(dec.ReadBytes())
-	case TagTime:
-		*p = dec.ReadTime().String()
-	case TagDate:
-		*p = dec.ReadDateTime().String()
+	case TagTime, TagDate:
+		dec.convertTime(tag, p)
 	case TagGUID:
 		*p = dec.ReadUUID().String()
 	default:
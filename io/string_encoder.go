@@ -6,7 +6,7 @@
 |                                                          |
 | io/string_encoder.go                                     |
 |                                                          |
-| LastModified: Feb 18, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -14,6 +14,8 @@
 package io
 
 import (
+	"unsafe"
+
 	"github.com/modern-go/reflect2"
 )
 
@@ -52,3 +54,29 @@ func (enc *Encoder) WriteString(s string) {
 	enc.SetStringReference(s)
 	enc.buf = appendString(enc.buf, s, utf16Length(s))
 }
+
+// textEncoder is the implementation of ValueEncoder for the types which are
+// encoded as strings, it returns false when the value p points to is null.
+type textEncoder func(p unsafe.Pointer) (s string, ok bool)
+
+func (valenc textEncoder) Encode(enc *Encoder, v interface{}) {
+	if isNil(v) {
+		enc.WriteNil()
+	} else if s, ok := valenc(reflect2.PtrOf(v)); ok {
+		enc.EncodeString(s)
+	} else {
+		enc.WriteNil()
+	}
+}
+
+func (valenc textEncoder) Write(enc *Encoder, v interface{}) {
+	if isNil(v) {
+		enc.WriteNil()
+	} else if s, ok := valenc(reflect2.PtrOf(v)); !ok {
+		enc.WriteNil()
+	} else if s == "" {
+		enc.buf = append(enc.buf, TagEmpty)
+	} else {
+		enc.WriteString(s)
+	}
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | io/struct_decoder.go                                     |
 |                                                          |
-| LastModified: Feb 18, 2024                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -16,7 +16,6 @@ package io
 import (
 	"reflect"
 	"sync"
-	"unsafe"
 
 	"github.com/modern-go/reflect2"
 )
@@ -24,15 +23,14 @@ import (
 func (dec *Decoder) readObjectAsMap(structInfo structInfo) map[string]interface{} {
 	m := make(map[string]interface{}, len(structInfo.names))
 	t := reflect2.TypeOf(m).(*reflect2.UnsafeMapType)
-	if !dec.IsSimple() {
-		dec.refer.Add(m)
-	}
+	dec.AddReference(m)
 	ptr := reflect2.PtrOf(&m)
 	for _, name := range structInfo.names {
 		var v interface{}
 		dec.decodeInterface(dec.NextByte(), &v)
 		t.UnsafeSetIndex(ptr, reflect2.PtrOf(name), reflect2.PtrOf(&v))
 	}
+	dec.leave()
 	dec.Skip()
 	return m
 }
@@ -49,6 +47,7 @@ func (dec *Decoder) readObject(structInfo structInfo) interface{} {
 			dec.decodeInterface(dec.NextByte(), &v)
 		}
 	}
+	dec.leave()
 	dec.Skip()
 	if dec.StructType == StructTypeValue {
 		return structInfo.t.UnsafeIndirect(ptr)
@@ -58,8 +57,14 @@ func (dec *Decoder) readObject(structInfo structInfo) interface{} {
 
 // ReadObject reads object and add reference.
 func (dec *Decoder) ReadObject() interface{} {
+	if !dec.enter() {
+		return nil
+	}
 	index := dec.ReadInt()
 	structInfo := dec.getStructInfo(index)
+	if structInfo.union != nil {
+		return dec.readUnion(structInfo)
+	}
 	if structInfo.fields == nil {
 		return dec.readObjectAsMap(structInfo)
 	}
@@ -73,47 +78,91 @@ type structDecoder struct {
 	sync.RWMutex
 }
 
-func (valdec *structDecoder) decodeField(dec *Decoder, ptr unsafe.Pointer, name string) {
-	valdec.RLock()
-	field, ok := valdec.fields[name]
-	valdec.RUnlock()
-	if ok {
-		field.Decode(dec, field.Type.Type1(), field.Field.UnsafeGet(ptr))
-	} else {
+// FieldDecoder is the interface that wraps the DecodeField method.
+//
+// DecodeField decodes the field of the struct that p points to by name,
+// it returns false if the field is unknown. It is used by the generated ValueDecoder.
+type FieldDecoder interface {
+	DecodeField(dec *Decoder, p interface{}, name string) bool
+}
+
+func (dec *Decoder) decodeField(fd FieldDecoder, p interface{}, name string) {
+	if !fd.DecodeField(dec, p, name) {
 		var v interface{}
 		dec.decodeInterface(dec.NextByte(), &v)
+	} else if dec.isMerging() {
+		dec.addPresentField(p, name)
 	}
 }
 
-func (valdec *structDecoder) decodeObject(dec *Decoder, p interface{}) {
+func (dec *Decoder) decodeObject(fd FieldDecoder, p interface{}) {
+	if !dec.enter() {
+		return
+	}
 	index := dec.ReadInt()
 	structInfo := dec.getStructInfo(index)
 	dec.AddReference(p)
-	ptr := reflect2.PtrOf(p)
+	if dec.isMerging() {
+		dec.resetPresentFields(p)
+	}
 	for _, name := range structInfo.names {
-		valdec.decodeField(dec, ptr, name)
+		dec.decodeField(fd, p, name)
 	}
+	dec.leave()
 	dec.Skip()
 }
 
-func (valdec *structDecoder) decodeMapAsObject(dec *Decoder, p interface{}) {
-	ptr := reflect2.PtrOf(p)
-	count := dec.ReadInt()
+func (dec *Decoder) decodeMapAsObject(fd FieldDecoder, p interface{}) {
+	if !dec.enter() {
+		return
+	}
+	count := dec.readCount(0)
 	dec.AddReference(p)
+	if dec.isMerging() {
+		dec.resetPresentFields(p)
+	}
 	for i := 0; i < count; i++ {
 		var name string
 		dec.decodeString(stringType, dec.NextByte(), &name)
-		valdec.decodeField(dec, ptr, name)
+		dec.decodeField(fd, p, name)
 	}
+	dec.leave()
 	dec.Skip()
 }
 
+// DecodeStruct decodes an object or a map to the struct of type t that p points to,
+// the fields are decoded by fd.
+func (dec *Decoder) DecodeStruct(t reflect.Type, fd FieldDecoder, p interface{}, tag byte) {
+	switch tag {
+	case TagObject:
+		dec.decodeObject(fd, p)
+	case TagMap:
+		dec.decodeMapAsObject(fd, p)
+	case TagEmpty:
+		t2 := reflect2.Type2(t)
+		t2.UnsafeSet(reflect2.PtrOf(p), t2.UnsafeNew())
+	default:
+		dec.defaultDecode(t, p, tag)
+	}
+}
+
+// DecodeField implements the FieldDecoder interface.
+func (valdec *structDecoder) DecodeField(dec *Decoder, p interface{}, name string) bool {
+	valdec.RLock()
+	field, ok := valdec.fields[name]
+	valdec.RUnlock()
+	if ok {
+		field.Decode(dec, field.Type.Type1(), field.Field.UnsafeGet(reflect2.PtrOf(p)))
+	}
+	return ok
+}
+
 func (valdec *structDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 	switch tag {
 	case TagObject:
-		valdec.decodeObject(dec, p)
+		dec.decodeObject(valdec, p)
 	case TagMap:
-		valdec.decodeMapAsObject(dec, p)
+		dec.decodeMapAsObject(valdec, p)
 	case TagEmpty:
 		valdec.t.UnsafeSet(reflect2.PtrOf(p), valdec.t.UnsafeNew())
 	default:
@@ -134,13 +183,14 @@ func getNamedStructDecoder(t reflect.Type) ValueDecoder {
 	return nil
 }
 
-func newNamedStructDecoder(t reflect.Type, tag ...string) *structDecoder {
+func newNamedStructDecoder(t reflect.Type, name string, tag ...string) *structDecoder {
 	t2 := reflect2.Type2(t).(*reflect2.UnsafeStructType)
 	decoder := &structDecoder{t: t2}
 	decoder.Lock()
 	defer decoder.Unlock()
 	registerNamedStructDecoder(t, decoder)
 	decoder.fields = getFieldMap(t, tag...)
+	registerRedactedFields(name, getFields(t, tag...))
 	return decoder
 }
 
@@ -155,5 +205,5 @@ func getStructDecoder(t reflect.Type) ValueDecoder {
 	if t.Name() == "" {
 		return newAnonymousStructDecoder(t)
 	}
-	return newNamedStructDecoder(t)
+	return newNamedStructDecoder(t, t.Name())
 }
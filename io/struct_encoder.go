@@ -6,7 +6,7 @@
 |                                                          |
 | io/struct_encoder.go                                     |
 |                                                          |
-| LastModified: Mar 5, 2022                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"unsafe"
 
 	"github.com/hprose/hprose-golang/v3/internal/convert"
 	"github.com/modern-go/reflect2"
@@ -37,8 +38,13 @@ func (valenc *structEncoder) Write(enc *Encoder, v interface{}) {
 	n := len(fields)
 	t := reflect.TypeOf(v)
 	st := t
-	if t.Kind() == reflect.Ptr {
+	isPtr := t.Kind() == reflect.Ptr
+	if isPtr {
 		st = t.Elem()
+		if !enc.enter(reflect2.PtrOf(v), t) {
+			enc.WriteNil()
+			return
+		}
 	} else if n == 1 {
 		v = toPtr(t, v)
 	}
@@ -53,6 +59,9 @@ func (valenc *structEncoder) Write(enc *Encoder, v interface{}) {
 		fields[i].Encode(enc, fields[i].Type.UnsafeIndirect(fields[i].Field.UnsafeGet(p)))
 	}
 	enc.WriteFoot()
+	if isPtr {
+		enc.leave(p, t)
+	}
 }
 
 func appendName(buf []byte, s string, message string) []byte {
@@ -82,12 +91,8 @@ func getNamedStructEncoder(t reflect.Type) ValueEncoder {
 	return nil
 }
 
-func newNamedStructEncoder(t reflect.Type, name string, tag ...string) *structEncoder {
-	encoder := &structEncoder{}
-	registerNamedStructEncoder(t, encoder)
-	fields := getFields(t, tag...)
-	n := len(fields)
-	var metadata []byte
+func makeMetadata(name string, aliases []string) (metadata []byte) {
+	n := len(aliases)
 	metadata = append(metadata, TagClass)
 	metadata = appendName(metadata, name, "struct name")
 	if n > 0 {
@@ -96,15 +101,78 @@ func newNamedStructEncoder(t reflect.Type, name string, tag ...string) *structEn
 	metadata = append(metadata, TagOpenbrace)
 	for i := 0; i < n; i++ {
 		metadata = append(metadata, TagString)
-		metadata = appendName(metadata, fields[i].Alias, "struct field name or alias")
+		metadata = appendName(metadata, aliases[i], "struct field name or alias")
 	}
 	metadata = append(metadata, TagClosebrace)
+	return
+}
+
+func newNamedStructEncoder(t reflect.Type, name string, tag ...string) *structEncoder {
+	encoder := &structEncoder{}
+	registerNamedStructEncoder(t, encoder)
+	fields := getFields(t, tag...)
+	aliases := make([]string, len(fields))
+	for i := range fields {
+		aliases[i] = fields[i].Alias
+	}
 	encoder.fields = fields
-	encoder.metadata = metadata
+	encoder.metadata = makeMetadata(name, aliases)
 	registerValueEncoder(t, encoder)
+	registerRedactedFields(name, fields)
 	return encoder
 }
 
+// StructMetadata is the struct type definition written before the first object of the struct.
+// It is used by the generated ValueEncoder.
+type StructMetadata struct {
+	t        reflect.Type
+	count    int
+	metadata []byte
+}
+
+// NewStructMetadata returns the StructMetadata of struct type t with name and field aliases.
+func NewStructMetadata(t reflect.Type, name string, aliases ...string) *StructMetadata {
+	registerRedactedFields(name, getFields(t))
+	return &StructMetadata{
+		t:        t,
+		count:    len(aliases),
+		metadata: makeMetadata(name, aliases),
+	}
+}
+
+// WriteStructHead writes the struct type definition if it has not been written,
+// sets the reference of v and writes the object head to encoder.
+// It returns the pointer to the struct value, the fields of the object and
+// WriteStructFoot should be called after it. If v is a pointer which is being
+// written, it writes nil and returns nil, and nothing else should be written.
+func (enc *Encoder) WriteStructHead(m *StructMetadata, v interface{}) unsafe.Pointer {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		if !enc.enter(reflect2.PtrOf(v), t) {
+			enc.WriteNil()
+			return nil
+		}
+	} else if m.count == 1 {
+		v = toPtr(t, v)
+	}
+	var r = enc.WriteStructType(m.t, func() {
+		enc.AddReferenceCount(m.count)
+		enc.buf = append(enc.buf, m.metadata...)
+	})
+	enc.SetReference(v)
+	enc.WriteObjectHead(r)
+	return reflect2.PtrOf(v)
+}
+
+// WriteStructFoot writes the object foot to encoder, v is the same value
+// passed to WriteStructHead.
+func (enc *Encoder) WriteStructFoot(v interface{}) {
+	enc.WriteFoot()
+	if t := reflect.TypeOf(v); t.Kind() == reflect.Ptr {
+		enc.leave(reflect2.PtrOf(v), t)
+	}
+}
+
 // anonymousStructEncoder is the implementation of ValueEncoder for anonymous struct/*struct.
 type anonymousStructEncoder struct {
 	fields []FieldAccessor
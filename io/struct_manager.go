@@ -6,7 +6,7 @@
 |                                                          |
 | io/struct_manager.go                                     |
 |                                                          |
-| LastModified: Feb 12, 2023                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -16,6 +16,7 @@ package io
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -31,6 +32,10 @@ type FieldAccessor struct {
 	Field  reflect2.StructField
 	Encode EncodeHandler
 	Decode DecodeHandler
+	// redact is true if the field has the redact option.
+	redact bool
+	// encrypt is true if the field has the encrypt option.
+	encrypt bool
 }
 
 func stripOptions(tag string) string {
@@ -45,6 +50,24 @@ func _fieldAlias(tag reflect.StructTag, tagname string) string {
 	return strings.Trim(stripOptions(tag.Get(tagname)), " ")
 }
 
+func hasOption(tag reflect.StructTag, option string, tags []string) bool {
+	if len(tags) == 0 {
+		tags = defaultTags
+	}
+	for _, tagname := range tags {
+		if tagname == "" {
+			continue
+		}
+		options := strings.Split(tag.Get(tagname), ",")
+		for _, o := range options[1:] {
+			if strings.Trim(o, " ") == option {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func fieldAlias(tag reflect.StructTag, name string, tags []string) string {
 	if len(tags) == 0 {
 		tags = defaultTags
@@ -102,6 +125,13 @@ func _getFields(t reflect2.StructType, tags []string, mapping map[string]struct{
 		if field.Decode = GetDecodeHandler(typ); field.Decode == nil {
 			continue
 		}
+		if field.encrypt = hasOption(f.Tag(), "encrypt", tags); field.encrypt {
+			field.Encode = encryptEncodeHandler(field.Encode)
+			field.Decode = encryptDecodeHandler(field.Decode)
+		}
+		if field.redact = hasOption(f.Tag(), "redact", tags); field.redact {
+			field.Encode = redactEncodeHandler(field.Encode)
+		}
 
 		mapping[name] = struct{}{}
 		fields = append(fields, field)
@@ -113,6 +143,15 @@ func getFields(t reflect.Type, tag ...string) []FieldAccessor {
 	return _getFields(reflect2.Type2(t).(reflect2.StructType), tag, map[string]struct{}{}, nil)
 }
 
+func getField(t reflect.Type, alias string, tag ...string) (field FieldAccessor, ok bool) {
+	for _, field = range getFields(t, tag...) {
+		if field.Alias == alias {
+			return field, true
+		}
+	}
+	return field, false
+}
+
 var structFieldMapCache sync.Map
 
 func getFieldMap(t reflect.Type, tag ...string) map[string]FieldAccessor {
@@ -133,11 +172,19 @@ type structInfo struct {
 	names  []string
 	t      *reflect2.UnsafeStructType
 	fields map[string]FieldAccessor
+	// union is the type registered by RegisterUnion.
+	union reflect.Type
+	// keys are the field names read by the Value decoder.
+	keys []*Value
 }
 
 func makeStructInfo(name string, names []string, t reflect.Type) (info structInfo) {
 	info.name = name
 	info.names = names
+	if union := GetUnionType(name); union != nil && len(names) == 1 {
+		info.union = union
+		return
+	}
 	typ := GetStructType(name)
 	if typ == nil {
 		for t.Kind() == reflect.Ptr {
@@ -157,7 +204,7 @@ func makeStructInfo(name string, names []string, t reflect.Type) (info structInf
 // ReadStruct reads struct type.
 func (dec *Decoder) ReadStruct(t reflect.Type) {
 	name := dec.ReadSafeString()
-	count := dec.ReadInt()
+	count := dec.readCount(int(stringType.Size()))
 	names := make([]string, count)
 	for i := 0; i < count; i++ {
 		dec.decodeString(stringType, dec.NextByte(), &names[i])
@@ -167,6 +214,12 @@ func (dec *Decoder) ReadStruct(t reflect.Type) {
 }
 
 func (dec *Decoder) getStructInfo(index int) structInfo {
+	if index < 0 || index >= len(dec.ref) {
+		if dec.Error == nil {
+			dec.Error = DecodeError("hprose/io: invalid struct index " + strconv.Itoa(index))
+		}
+		return structInfo{}
+	}
 	return dec.ref[index]
 }
 
@@ -196,7 +249,7 @@ func RegisterName(alias string, proto interface{}, tag ...string) {
 		newAnonymousStructDecoder(t, tag...)
 	} else {
 		newNamedStructEncoder(t, name, tag...)
-		newNamedStructDecoder(t, tag...)
+		newNamedStructDecoder(t, name, tag...)
 	}
 }
 
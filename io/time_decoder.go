@@ -6,7 +6,7 @@
 |                                                          |
 | io/time_decoder.go                                       |
 |                                                          |
-| LastModified: May 27, 2022                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -97,6 +97,13 @@ func (dec *Decoder) readNsec() (nsec int, tag byte) {
 	return
 }
 
+func (dec *Decoder) location() *time.Location {
+	if dec.Location != nil {
+		return dec.Location
+	}
+	return time.Local
+}
+
 func (dec *Decoder) readTime(p *time.Time) {
 	hour := dec.read2Digit()
 	min := dec.read2Digit()
@@ -106,7 +113,7 @@ func (dec *Decoder) readTime(p *time.Time) {
 	if tag == TagPoint {
 		nsec, tag = dec.readNsec()
 	}
-	loc := time.Local
+	loc := dec.location()
 	if tag == TagUTC {
 		loc = time.UTC
 	}
@@ -116,9 +123,7 @@ func (dec *Decoder) readTime(p *time.Time) {
 // ReadTime reads time.Time and add reference.
 func (dec *Decoder) ReadTime() (t time.Time) {
 	dec.readTime(&t)
-	if !dec.IsSimple() {
-		dec.refer.Add(t)
-	}
+	dec.AddReference(t)
 	return
 }
 
@@ -137,7 +142,7 @@ func (dec *Decoder) readDateTime(p *time.Time) {
 			nsec, tag = dec.readNsec()
 		}
 	}
-	loc := time.Local
+	loc := dec.location()
 	if tag == TagUTC {
 		loc = time.UTC
 	}
@@ -147,12 +152,31 @@ func (dec *Decoder) readDateTime(p *time.Time) {
 // ReadDateTime reads time.Time and add reference.
 func (dec *Decoder) ReadDateTime() (t time.Time) {
 	dec.readDateTime(&t)
-	if !dec.IsSimple() {
-		dec.refer.Add(t)
-	}
+	dec.AddReference(t)
 	return
 }
 
+// convertTime reads time.Time, adds reference and converts it to p by the
+// converter registered for time.Time.
+func (dec *Decoder) convertTime(tag byte, p interface{}) {
+	var t time.Time
+	if tag == TagTime {
+		dec.readTime(&t)
+	} else {
+		dec.readDateTime(&t)
+	}
+	dec.AddReference(t)
+	dest := reflect.TypeOf(p).Elem()
+	if conv := GetConverter(timeType, dest); conv != nil {
+		conv(dec, t, p)
+	} else if dec.Error == nil {
+		dec.Error = CastError{
+			Source:      timeType,
+			Destination: dest,
+		}
+	}
+}
+
 func (dec *Decoder) decodeTime(t reflect.Type, tag byte, p *time.Time) {
 	if i := intDigits[tag]; i != invalidDigit {
 		*p = time.Unix(0, int64(i))
@@ -169,14 +193,10 @@ func (dec *Decoder) decodeTime(t reflect.Type, tag byte, p *time.Time) {
 		*p = time.Unix(0, int64(dec.ReadFloat64()))
 	case TagTime:
 		dec.readTime(p)
-		if !dec.IsSimple() {
-			dec.refer.Add(*p)
-		}
+		dec.AddReference(*p)
 	case TagDate:
 		dec.readDateTime(p)
-		if !dec.IsSimple() {
-			dec.refer.Add(*p)
-		}
+		dec.AddReference(*p)
 	case TagString:
 		if dec.IsSimple() {
 			*p = dec.stringToTime(dec.ReadUnsafeString())
@@ -207,4 +227,7 @@ func (timeDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
 
 func init() {
 	registerValueDecoder(timeType, timeDecoder{})
+	RegisterConverter(timeType, int64Type, func(dec *Decoder, o interface{}, p interface{}) {
+		*(*int64)(reflect2.PtrOf(p)) = o.(time.Time).UnixNano()
+	})
 }
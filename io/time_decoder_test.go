@@ -6,7 +6,7 @@
 |                                                          |
 | io/time_decoder_test.go                                  |
 |                                                          |
-| LastModified: May 27, 2022                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -14,6 +14,7 @@
 package io_test
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -76,3 +77,54 @@ func TestDecodeTime(t *testing.T) {
 	dec.Decode(&t6)
 	assert.Equal(t, t6, Time(time.Unix(0, 0)))
 }
+
+func TestDecodeTimeLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	dec := NewDecoder(([]byte)(`D20210605T185732;D20210605T185732ZT185732;`))
+	dec.Location = loc
+	var t1, t2, t3 time.Time
+	dec.Decode(&t1)
+	dec.Decode(&t2)
+	dec.Decode(&t3)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, time.Date(2021, 6, 5, 18, 57, 32, 0, loc), t1)
+	assert.Equal(t, time.Date(2021, 6, 5, 18, 57, 32, 0, time.UTC), t2)
+	assert.Equal(t, time.Date(1970, 1, 1, 18, 57, 32, 0, loc), t3)
+
+	var t4 time.Time
+	assert.NoError(t, Formatter{Simple: true, Location: loc}.Unmarshal(([]byte)(`D20210605;`), &t4))
+	assert.Equal(t, time.Date(2021, 6, 5, 0, 0, 0, 0, loc), t4)
+}
+
+func TestDecodeTimeToInt64(t *testing.T) {
+	t1 := time.Date(2021, 6, 5, 18, 57, 32, 0, time.UTC)
+	dec := NewDecoder(([]byte)(`D20210605T185732Za2{D20210605T185732Zr2;}`)).Simple(false)
+	var i int64
+	var a []int64
+	dec.Decode(&i)
+	dec.Decode(&a)
+	assert.NoError(t, dec.Error)
+	assert.Equal(t, t1.UnixNano(), i)
+	assert.Equal(t, []int64{t1.UnixNano(), t1.UnixNano()}, a)
+
+	var n int8
+	dec = NewDecoder(([]byte)(`D20210605T185732Z`))
+	dec.Decode(&n)
+	assert.EqualError(t, dec.Error, `hprose/io: can not cast time.Time to int8`)
+}
+
+func TestDecodeTimeToString(t *testing.T) {
+	t1 := time.Date(2021, 6, 5, 18, 57, 32, 0, time.UTC)
+	var s string
+	assert.NoError(t, Unmarshal(([]byte)(`D20210605T185732Z`), &s))
+	assert.Equal(t, t1.String(), s)
+
+	timeType, stringType := reflect.TypeOf(t1), reflect.TypeOf("")
+	converter := GetConverter(timeType, stringType)
+	defer RegisterConverter(timeType, stringType, converter)
+	RegisterConverter(timeType, stringType, func(dec *Decoder, o interface{}, p interface{}) {
+		*(p.(*string)) = o.(time.Time).Format(time.RFC3339)
+	})
+	assert.NoError(t, Unmarshal(([]byte)(`D20210605T185732Z`), &s))
+	assert.Equal(t, "2021-06-05T18:57:32Z", s)
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | io/time_encoder.go                                       |
 |                                                          |
-| LastModified: Feb 18, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -19,6 +19,21 @@ import (
 	"github.com/modern-go/reflect2"
 )
 
+// TimeZoneMode represents how the encoder writes time.Time which is not in UTC.
+type TimeZoneMode int8
+
+const (
+	// TimeZoneLocal represents the time is written as a local time without
+	// time zone, the decoder reads it in its own location.
+	TimeZoneLocal TimeZoneMode = iota
+	// TimeZoneUTC represents the time is converted to UTC before writing,
+	// so the decoder reads the same instant in any location.
+	TimeZoneUTC
+	// TimeZoneOffset represents the time is written as a RFC 3339 string
+	// with its offset, such as "2006-01-02T15:04:05+07:00".
+	TimeZoneOffset
+)
+
 // timeEncoder is the implementation of ValueEncoder for time.Time/*time.Time.
 type timeEncoder struct{}
 
@@ -75,6 +90,15 @@ func (enc *Encoder) writeTimePart(hour int, min int, sec int, nsec int) {
 }
 
 func (enc *Encoder) writeTime(t time.Time) {
+	switch {
+	case enc.canonical, enc.TimeZoneMode == TimeZoneUTC:
+		t = t.UTC()
+	case enc.TimeZoneMode == TimeZoneOffset && t.Location() != time.UTC:
+		// the reference has been counted for t.
+		s := t.Format(time.RFC3339Nano)
+		enc.buf = appendString(enc.buf, s, len(s))
+		return
+	}
 	year, month, day := t.Date()
 	hour, min, sec := t.Clock()
 	nsec := t.Nanosecond()
@@ -6,7 +6,7 @@
 |                                                          |
 | io/time_encoder_test.go                                  |
 |                                                          |
-| LastModified: Jun 05, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -57,3 +57,50 @@ func TestEncodeTime(t *testing.T) {
 	assert.NoError(t, enc.Encode(t2))
 	assert.Equal(t, `D20210605T185732ZD20210605T185732Zr0;D20210605T185732Z`, sb.String())
 }
+
+func TestEncodeTimeZoneMode(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	t1 := time.Date(2021, 6, 5, 18, 57, 32, 0, loc)
+
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb)
+	assert.NoError(t, enc.Encode(t1))
+	assert.Equal(t, `D20210605T185732;`, sb.String())
+
+	sb.Reset()
+	enc.TimeZoneMode = TimeZoneUTC
+	assert.NoError(t, enc.Encode(t1))
+	assert.Equal(t, `D20210605T105732Z`, sb.String())
+
+	sb.Reset()
+	enc.TimeZoneMode = TimeZoneOffset
+	assert.NoError(t, enc.Encode(t1))
+	assert.NoError(t, enc.Encode(t1.UTC()))
+	assert.Equal(t, `s25"2021-06-05T18:57:32+08:00"D20210605T105732Z`, sb.String())
+
+	sb.Reset()
+	enc = NewEncoder(sb).Simple(false)
+	enc.TimeZoneMode = TimeZoneOffset
+	assert.NoError(t, enc.Encode(&t1))
+	assert.NoError(t, enc.Encode(&t1))
+	assert.NoError(t, enc.Encode("hello"))
+	assert.NoError(t, enc.Encode("hello"))
+	assert.Equal(t, `s25"2021-06-05T18:57:32+08:00"r0;s5"hello"r1;`, sb.String())
+
+	var t2 time.Time
+	assert.NoError(t, Unmarshal([]byte(`s25"2021-06-05T18:57:32+08:00"`), &t2))
+	assert.True(t, t1.Equal(t2))
+	_, offset := t2.Zone()
+	assert.Equal(t, 8*3600, offset)
+}
+
+func TestFormatterTimeZoneMode(t *testing.T) {
+	t1 := time.Date(2021, 6, 5, 18, 57, 32, 0, time.FixedZone("UTC+8", 8*3600))
+	data, err := Formatter{Simple: true, TimeZoneMode: TimeZoneUTC}.Marshal(t1)
+	assert.NoError(t, err)
+	assert.Equal(t, `D20210605T105732Z`, string(data))
+	enc := GetEncoder()
+	enc.TimeZoneMode = TimeZoneUTC
+	FreeEncoder(enc)
+	assert.Equal(t, TimeZoneLocal, GetEncoder().TimeZoneMode)
+}
@@ -0,0 +1,511 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/transcode/cbor.go                                     |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package transcode
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCBOR is returned when the CBOR data is malformed.
+var ErrInvalidCBOR = errors.New("hprose/transcode: invalid CBOR data")
+
+// CBOR major types.
+const (
+	cborUint byte = iota << 5
+	cborNegInt
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// CBOR tags.
+const (
+	cborTagDateTime  = 0
+	cborTagEpoch     = 1
+	cborTagPosBignum = 2
+	cborTagNegBignum = 3
+	cborTagShareable = 28
+	cborTagSharedRef = 29
+	cborTagUUID      = 37
+)
+
+const cborBreak = 0xff
+
+// ToCBOR converts hprose data to CBOR.
+func (t Transcoder) ToCBOR(data []byte) ([]byte, error) {
+	v, shared, err := t.readHprose(data)
+	if err != nil {
+		return nil, err
+	}
+	w := cborWriter{Transcoder: t, shared: shared, index: make(map[interface{}]uint64)}
+	w.write(v)
+	return w.buf, nil
+}
+
+// FromCBOR converts CBOR data to hprose.
+func (t Transcoder) FromCBOR(data []byte) ([]byte, error) {
+	r := cborReader{Transcoder: t, data: data, depth: depth{max: t.MaxDepth}, pending: -1}
+	v, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	if r.pos < len(data) {
+		return nil, ErrTrailingData
+	}
+	return writeHprose(v), nil
+}
+
+type cborWriter struct {
+	Transcoder
+	buf    []byte
+	shared map[interface{}]bool
+	index  map[interface{}]uint64
+}
+
+func (w *cborWriter) writeHead(major byte, n uint64) {
+	switch {
+	case n < 24:
+		w.buf = append(w.buf, major|byte(n))
+	case n <= math.MaxUint8:
+		w.buf = append(w.buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		w.buf = appendUint16(append(w.buf, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		w.buf = appendUint32(append(w.buf, major|26), uint32(n))
+	default:
+		w.buf = appendUint64(append(w.buf, major|27), n)
+	}
+}
+
+func (w *cborWriter) writeInt(v int64) {
+	if v >= 0 {
+		w.writeHead(cborUint, uint64(v))
+	} else {
+		w.writeHead(cborNegInt, uint64(-1-v))
+	}
+}
+
+func (w *cborWriter) writeBigInt(v *big.Int) {
+	switch {
+	case v.IsInt64():
+		w.writeInt(v.Int64())
+	case v.IsUint64():
+		w.writeHead(cborUint, v.Uint64())
+	case v.Sign() > 0:
+		w.writeHead(cborTag, cborTagPosBignum)
+		w.writeBytes(cborBytes, v.Bytes())
+	default:
+		n := new(big.Int).Neg(v)
+		n.Sub(n, big.NewInt(1))
+		w.writeHead(cborTag, cborTagNegBignum)
+		w.writeBytes(cborBytes, n.Bytes())
+	}
+}
+
+func (w *cborWriter) writeBytes(major byte, data []byte) {
+	w.writeHead(major, uint64(len(data)))
+	w.buf = append(w.buf, data...)
+}
+
+// writeShared writes the sharedref if v has been written, otherwise it
+// writes the shareable tag for v if v is referenced more than once.
+func (w *cborWriter) writeShared(v interface{}) bool {
+	if !w.shared[v] {
+		return false
+	}
+	if i, ok := w.index[v]; ok {
+		w.writeHead(cborTag, cborTagSharedRef)
+		w.writeHead(cborUint, i)
+		return true
+	}
+	w.index[v] = uint64(len(w.index))
+	w.writeHead(cborTag, cborTagShareable)
+	return false
+}
+
+func (w *cborWriter) write(v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		w.buf = append(w.buf, cborSimple|22)
+	case bool:
+		if v {
+			w.buf = append(w.buf, cborSimple|21)
+		} else {
+			w.buf = append(w.buf, cborSimple|20)
+		}
+	case int64:
+		w.writeInt(v)
+	case uint64:
+		w.writeHead(cborUint, v)
+	case *big.Int:
+		w.writeBigInt(v)
+	case float64:
+		w.buf = appendUint64(append(w.buf, cborSimple|27), math.Float64bits(v))
+	case string:
+		w.writeBytes(cborText, []byte(v))
+	case []byte:
+		w.writeBytes(cborBytes, v)
+	case time.Time:
+		w.writeHead(cborTag, cborTagDateTime)
+		w.writeBytes(cborText, []byte(v.Format(time.RFC3339Nano)))
+	case uuid.UUID:
+		w.writeHead(cborTag, cborTagUUID)
+		w.writeBytes(cborBytes, v[:])
+	case *list:
+		if !w.writeShared(v) {
+			w.writeHead(cborArray, uint64(len(v.items)))
+			for _, item := range v.items {
+				w.write(item)
+			}
+		}
+	case *mapping:
+		if !w.writeShared(v) {
+			w.writeMap(v)
+		}
+	case *object:
+		if !w.writeShared(v) {
+			w.writeMap(w.toMapping(v))
+		}
+	}
+}
+
+func (w *cborWriter) writeMap(m *mapping) {
+	w.writeHead(cborMap, uint64(len(m.keys)))
+	for i, key := range m.keys {
+		w.write(key)
+		w.write(m.values[i])
+	}
+}
+
+type cborReader struct {
+	Transcoder
+	data   []byte
+	pos    int
+	depth  depth
+	shared []interface{}
+	// pending is the index of the shareable value being read, or -1.
+	pending int
+}
+
+func (r *cborReader) take(n uint64) ([]byte, error) {
+	if n > uint64(len(r.data)-r.pos) {
+		return nil, ErrInvalidCBOR
+	}
+	data := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return data, nil
+}
+
+// readHead returns the major type, the additional information and the
+// argument of the data item head.
+func (r *cborReader) readHead() (major byte, info byte, arg uint64, err error) {
+	data, err := r.take(1)
+	if err != nil {
+		return
+	}
+	major, info = data[0]&0xe0, data[0]&0x1f
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info <= 27:
+		if data, err = r.take(1 << (info - 24)); err != nil {
+			return
+		}
+		for _, b := range data {
+			arg = arg<<8 | uint64(b)
+		}
+	case info == 31:
+		if major == cborUint || major == cborNegInt || major == cborTag {
+			err = ErrInvalidCBOR
+		}
+	default:
+		err = ErrInvalidCBOR
+	}
+	return
+}
+
+// readLength returns the length of string, array or map, or -1 for the indefinite length.
+func (r *cborReader) readLength(info byte, arg uint64, min int) (int, error) {
+	if info == 31 {
+		return -1, nil
+	}
+	if arg > uint64((len(r.data)-r.pos)/min) {
+		return 0, ErrInvalidCBOR
+	}
+	return int(arg), nil
+}
+
+func (r *cborReader) isBreak() bool {
+	if r.pos < len(r.data) && r.data[r.pos] == cborBreak {
+		r.pos++
+		return true
+	}
+	return false
+}
+
+// share registers the container if it is the pending shareable value.
+func (r *cborReader) share(v interface{}) int {
+	i := r.pending
+	if i >= 0 {
+		r.shared[i] = v
+		r.pending = -1
+	}
+	return i
+}
+
+func (r *cborReader) read() (interface{}, error) {
+	major, info, arg, err := r.readHead()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		if arg > math.MaxInt64 {
+			return new(big.Int).SetUint64(arg), nil
+		}
+		return int64(arg), nil
+	case cborNegInt:
+		if arg > math.MaxInt64 {
+			n := new(big.Int).SetUint64(arg)
+			return n.Neg(n).Sub(n, big.NewInt(1)), nil
+		}
+		return -1 - int64(arg), nil
+	case cborBytes, cborText:
+		data, err := r.readString(major, info, arg)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborText {
+			return string(data), nil
+		}
+		return data, nil
+	case cborArray:
+		n, err := r.readLength(info, arg, 1)
+		if err != nil {
+			return nil, err
+		}
+		return r.readList(n)
+	case cborMap:
+		n, err := r.readLength(info, arg, 2)
+		if err != nil {
+			return nil, err
+		}
+		return r.readMap(n)
+	case cborTag:
+		return r.readTag(arg)
+	}
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return halfToFloat64(uint16(arg)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(arg))), nil
+	case 27:
+		return math.Float64frombits(arg), nil
+	}
+	return nil, ErrInvalidCBOR
+}
+
+func (r *cborReader) readString(major, info byte, arg uint64) ([]byte, error) {
+	if info != 31 {
+		data, err := r.take(arg)
+		return append([]byte{}, data...), err
+	}
+	data := []byte{}
+	for !r.isBreak() {
+		m, i, n, err := r.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if m != major || i == 31 {
+			return nil, ErrInvalidCBOR
+		}
+		chunk, err := r.take(n)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, chunk...)
+	}
+	return data, nil
+}
+
+func (r *cborReader) readTag(tag uint64) (interface{}, error) {
+	switch tag {
+	case cborTagShareable:
+		i := len(r.shared)
+		r.shared = append(r.shared, nil)
+		r.pending = i
+		v, err := r.read()
+		if r.pending == i {
+			r.shared[i] = v
+			r.pending = -1
+		}
+		return v, err
+	case cborTagSharedRef:
+		major, _, i, err := r.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if major != cborUint || i >= uint64(len(r.shared)) {
+			return nil, ErrInvalidCBOR
+		}
+		return r.shared[i], nil
+	}
+	v, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case cborTagDateTime:
+		if s, ok := v.(string); ok {
+			return time.Parse(time.RFC3339Nano, s)
+		}
+	case cborTagEpoch:
+		switch v := v.(type) {
+		case int64:
+			return time.Unix(v, 0).UTC(), nil
+		case float64:
+			sec, frac := math.Modf(v)
+			return time.Unix(int64(sec), int64(frac*1e9)).UTC(), nil
+		}
+	case cborTagPosBignum, cborTagNegBignum:
+		if data, ok := v.([]byte); ok {
+			n := new(big.Int).SetBytes(data)
+			if tag == cborTagNegBignum {
+				n.Neg(n).Sub(n, big.NewInt(1))
+			}
+			return n, nil
+		}
+	case cborTagUUID:
+		if data, ok := v.([]byte); ok {
+			return uuid.FromBytes(data)
+		}
+	default:
+		return v, nil
+	}
+	return nil, ErrInvalidCBOR
+}
+
+func (r *cborReader) readList(n int) (interface{}, error) {
+	if err := r.depth.enter(); err != nil {
+		return nil, err
+	}
+	defer r.depth.leave()
+	l := &list{}
+	if n > 0 {
+		l.items = make([]interface{}, 0, n)
+	}
+	r.share(l)
+	for i := 0; n < 0 || i < n; i++ {
+		if n < 0 && r.isBreak() {
+			break
+		}
+		item, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+		l.items = append(l.items, item)
+	}
+	return l, nil
+}
+
+// readMap reads the map as an object if its first entry is the class name,
+// the container is created before its entries are read, so that the shared
+// references in the entries can refer to it.
+func (r *cborReader) readMap(n int) (interface{}, error) {
+	if err := r.depth.enter(); err != nil {
+		return nil, err
+	}
+	defer r.depth.leave()
+	m := &mapping{}
+	index := r.share(m)
+	var o *object
+	for i := 0; n < 0 || i < n; i++ {
+		if n < 0 && r.isBreak() {
+			break
+		}
+		key, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+		value, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 && r.ClassKey != "" && key == r.ClassKey {
+			if class, ok := value.(string); ok {
+				o = &object{class: class}
+				if index >= 0 {
+					r.shared[index] = o
+				}
+				continue
+			}
+		}
+		if o != nil {
+			if field, ok := key.(string); ok {
+				o.fields = append(o.fields, field)
+				o.values = append(o.values, value)
+				continue
+			}
+			m.keys = append(m.keys, r.ClassKey)
+			m.values = append(m.values, o.class)
+			for j, field := range o.fields {
+				m.keys = append(m.keys, field)
+				m.values = append(m.values, o.values[j])
+			}
+			if index >= 0 {
+				r.shared[index] = m
+			}
+			o = nil
+		}
+		m.keys = append(m.keys, key)
+		m.values = append(m.values, value)
+	}
+	if o != nil {
+		return o, nil
+	}
+	return m, nil
+}
+
+// halfToFloat64 converts the IEEE 754 half-precision float to float64.
+func halfToFloat64(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp, frac := int(h>>10&0x1f), float64(h&0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac != 0 {
+			return math.NaN()
+		}
+		return math.Inf(int(sign))
+	}
+	return sign * math.Ldexp(frac+1024, exp-25)
+}
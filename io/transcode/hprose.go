@@ -0,0 +1,450 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/transcode/hprose.go                                   |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package transcode
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/hprose/hprose-golang/v3/io"
+)
+
+type class struct {
+	name   string
+	fields []string
+}
+
+// hproseReader reads hprose data as the values of transcode.
+type hproseReader struct {
+	dec     *io.Decoder
+	size    int
+	depth   depth
+	refs    []interface{}
+	shared  map[interface{}]bool
+	classes []class
+}
+
+// readHprose returns the value of hprose data and the containers referenced more than once.
+func (t Transcoder) readHprose(data []byte) (v interface{}, shared map[interface{}]bool, err error) {
+	dec := io.NewDecoder(data).Simple(true)
+	dec.Limits = t.Limits
+	r := hproseReader{
+		dec:    dec,
+		size:   len(data),
+		depth:  depth{max: t.MaxDepth},
+		shared: make(map[interface{}]bool),
+	}
+	if v, err = r.read(dec.NextByte()); err == nil {
+		err = dec.Error
+	}
+	if err == nil && len(dec.Remains()) > 0 {
+		err = ErrTrailingData
+	}
+	return v, r.shared, err
+}
+
+func (r *hproseReader) addReference(v interface{}) interface{} {
+	r.refs = append(r.refs, v)
+	return v
+}
+
+// capacity limits the preallocated capacity by the size of data.
+func (r *hproseReader) capacity(n int) int {
+	if n > r.size {
+		return r.size
+	}
+	return n
+}
+
+func (r *hproseReader) readFoot() error {
+	if r.dec.NextByte() != io.TagClosebrace && r.dec.Error == nil {
+		r.dec.Error = io.DecodeError("hprose/transcode: expected '}'")
+	}
+	return r.dec.Error
+}
+
+func (r *hproseReader) read(tag byte) (interface{}, error) {
+	dec := r.dec
+	if dec.Error != nil {
+		return nil, dec.Error
+	}
+	switch tag {
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return int64(tag - '0'), nil
+	case io.TagInteger:
+		return dec.ReadInt64(), nil
+	case io.TagLong:
+		i := dec.ReadBigInt()
+		if i == nil {
+			if dec.Error == nil {
+				dec.Error = io.DecodeError("hprose/transcode: invalid long")
+			}
+			return nil, dec.Error
+		}
+		if i.IsInt64() {
+			return i.Int64(), nil
+		}
+		return i, nil
+	case io.TagDouble:
+		return dec.ReadFloat64(), nil
+	case io.TagNaN:
+		return math.NaN(), nil
+	case io.TagInfinity:
+		if dec.NextByte() == io.TagNeg {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case io.TagNull:
+		return nil, nil
+	case io.TagEmpty:
+		return "", nil
+	case io.TagTrue:
+		return true, nil
+	case io.TagFalse:
+		return false, nil
+	case io.TagUTF8Char:
+		var s string
+		dec.Decode(&s, tag)
+		return s, dec.Error
+	case io.TagString:
+		return r.addReference(dec.ReadSafeString()), dec.Error
+	case io.TagBytes:
+		return r.addReference(dec.ReadBytes()), dec.Error
+	case io.TagGUID:
+		return r.addReference(dec.ReadUUID()), dec.Error
+	case io.TagDate:
+		return r.addReference(dec.ReadDateTime()), dec.Error
+	case io.TagTime:
+		return r.addReference(dec.ReadTime()), dec.Error
+	case io.TagList:
+		return r.readList()
+	case io.TagMap:
+		return r.readMap()
+	case io.TagClass:
+		if err := r.readClass(); err != nil {
+			return nil, err
+		}
+		return r.read(dec.NextByte())
+	case io.TagObject:
+		return r.readObject()
+	case io.TagRef:
+		index := dec.ReadInt()
+		if index < 0 || index >= len(r.refs) {
+			return nil, io.DecodeError("hprose/transcode: invalid reference index " + strconv.Itoa(index))
+		}
+		v := r.refs[index]
+		switch v.(type) {
+		case *list, *mapping, *object:
+			r.shared[v] = true
+		}
+		return v, nil
+	}
+	return nil, io.DecodeError("hprose/transcode: unexpected tag '" + string(tag) + "'")
+}
+
+func (r *hproseReader) readList() (interface{}, error) {
+	if err := r.depth.enter(); err != nil {
+		return nil, err
+	}
+	defer r.depth.leave()
+	count := r.dec.ReadCount()
+	l := &list{items: make([]interface{}, 0, r.capacity(count))}
+	r.addReference(l)
+	for i := 0; i < count; i++ {
+		item, err := r.read(r.dec.NextByte())
+		if err != nil {
+			return nil, err
+		}
+		l.items = append(l.items, item)
+	}
+	return l, r.readFoot()
+}
+
+func (r *hproseReader) readMap() (interface{}, error) {
+	if err := r.depth.enter(); err != nil {
+		return nil, err
+	}
+	defer r.depth.leave()
+	count := r.dec.ReadCount()
+	m := &mapping{
+		keys:   make([]interface{}, 0, r.capacity(count)),
+		values: make([]interface{}, 0, r.capacity(count)),
+	}
+	r.addReference(m)
+	for i := 0; i < count; i++ {
+		key, err := r.read(r.dec.NextByte())
+		if err != nil {
+			return nil, err
+		}
+		value, err := r.read(r.dec.NextByte())
+		if err != nil {
+			return nil, err
+		}
+		m.keys = append(m.keys, key)
+		m.values = append(m.values, value)
+	}
+	return m, r.readFoot()
+}
+
+func (r *hproseReader) readClass() error {
+	name := r.dec.ReadSafeString()
+	count := r.dec.ReadCount()
+	fields := make([]string, 0, r.capacity(count))
+	for i := 0; i < count; i++ {
+		field, err := r.read(r.dec.NextByte())
+		if err != nil {
+			return err
+		}
+		s, ok := field.(string)
+		if !ok {
+			return io.DecodeError("hprose/transcode: invalid field name of class " + name)
+		}
+		fields = append(fields, s)
+	}
+	r.classes = append(r.classes, class{name, fields})
+	return r.readFoot()
+}
+
+func (r *hproseReader) readObject() (interface{}, error) {
+	if err := r.depth.enter(); err != nil {
+		return nil, err
+	}
+	defer r.depth.leave()
+	index := r.dec.ReadInt()
+	if index < 0 || index >= len(r.classes) {
+		return nil, io.DecodeError("hprose/transcode: invalid class index " + strconv.Itoa(index))
+	}
+	c := r.classes[index]
+	o := &object{c.name, c.fields, make([]interface{}, 0, len(c.fields))}
+	r.addReference(o)
+	for range c.fields {
+		value, err := r.read(r.dec.NextByte())
+		if err != nil {
+			return nil, err
+		}
+		o.values = append(o.values, value)
+	}
+	return o, r.readFoot()
+}
+
+// hproseWriter writes the values of transcode as hprose data.
+type hproseWriter struct {
+	buf     []byte
+	count   int
+	refs    map[interface{}]int
+	classes map[string]int
+}
+
+func writeHprose(v interface{}) []byte {
+	w := hproseWriter{
+		refs:    make(map[interface{}]int),
+		classes: make(map[string]int),
+	}
+	w.write(v)
+	return w.buf
+}
+
+func utf16Length(s string) int {
+	if !utf8.ValidString(s) {
+		return -1
+	}
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+func (w *hproseWriter) writeReference(v interface{}) bool {
+	if i, ok := w.refs[v]; ok {
+		w.buf = append(w.buf, io.TagRef)
+		w.buf = io.AppendInt64(w.buf, int64(i))
+		w.buf = append(w.buf, io.TagSemicolon)
+		return true
+	}
+	w.refs[v] = w.count
+	w.count++
+	return false
+}
+
+func (w *hproseWriter) writeBinary(tag byte, data []byte, length int) {
+	w.buf = append(w.buf, tag)
+	if length > 0 {
+		w.buf = io.AppendInt64(w.buf, int64(length))
+	}
+	w.buf = append(w.buf, io.TagQuote)
+	w.buf = append(w.buf, data...)
+	w.buf = append(w.buf, io.TagQuote)
+}
+
+func (w *hproseWriter) writeString(s string) {
+	length := utf16Length(s)
+	switch {
+	case length == 0:
+		w.buf = append(w.buf, io.TagEmpty)
+	case length == 1:
+		w.buf = append(w.buf, io.TagUTF8Char)
+		w.buf = append(w.buf, s...)
+	case length < 0:
+		w.writeBytes([]byte(s))
+	case !w.writeReference(s):
+		w.writeBinary(io.TagString, []byte(s), length)
+	}
+}
+
+func (w *hproseWriter) writeBytes(data []byte) {
+	w.count++
+	w.writeBinary(io.TagBytes, data, len(data))
+}
+
+func (w *hproseWriter) writeInt(i int64) {
+	switch {
+	case i >= 0 && i <= 9:
+		w.buf = append(w.buf, byte('0'+i))
+	case i >= math.MinInt32 && i <= math.MaxInt32:
+		w.buf = append(w.buf, io.TagInteger)
+		w.buf = io.AppendInt64(w.buf, i)
+		w.buf = append(w.buf, io.TagSemicolon)
+	default:
+		w.buf = append(w.buf, io.TagLong)
+		w.buf = io.AppendInt64(w.buf, i)
+		w.buf = append(w.buf, io.TagSemicolon)
+	}
+}
+
+func (w *hproseWriter) writeFloat(f float64) {
+	switch {
+	case math.IsNaN(f):
+		w.buf = append(w.buf, io.TagNaN)
+	case math.IsInf(f, 1):
+		w.buf = append(w.buf, io.TagInfinity, io.TagPos)
+	case math.IsInf(f, -1):
+		w.buf = append(w.buf, io.TagInfinity, io.TagNeg)
+	default:
+		w.buf = append(w.buf, io.TagDouble)
+		w.buf = strconv.AppendFloat(w.buf, f, 'g', -1, 64)
+		w.buf = append(w.buf, io.TagSemicolon)
+	}
+}
+
+func (w *hproseWriter) write(v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		w.buf = append(w.buf, io.TagNull)
+	case bool:
+		if v {
+			w.buf = append(w.buf, io.TagTrue)
+		} else {
+			w.buf = append(w.buf, io.TagFalse)
+		}
+	case int64:
+		w.writeInt(v)
+	case uint64:
+		if v <= math.MaxInt64 {
+			w.writeInt(int64(v))
+		} else {
+			w.buf = append(w.buf, io.TagLong)
+			w.buf = io.AppendUint64(w.buf, v)
+			w.buf = append(w.buf, io.TagSemicolon)
+		}
+	case *big.Int:
+		w.buf = append(w.buf, io.TagLong)
+		w.buf = append(w.buf, v.String()...)
+		w.buf = append(w.buf, io.TagSemicolon)
+	case float64:
+		w.writeFloat(v)
+	case string:
+		w.writeString(v)
+	case []byte:
+		w.writeBytes(v)
+	case time.Time:
+		data, _ := io.Marshal(v)
+		w.count++
+		w.buf = append(w.buf, data...)
+	case uuid.UUID:
+		w.count++
+		w.buf = append(w.buf, io.TagGUID, io.TagOpenbrace)
+		w.buf = append(w.buf, v.String()...)
+		w.buf = append(w.buf, io.TagClosebrace)
+	case *list:
+		if !w.writeReference(v) {
+			w.writeHead(io.TagList, len(v.items))
+			for _, item := range v.items {
+				w.write(item)
+			}
+			w.buf = append(w.buf, io.TagClosebrace)
+		}
+	case *mapping:
+		if !w.writeReference(v) {
+			w.writeHead(io.TagMap, len(v.keys))
+			for i, key := range v.keys {
+				w.write(key)
+				w.write(v.values[i])
+			}
+			w.buf = append(w.buf, io.TagClosebrace)
+		}
+	case *object:
+		w.writeObject(v)
+	}
+}
+
+func (w *hproseWriter) writeHead(tag byte, n int) {
+	w.buf = append(w.buf, tag)
+	w.writeCount(n)
+}
+
+func (w *hproseWriter) writeCount(n int) {
+	if n > 0 {
+		w.buf = io.AppendInt64(w.buf, int64(n))
+	}
+	w.buf = append(w.buf, io.TagOpenbrace)
+}
+
+func (w *hproseWriter) writeObject(o *object) {
+	if _, ok := w.refs[o]; ok {
+		w.writeReference(o)
+		return
+	}
+	key := o.class
+	for _, field := range o.fields {
+		key += "\x00" + field
+	}
+	index, ok := w.classes[key]
+	if !ok {
+		index = len(w.classes)
+		w.classes[key] = index
+		w.writeBinary(io.TagClass, []byte(o.class), utf16Length(o.class))
+		w.writeCount(len(o.fields))
+		for _, field := range o.fields {
+			w.writeString(field)
+		}
+		w.buf = append(w.buf, io.TagClosebrace)
+	}
+	w.writeReference(o)
+	w.buf = append(w.buf, io.TagObject)
+	w.buf = io.AppendInt64(w.buf, int64(index))
+	w.buf = append(w.buf, io.TagOpenbrace)
+	for _, value := range o.values {
+		w.write(value)
+	}
+	w.buf = append(w.buf, io.TagClosebrace)
+}
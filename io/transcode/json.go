@@ -0,0 +1,268 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/transcode/json.go                                     |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package transcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ToJSON converts hprose data to JSON.
+func (t Transcoder) ToJSON(data []byte) ([]byte, error) {
+	v, _, err := t.readHprose(data)
+	if err != nil {
+		return nil, err
+	}
+	w := jsonWriter{Transcoder: t, active: make(active)}
+	if err = w.write(v); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+// FromJSON converts JSON data to hprose.
+func (t Transcoder) FromJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	r := jsonReader{Transcoder: t, dec: dec, depth: depth{max: t.MaxDepth}}
+	v, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = dec.Token(); err != io.EOF {
+		return nil, ErrTrailingData
+	}
+	return writeHprose(v), nil
+}
+
+type jsonWriter struct {
+	Transcoder
+	buf    []byte
+	active active
+}
+
+const hex = "0123456789abcdef"
+
+func appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				buf = append(buf, '\\', c)
+			case c == '\n':
+				buf = append(buf, '\\', 'n')
+			case c == '\r':
+				buf = append(buf, '\\', 'r')
+			case c == '\t':
+				buf = append(buf, '\\', 't')
+			case c < 0x20:
+				buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			default:
+				buf = append(buf, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf = append(buf, `\ufffd`...)
+		case r == '\u2028' || r == '\u2029':
+			buf = append(buf, '\\', 'u', '2', '0', '2', hex[r&0xF])
+		default:
+			buf = append(buf, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(buf, '"')
+}
+
+func (w *jsonWriter) write(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		w.buf = append(w.buf, "null"...)
+	case bool:
+		w.buf = strconv.AppendBool(w.buf, v)
+	case int64:
+		w.buf = strconv.AppendInt(w.buf, v, 10)
+	case *big.Int:
+		w.buf = v.Append(w.buf, 10)
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			w.buf = append(w.buf, `"NaN"`...)
+		case math.IsInf(v, 1):
+			w.buf = append(w.buf, `"Infinity"`...)
+		case math.IsInf(v, -1):
+			w.buf = append(w.buf, `"-Infinity"`...)
+		default:
+			w.buf = strconv.AppendFloat(w.buf, v, 'g', -1, 64)
+		}
+	case string:
+		w.buf = appendJSONString(w.buf, v)
+	case []byte:
+		w.buf = appendJSONString(w.buf, base64.StdEncoding.EncodeToString(v))
+	case time.Time:
+		w.buf = appendJSONString(w.buf, v.Format(time.RFC3339Nano))
+	case uuid.UUID:
+		w.buf = appendJSONString(w.buf, v.String())
+	case *list:
+		if err := w.active.enter(v); err != nil {
+			return err
+		}
+		w.buf = append(w.buf, '[')
+		for i, item := range v.items {
+			if i > 0 {
+				w.buf = append(w.buf, ',')
+			}
+			if err := w.write(item); err != nil {
+				return err
+			}
+		}
+		w.buf = append(w.buf, ']')
+		w.active.leave(v)
+	case *mapping:
+		if err := w.active.enter(v); err != nil {
+			return err
+		}
+		if err := w.writeMap(v); err != nil {
+			return err
+		}
+		w.active.leave(v)
+	case *object:
+		if err := w.active.enter(v); err != nil {
+			return err
+		}
+		if err := w.writeMap(w.toMapping(v)); err != nil {
+			return err
+		}
+		w.active.leave(v)
+	}
+	return w.checkOutput(w.buf)
+}
+
+func (w *jsonWriter) writeMap(m *mapping) error {
+	w.buf = append(w.buf, '{')
+	for i, key := range m.keys {
+		if i > 0 {
+			w.buf = append(w.buf, ',')
+		}
+		switch key := key.(type) {
+		case string:
+			w.buf = appendJSONString(w.buf, key)
+		case *list, *mapping, *object:
+			return ErrUnsupportedKey
+		default:
+			start := len(w.buf)
+			if err := w.write(key); err != nil {
+				return err
+			}
+			if w.buf[start] != '"' {
+				text := string(w.buf[start:])
+				w.buf = appendJSONString(w.buf[:start], text)
+			}
+		}
+		w.buf = append(w.buf, ':')
+		if err := w.write(m.values[i]); err != nil {
+			return err
+		}
+	}
+	w.buf = append(w.buf, '}')
+	return nil
+}
+
+type jsonReader struct {
+	Transcoder
+	dec   *json.Decoder
+	depth depth
+}
+
+func parseNumber(s string) interface{} {
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+		if i, ok := new(big.Int).SetString(s, 10); ok {
+			return i
+		}
+	}
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+func (r *jsonReader) read() (interface{}, error) {
+	token, err := r.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token := token.(type) {
+	case json.Delim:
+		if err := r.depth.enter(); err != nil {
+			return nil, err
+		}
+		defer r.depth.leave()
+		if token == '[' {
+			return r.readList()
+		}
+		return r.readMap()
+	case json.Number:
+		return parseNumber(string(token)), nil
+	}
+	return token, nil
+}
+
+func (r *jsonReader) readList() (interface{}, error) {
+	l := &list{}
+	for r.dec.More() {
+		item, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+		l.items = append(l.items, item)
+	}
+	_, err := r.dec.Token()
+	return l, err
+}
+
+func (r *jsonReader) readMap() (interface{}, error) {
+	m := &mapping{}
+	for r.dec.More() {
+		key, err := r.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		value, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+		m.keys = append(m.keys, key)
+		m.values = append(m.values, value)
+	}
+	if _, err := r.dec.Token(); err != nil {
+		return nil, err
+	}
+	return r.fromMapping(m), nil
+}
@@ -0,0 +1,422 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/transcode/msgpack.go                                  |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package transcode
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidMsgPack is returned when the MessagePack data is malformed.
+var ErrInvalidMsgPack = errors.New("hprose/transcode: invalid MessagePack data")
+
+// ToMsgPack converts hprose data to MessagePack.
+func (t Transcoder) ToMsgPack(data []byte) ([]byte, error) {
+	v, _, err := t.readHprose(data)
+	if err != nil {
+		return nil, err
+	}
+	w := msgpackWriter{Transcoder: t, active: make(active)}
+	if err = w.write(v); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+// FromMsgPack converts MessagePack data to hprose.
+func (t Transcoder) FromMsgPack(data []byte) ([]byte, error) {
+	r := msgpackReader{Transcoder: t, data: data, depth: depth{max: t.MaxDepth}}
+	v, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	if r.pos < len(data) {
+		return nil, ErrTrailingData
+	}
+	return writeHprose(v), nil
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v>>8), byte(v))
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	return appendUint32(appendUint32(buf, uint32(v>>32)), uint32(v))
+}
+
+type msgpackWriter struct {
+	Transcoder
+	buf    []byte
+	active active
+}
+
+func (w *msgpackWriter) writeUint(v uint64) {
+	switch {
+	case v <= math.MaxInt8:
+		w.buf = append(w.buf, byte(v))
+	case v <= math.MaxUint8:
+		w.buf = append(w.buf, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		w.buf = appendUint16(append(w.buf, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		w.buf = appendUint32(append(w.buf, 0xce), uint32(v))
+	default:
+		w.buf = appendUint64(append(w.buf, 0xcf), v)
+	}
+}
+
+func (w *msgpackWriter) writeInt(v int64) {
+	switch {
+	case v >= 0:
+		w.writeUint(uint64(v))
+	case v >= -32:
+		w.buf = append(w.buf, byte(v))
+	case v >= math.MinInt8:
+		w.buf = append(w.buf, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		w.buf = appendUint16(append(w.buf, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		w.buf = appendUint32(append(w.buf, 0xd2), uint32(v))
+	default:
+		w.buf = appendUint64(append(w.buf, 0xd3), uint64(v))
+	}
+}
+
+// writeHead writes the header of str, bin, array or map, fix is the first
+// byte of the fix format or 0 if there is no fix format, and max is the
+// maximum length of the fix format.
+func (w *msgpackWriter) writeHead(n int, fix byte, max int, b8, b16, b32 byte) {
+	switch {
+	case fix != 0 && n <= max:
+		w.buf = append(w.buf, fix|byte(n))
+	case b8 != 0 && n <= math.MaxUint8:
+		w.buf = append(w.buf, b8, byte(n))
+	case n <= math.MaxUint16:
+		w.buf = appendUint16(append(w.buf, b16), uint16(n))
+	default:
+		w.buf = appendUint32(append(w.buf, b32), uint32(n))
+	}
+}
+
+func (w *msgpackWriter) writeString(s string) {
+	w.writeHead(len(s), 0xa0, 31, 0xd9, 0xda, 0xdb)
+	w.buf = append(w.buf, s...)
+}
+
+func (w *msgpackWriter) writeTime(t time.Time) {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	if sec>>34 == 0 {
+		data := nsec<<34 | uint64(sec)
+		if data>>32 == 0 {
+			w.buf = appendUint32(append(w.buf, 0xd6, 0xff), uint32(data))
+		} else {
+			w.buf = appendUint64(append(w.buf, 0xd7, 0xff), data)
+		}
+		return
+	}
+	w.buf = appendUint32(append(w.buf, 0xc7, 12, 0xff), uint32(nsec))
+	w.buf = appendUint64(w.buf, uint64(sec))
+}
+
+func (w *msgpackWriter) write(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		w.buf = append(w.buf, 0xc0)
+	case bool:
+		if v {
+			w.buf = append(w.buf, 0xc3)
+		} else {
+			w.buf = append(w.buf, 0xc2)
+		}
+	case int64:
+		w.writeInt(v)
+	case uint64:
+		w.writeUint(v)
+	case *big.Int:
+		switch {
+		case v.IsInt64():
+			w.writeInt(v.Int64())
+		case v.IsUint64():
+			w.writeUint(v.Uint64())
+		default:
+			w.writeString(v.String())
+		}
+	case float64:
+		w.buf = appendUint64(append(w.buf, 0xcb), math.Float64bits(v))
+	case string:
+		w.writeString(v)
+	case []byte:
+		w.writeHead(len(v), 0, 0, 0xc4, 0xc5, 0xc6)
+		w.buf = append(w.buf, v...)
+	case time.Time:
+		w.writeTime(v)
+	case uuid.UUID:
+		w.writeString(v.String())
+	case *list:
+		if err := w.active.enter(v); err != nil {
+			return err
+		}
+		w.writeHead(len(v.items), 0x90, 15, 0, 0xdc, 0xdd)
+		for _, item := range v.items {
+			if err := w.write(item); err != nil {
+				return err
+			}
+		}
+		w.active.leave(v)
+	case *mapping:
+		if err := w.active.enter(v); err != nil {
+			return err
+		}
+		if err := w.writeMap(v); err != nil {
+			return err
+		}
+		w.active.leave(v)
+	case *object:
+		if err := w.active.enter(v); err != nil {
+			return err
+		}
+		if err := w.writeMap(w.toMapping(v)); err != nil {
+			return err
+		}
+		w.active.leave(v)
+	}
+	return w.checkOutput(w.buf)
+}
+
+func (w *msgpackWriter) writeMap(m *mapping) error {
+	w.writeHead(len(m.keys), 0x80, 15, 0, 0xde, 0xdf)
+	for i, key := range m.keys {
+		if err := w.write(key); err != nil {
+			return err
+		}
+		if err := w.write(m.values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type msgpackReader struct {
+	Transcoder
+	data  []byte
+	pos   int
+	depth depth
+}
+
+func (r *msgpackReader) take(n int) ([]byte, error) {
+	if n < 0 || n > len(r.data)-r.pos {
+		return nil, ErrInvalidMsgPack
+	}
+	data := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return data, nil
+}
+
+// readUint reads the big-endian unsigned integer of n bytes.
+func (r *msgpackReader) readUint(n int) (uint64, error) {
+	data, err := r.take(n)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, b := range data {
+		v = v<<8 | uint64(b)
+	}
+	return v, nil
+}
+
+// readLength reads the length of n bytes, and checks it by the remaining data.
+func (r *msgpackReader) readLength(n int) (int, error) {
+	v, err := r.readUint(n)
+	if err != nil {
+		return 0, err
+	}
+	if v > uint64(len(r.data)-r.pos) {
+		return 0, ErrInvalidMsgPack
+	}
+	return int(v), nil
+}
+
+func (r *msgpackReader) read() (interface{}, error) {
+	data, err := r.take(1)
+	if err != nil {
+		return nil, err
+	}
+	b := data[0]
+	switch {
+	case b <= 0x7f:
+		return int64(b), nil
+	case b <= 0x8f:
+		return r.readMap(int(b & 0x0f))
+	case b <= 0x9f:
+		return r.readList(int(b & 0x0f))
+	case b <= 0xbf:
+		return r.readString(int(b & 0x1f))
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	}
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := r.readLength(1 << (b - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		data, err := r.take(n)
+		return append([]byte{}, data...), err
+	case 0xc7, 0xc8, 0xc9:
+		n, err := r.readLength(1 << (b - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return r.readExt(n)
+	case 0xca:
+		v, err := r.readUint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := r.readUint(8)
+		return math.Float64frombits(v), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v, err := r.readUint(1 << (b - 0xcc))
+		if v > math.MaxInt64 {
+			return new(big.Int).SetUint64(v), err
+		}
+		return int64(v), err
+	case 0xd0:
+		v, err := r.readUint(1)
+		return int64(int8(v)), err
+	case 0xd1:
+		v, err := r.readUint(2)
+		return int64(int16(v)), err
+	case 0xd2:
+		v, err := r.readUint(4)
+		return int64(int32(v)), err
+	case 0xd3:
+		v, err := r.readUint(8)
+		return int64(v), err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return r.readExt(1 << (b - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := r.readLength(1 << (b - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return r.readString(n)
+	case 0xdc, 0xdd:
+		n, err := r.readLength(2 << (b - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return r.readList(n)
+	case 0xde, 0xdf:
+		n, err := r.readLength(2 << (b - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return r.readMap(n)
+	}
+	return nil, ErrInvalidMsgPack
+}
+
+func (r *msgpackReader) readString(n int) (interface{}, error) {
+	data, err := r.take(n)
+	return string(data), err
+}
+
+func (r *msgpackReader) readExt(n int) (interface{}, error) {
+	typ, err := r.take(1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.take(n)
+	if err != nil {
+		return nil, err
+	}
+	if int8(typ[0]) != -1 {
+		return nil, errors.New("hprose/transcode: unsupported MessagePack extension type " + strconv.Itoa(int(int8(typ[0]))))
+	}
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0).UTC(), nil
+	case 8:
+		v := binary.BigEndian.Uint64(data)
+		return time.Unix(int64(v&(1<<34-1)), int64(v>>34)).UTC(), nil
+	case 12:
+		nsec := binary.BigEndian.Uint32(data)
+		sec := binary.BigEndian.Uint64(data[4:])
+		return time.Unix(int64(sec), int64(nsec)).UTC(), nil
+	}
+	return nil, ErrInvalidMsgPack
+}
+
+func (r *msgpackReader) readList(n int) (interface{}, error) {
+	if n > len(r.data)-r.pos {
+		return nil, ErrInvalidMsgPack
+	}
+	if err := r.depth.enter(); err != nil {
+		return nil, err
+	}
+	defer r.depth.leave()
+	l := &list{items: make([]interface{}, 0, n)}
+	for i := 0; i < n; i++ {
+		item, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+		l.items = append(l.items, item)
+	}
+	return l, nil
+}
+
+func (r *msgpackReader) readMap(n int) (interface{}, error) {
+	if n > (len(r.data)-r.pos)/2 {
+		return nil, ErrInvalidMsgPack
+	}
+	if err := r.depth.enter(); err != nil {
+		return nil, err
+	}
+	defer r.depth.leave()
+	m := &mapping{
+		keys:   make([]interface{}, 0, n),
+		values: make([]interface{}, 0, n),
+	}
+	for i := 0; i < n; i++ {
+		key, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+		value, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+		m.keys = append(m.keys, key)
+		m.values = append(m.values, value)
+	}
+	return r.fromMapping(m), nil
+}
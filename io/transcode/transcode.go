@@ -0,0 +1,203 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/transcode/transcode.go                                |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+// Package transcode converts hprose data to and from JSON, MessagePack and
+// CBOR without knowing the Go types of the values.
+//
+// The values are mapped as follows:
+//
+//	hprose            JSON              MessagePack          CBOR
+//	null              null              nil                  null
+//	empty             ""                ""                   ""
+//	true/false        true/false        true/false           true/false
+//	integer           number            int                  int
+//	long              number            int, or string       int, or bignum (tag 2/3)
+//	double            number            float64              float64
+//	NaN/Infinity      "NaN"/"Infinity"  float64              float64
+//	string/char       string            str                  text
+//	bytes             base64 string     bin                  bytes
+//	date/time         RFC 3339 string   timestamp (ext -1)   RFC 3339 text (tag 0)
+//	guid              string            str                  bytes (tag 37)
+//	list              array             array                array
+//	map               object            map                  map
+//	object            object            map                  map
+//
+// The long integers which can not be represented by int64 or uint64 are
+// encoded as decimal strings in MessagePack. The keys of maps are converted
+// to strings in JSON, and lists or maps can not be used as the keys of JSON
+// objects.
+//
+// The class name of an object is dropped unless ClassKey is set. If ClassKey
+// is set, the class name is encoded as the first entry of the map with the
+// key ClassKey, and such maps are decoded as hprose objects.
+//
+// The references of hprose are expanded in JSON and MessagePack, so the data
+// with circular references can not be converted to them, and the size of
+// the expanded data is limited by MaxAllocation. In CBOR, the lists,
+// maps and objects referenced more than once are encoded with the
+// shareable (28) and sharedref (29) tags. Converting data to hprose always
+// writes the shared values as hprose references.
+package transcode
+
+import (
+	"errors"
+
+	"github.com/hprose/hprose-golang/v3/io"
+)
+
+// Errors returned by Transcoder.
+var (
+	ErrCircularReference  = errors.New("hprose/transcode: circular reference")
+	ErrUnsupportedKey     = errors.New("hprose/transcode: list or map can not be used as key")
+	ErrTrailingData       = errors.New("hprose/transcode: unexpected trailing data")
+	ErrDepthExceeded      = io.ErrDepthExceeded
+	ErrAllocationExceeded = io.ErrAllocationExceeded
+)
+
+// Transcoder converts hprose data to and from other formats.
+type Transcoder struct {
+	// ClassKey is the map key which holds the class name of hprose objects.
+	ClassKey string
+	// Limits of the decoders, MaxDepth is also used by the decoders of the
+	// other formats, and MaxAllocation also limits the size of JSON and
+	// MessagePack data in which the references are expanded.
+	io.Limits
+}
+
+// list, mapping and object are the containers which can be shared by references.
+type list struct {
+	items []interface{}
+}
+
+type mapping struct {
+	keys   []interface{}
+	values []interface{}
+}
+
+type object struct {
+	class  string
+	fields []string
+	values []interface{}
+}
+
+// toMapping returns the object as a mapping with the class name entry.
+func (t Transcoder) toMapping(o *object) *mapping {
+	n := len(o.fields)
+	if t.ClassKey != "" {
+		n++
+	}
+	m := &mapping{
+		keys:   make([]interface{}, 0, n),
+		values: make([]interface{}, 0, n),
+	}
+	if t.ClassKey != "" {
+		m.keys = append(m.keys, t.ClassKey)
+		m.values = append(m.values, o.class)
+	}
+	for i, field := range o.fields {
+		m.keys = append(m.keys, field)
+		m.values = append(m.values, o.values[i])
+	}
+	return m
+}
+
+// fromMapping returns the object if m has the class name entry, otherwise returns m.
+func (t Transcoder) fromMapping(m *mapping) interface{} {
+	if t.ClassKey == "" || len(m.keys) == 0 || m.keys[0] != t.ClassKey {
+		return m
+	}
+	class, ok := m.values[0].(string)
+	if !ok {
+		return m
+	}
+	fields := make([]string, len(m.keys)-1)
+	for i, key := range m.keys[1:] {
+		if fields[i], ok = key.(string); !ok {
+			return m
+		}
+	}
+	return &object{class, fields, m.values[1:]}
+}
+
+// depth checks the nesting depth of the decoders.
+type depth struct {
+	max     int
+	current int
+}
+
+func (d *depth) enter() error {
+	if d.max > 0 && d.current >= d.max {
+		return ErrDepthExceeded
+	}
+	d.current++
+	return nil
+}
+
+func (d *depth) leave() {
+	d.current--
+}
+
+// checkOutput checks the size of the output in which the references are expanded.
+func (t Transcoder) checkOutput(buf []byte) error {
+	if t.MaxAllocation > 0 && len(buf) > t.MaxAllocation {
+		return ErrAllocationExceeded
+	}
+	return nil
+}
+
+// active tracks the containers being encoded to detect circular references.
+type active map[interface{}]bool
+
+func (a active) enter(v interface{}) error {
+	if a[v] {
+		return ErrCircularReference
+	}
+	a[v] = true
+	return nil
+}
+
+func (a active) leave(v interface{}) {
+	delete(a, v)
+}
+
+var defaultTranscoder Transcoder
+
+// ToJSON converts hprose data to JSON.
+func ToJSON(data []byte) ([]byte, error) {
+	return defaultTranscoder.ToJSON(data)
+}
+
+// FromJSON converts JSON data to hprose.
+func FromJSON(data []byte) ([]byte, error) {
+	return defaultTranscoder.FromJSON(data)
+}
+
+// ToMsgPack converts hprose data to MessagePack.
+func ToMsgPack(data []byte) ([]byte, error) {
+	return defaultTranscoder.ToMsgPack(data)
+}
+
+// FromMsgPack converts MessagePack data to hprose.
+func FromMsgPack(data []byte) ([]byte, error) {
+	return defaultTranscoder.FromMsgPack(data)
+}
+
+// ToCBOR converts hprose data to CBOR.
+func ToCBOR(data []byte) ([]byte, error) {
+	return defaultTranscoder.ToCBOR(data)
+}
+
+// FromCBOR converts CBOR data to hprose.
+func FromCBOR(data []byte) ([]byte, error) {
+	return defaultTranscoder.FromCBOR(data)
+}
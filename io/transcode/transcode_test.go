@@ -0,0 +1,275 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/transcode/transcode_test.go                           |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package transcode_test
+
+import (
+	"encoding/hex"
+	"math"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hprose/hprose-golang/v3/io"
+	. "github.com/hprose/hprose-golang/v3/io/transcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToJSON(t *testing.T) {
+	data, err := ToJSON([]byte(`m3{s4"name"s6"hprose"s4"list"a3{1d1.5;n}s5"bytes"b3"abc"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"hprose","list":[1,1.5,null],"bytes":"YWJj"}`, string(data))
+	data, err = ToJSON([]byte(`a4{NI+I-ut}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `["NaN","Infinity","-Infinity","t"]`, string(data))
+	data, err = ToJSON([]byte(`D20201231T235959.123Z`))
+	assert.NoError(t, err)
+	assert.Equal(t, `"2020-12-31T23:59:59.123Z"`, string(data))
+	data, err = ToJSON([]byte(`m1{1s1"<"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"1":"<"}`, string(data))
+}
+
+func TestFromJSON(t *testing.T) {
+	data, err := FromJSON([]byte(` {"a": [1, -2, 1.5, 12345678901234567890, true, null, "x"]} `))
+	assert.NoError(t, err)
+	assert.Equal(t, `m1{uaa7{1i-2;d1.5;l12345678901234567890;tnux}}`, string(data))
+	_, err = FromJSON([]byte(`[1] 2`))
+	assert.Equal(t, ErrTrailingData, err)
+	_, err = FromJSON([]byte(`[1,`))
+	assert.Error(t, err)
+}
+
+func TestJSONReference(t *testing.T) {
+	data, err := ToJSON([]byte(`a2{a1{1}r1;}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `[[1],[1]]`, string(data))
+	_, err = ToJSON([]byte(`a1{r0;}`))
+	assert.Equal(t, ErrCircularReference, err)
+	_, err = ToJSON([]byte(`m1{a0{}1}`))
+	assert.Equal(t, ErrUnsupportedKey, err)
+}
+
+func TestExpandedReferenceLimit(t *testing.T) {
+	data := "a2{11}"
+	for i := 19; i >= 0; i-- {
+		data = "a2{" + data + "r" + strconv.Itoa(i+1) + ";}"
+	}
+	transcoder := Transcoder{Limits: io.Limits{MaxAllocation: 1 << 16}}
+	_, err := transcoder.ToJSON([]byte(data))
+	assert.Equal(t, ErrAllocationExceeded, err)
+	_, err = transcoder.ToMsgPack([]byte(data))
+	assert.Equal(t, ErrAllocationExceeded, err)
+	_, err = transcoder.ToCBOR([]byte(data))
+	assert.NoError(t, err)
+	json, err := transcoder.ToJSON([]byte(`a2{a1{1}r1;}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `[[1],[1]]`, string(json))
+}
+
+func TestClassKey(t *testing.T) {
+	hprose := []byte(`c5"Point"2{uxuy}o0{12}`)
+	data, err := ToJSON(hprose)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"x":1,"y":2}`, string(data))
+	tc := Transcoder{ClassKey: "@class"}
+	data, err = tc.ToJSON(hprose)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"@class":"Point","x":1,"y":2}`, string(data))
+	data, err = tc.FromJSON(data)
+	assert.NoError(t, err)
+	assert.Equal(t, string(hprose), string(data))
+	data, err = tc.ToMsgPack(hprose)
+	assert.NoError(t, err)
+	data, err = tc.FromMsgPack(data)
+	assert.NoError(t, err)
+	assert.Equal(t, string(hprose), string(data))
+	data, err = tc.ToCBOR(hprose)
+	assert.NoError(t, err)
+	data, err = tc.FromCBOR(data)
+	assert.NoError(t, err)
+	assert.Equal(t, string(hprose), string(data))
+}
+
+func TestMsgPack(t *testing.T) {
+	data, err := ToMsgPack([]byte(`m2{s1"a"a3{1i-1;i300;}s1"b"b2"xy"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "82a16193"+"01ff"+"cd012c"+"a162c4027879", hex.EncodeToString(data))
+	data, err = FromMsgPack(data)
+	assert.NoError(t, err)
+	assert.Equal(t, `m2{uaa3{1i-1;i300;}ubb2"xy"}`, string(data))
+}
+
+func TestMsgPackValues(t *testing.T) {
+	values := []interface{}{
+		nil, true, false, 0, -32, -33, 127, 128, 65535, 65536,
+		math.MaxInt64, math.MinInt64, 3.14, "hello", []byte{1, 2, 3},
+		[]int{1, 2, 3}, map[string]int{"one": 1},
+	}
+	for _, v := range values {
+		hprose, err := io.Marshal(v)
+		assert.NoError(t, err)
+		data, err := ToMsgPack(hprose)
+		assert.NoError(t, err)
+		data, err = FromMsgPack(data)
+		assert.NoError(t, err)
+		assert.Equal(t, string(hprose), string(data), v)
+	}
+}
+
+func TestMsgPackBigInt(t *testing.T) {
+	data, err := ToMsgPack([]byte(`l18446744073709551615;`))
+	assert.NoError(t, err)
+	assert.Equal(t, "cfffffffffffffffff", hex.EncodeToString(data))
+	data, err = FromMsgPack(data)
+	assert.NoError(t, err)
+	assert.Equal(t, `l18446744073709551615;`, string(data))
+	data, err = ToMsgPack([]byte(`l100000000000000000000;`))
+	assert.NoError(t, err)
+	assert.Equal(t, "b5313030303030303030303030303030303030303030", hex.EncodeToString(data))
+}
+
+func TestMsgPackTime(t *testing.T) {
+	times := []time.Time{
+		time.Unix(1600000000, 0).UTC(),
+		time.Unix(1600000000, 123456789).UTC(),
+		time.Unix(-1, 500).UTC(),
+		time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for _, tm := range times {
+		hprose, err := io.Marshal(tm)
+		assert.NoError(t, err)
+		data, err := ToMsgPack(hprose)
+		assert.NoError(t, err)
+		data, err = FromMsgPack(data)
+		assert.NoError(t, err)
+		var result time.Time
+		assert.NoError(t, io.Unmarshal(data, &result))
+		assert.True(t, tm.Equal(result), tm)
+	}
+}
+
+func TestMsgPackError(t *testing.T) {
+	_, err := FromMsgPack([]byte{0xc1})
+	assert.Equal(t, ErrInvalidMsgPack, err)
+	_, err = FromMsgPack([]byte{0xdd, 0xff, 0xff, 0xff, 0xff})
+	assert.Equal(t, ErrInvalidMsgPack, err)
+	_, err = FromMsgPack([]byte{0x01, 0x02})
+	assert.Equal(t, ErrTrailingData, err)
+	_, err = FromMsgPack([]byte{0xd4, 0x01, 0x00})
+	assert.EqualError(t, err, "hprose/transcode: unsupported MessagePack extension type 1")
+	tc := Transcoder{}
+	tc.MaxDepth = 2
+	_, err = tc.FromMsgPack([]byte{0x91, 0x91, 0x91, 0x01})
+	assert.Equal(t, ErrDepthExceeded, err)
+	_, err = ToMsgPack([]byte(`a1{r0;}`))
+	assert.Equal(t, ErrCircularReference, err)
+}
+
+func TestCBOR(t *testing.T) {
+	data, err := ToCBOR([]byte(`m2{s1"a"a3{1i-1;i300;}s1"b"b2"xy"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "a2616183"+"0120"+"19012c"+"6162427879", hex.EncodeToString(data))
+	data, err = FromCBOR(data)
+	assert.NoError(t, err)
+	assert.Equal(t, `m2{uaa3{1i-1;i300;}ubb2"xy"}`, string(data))
+}
+
+func TestCBORValues(t *testing.T) {
+	values := []interface{}{
+		nil, true, false, 0, -1, 23, 24, 255, 256, 65536, uint64(math.MaxUint32) + 1,
+		math.MaxInt64, math.MinInt64, 3.14, math.Inf(1), "hello", []byte{1, 2, 3},
+		[]int{1, 2, 3}, map[string]int{"one": 1},
+		uuid.MustParse("f47ac10b-58cc-4372-a567-0e02b2c3d479"),
+		time.Date(2020, 12, 31, 23, 59, 59, 123000000, time.UTC),
+	}
+	for _, v := range values {
+		hprose, err := io.Marshal(v)
+		assert.NoError(t, err)
+		data, err := ToCBOR(hprose)
+		assert.NoError(t, err)
+		data, err = FromCBOR(data)
+		assert.NoError(t, err)
+		assert.Equal(t, string(hprose), string(data), v)
+	}
+}
+
+func TestCBORBigInt(t *testing.T) {
+	n, _ := new(big.Int).SetString("-100000000000000000000", 10)
+	hprose, err := io.Marshal(n)
+	assert.NoError(t, err)
+	data, err := ToCBOR(hprose)
+	assert.NoError(t, err)
+	assert.Equal(t, "c349056bc75e2d630fffff", hex.EncodeToString(data))
+	data, err = FromCBOR(data)
+	assert.NoError(t, err)
+	assert.Equal(t, string(hprose), string(data))
+}
+
+func TestCBORShared(t *testing.T) {
+	hprose := []byte(`a3{a1{1}r1;r0;}`)
+	data, err := ToCBOR(hprose)
+	assert.NoError(t, err)
+	assert.Equal(t, "d81c83d81c8101d81d01d81d00", hex.EncodeToString(data))
+	data, err = FromCBOR(data)
+	assert.NoError(t, err)
+	assert.Equal(t, string(hprose), string(data))
+}
+
+func TestFromCBOR(t *testing.T) {
+	// indefinite length array, map and text, half float and epoch time.
+	data, err := FromCBOR([]byte{0x9f, 0xbf, 0x7f, 0x61, 0x61, 0x61, 0x62, 0xff, 0xf9, 0x3c, 0x00, 0xff, 0xc1, 0x00, 0xff})
+	assert.NoError(t, err)
+	assert.Equal(t, `a2{m1{s2"ab"d1;}D19700101Z}`, string(data))
+	_, err = FromCBOR([]byte{0x1c})
+	assert.Equal(t, ErrInvalidCBOR, err)
+	_, err = FromCBOR([]byte{0xd8, 0x1d, 0x00})
+	assert.Equal(t, ErrInvalidCBOR, err)
+	_, err = FromCBOR([]byte{0x01, 0x02})
+	assert.Equal(t, ErrTrailingData, err)
+}
+
+func TestMalformedHprose(t *testing.T) {
+	for _, data := range []string{`l;`, `lx;`, `l1.5;`, `a1{l;}`, `m1{s1"a"l-;}`} {
+		_, err := ToJSON([]byte(data))
+		assert.Error(t, err, data)
+		_, err = ToMsgPack([]byte(data))
+		assert.Error(t, err, data)
+		_, err = ToCBOR([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestRandomHprose(t *testing.T) {
+	const alphabet = `0123456789-.;"{}()abcdeilmnorstuxzDEIHNTFUCRc `
+	r := rand.New(rand.NewSource(1))
+	data := make([]byte, 20)
+	for i := 0; i < 100000; i++ {
+		n := 4 + r.Intn(17)
+		for j := 0; j < n; j++ {
+			if r.Intn(4) == 0 {
+				data[j] = byte(r.Intn(256))
+			} else {
+				data[j] = alphabet[r.Intn(len(alphabet))]
+			}
+		}
+		assert.NotPanics(t, func() {
+			_, _ = ToJSON(data[:n])
+			_, _ = ToMsgPack(data[:n])
+			_, _ = ToCBOR(data[:n])
+		}, "%q", data[:n])
+	}
+}
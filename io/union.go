@@ -0,0 +1,87 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/union.go                                              |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// unionInfo is the registered information of a union type.
+type unionInfo struct {
+	name     string
+	t        reflect.Type
+	metadata []byte
+}
+
+// unionField is the only field name of the union objects.
+const unionField = "value"
+
+var (
+	unionNameMap sync.Map
+	unionTypeMap sync.Map
+)
+
+// RegisterUnion registers the named non-struct type of the proto with alias
+// for the typed union mode, the type name is used if alias is empty.
+//
+// In the typed union mode, the values of the registered types are written
+// as the objects of the class alias, which have only one field "value".
+// The decoder reads them back to the registered types whether it is in the
+// typed union mode or not, so the slice of an interface type holding the
+// different registered types can be decoded to the right concrete types.
+// The struct types should be registered by Register or RegisterName.
+func RegisterUnion(alias string, proto interface{}) {
+	t := reflect.TypeOf(proto)
+	if t == nil || t.Kind() == reflect.Struct || t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		panic(fmt.Sprintf("hprose/io: invalid union type: %v", t))
+	}
+	name := t.Name()
+	if alias != "" {
+		name = alias
+	}
+	if name == "" {
+		panic(fmt.Sprintf("hprose/io: union type %s must have a name", t.String()))
+	}
+	info := &unionInfo{
+		name:     name,
+		t:        t,
+		metadata: makeMetadata(name, []string{unionField}),
+	}
+	unionNameMap.Store(name, info)
+	unionTypeMap.Store(t, info)
+}
+
+// GetUnionType by alias.
+func GetUnionType(alias string) reflect.Type {
+	if info := getUnionInfoByName(alias); info != nil {
+		return info.t
+	}
+	return nil
+}
+
+func getUnionInfoByName(name string) *unionInfo {
+	if info, ok := unionNameMap.Load(name); ok {
+		return info.(*unionInfo)
+	}
+	return nil
+}
+
+func getUnionInfo(t reflect.Type) *unionInfo {
+	if info, ok := unionTypeMap.Load(t); ok {
+		return info.(*unionInfo)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/union_decoder.go                                      |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/modern-go/reflect2"
+)
+
+// readUnionValue reads the value of the union object to p.
+func (dec *Decoder) readUnionValue(p interface{}) {
+	i := dec.addReference(nil)
+	dec.Decode(p)
+	if i >= 0 && dec.Error == nil {
+		dec.SetReference(i, reflect.ValueOf(p).Elem().Interface())
+	}
+	dec.leave()
+	dec.Skip()
+}
+
+func (dec *Decoder) readUnion(info structInfo) interface{} {
+	p := reflect.New(info.union)
+	dec.readUnionValue(p.Interface())
+	return p.Elem().Interface()
+}
+
+// decodeUnion decodes the union object to p, it reports a CastError if the
+// object is not a union object.
+func (dec *Decoder) decodeUnion(t reflect.Type, p interface{}) {
+	if !dec.enter() {
+		return
+	}
+	structInfo := dec.getStructInfo(dec.ReadInt())
+	if structInfo.union != nil {
+		dec.readUnionValue(p)
+		return
+	}
+	var o interface{}
+	if structInfo.fields == nil {
+		o = dec.readObjectAsMap(structInfo)
+	} else {
+		o = dec.readObject(structInfo)
+	}
+	if dec.Error == nil {
+		dec.Error = CastError{
+			Source:      reflect.TypeOf(o),
+			Destination: t,
+		}
+	}
+}
+
+// decodeNonEmptyInterface decodes the value to the interface of type t with
+// methods that p points to, the decoded value must implement t.
+func (dec *Decoder) decodeNonEmptyInterface(t reflect.Type, tag byte, p unsafe.Pointer) {
+	var o interface{}
+	dec.decodeInterface(tag, &o)
+	if dec.Error != nil {
+		return
+	}
+	target := reflect.NewAt(t, p).Elem()
+	if o == nil {
+		target.Set(reflect.Zero(t))
+		return
+	}
+	value := reflect.ValueOf(o)
+	switch {
+	case value.Type().Implements(t):
+		target.Set(value)
+	case reflect.PtrTo(value.Type()).Implements(t):
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		target.Set(ptr)
+	default:
+		dec.Error = CastError{
+			Source:      value.Type(),
+			Destination: t,
+		}
+	}
+}
+
+func (dec *Decoder) decodeNonEmptyInterfacePtr(t reflect.Type, tag byte, p unsafe.Pointer) {
+	pp := (*unsafe.Pointer)(p)
+	if tag == TagNull {
+		*pp = nil
+		return
+	}
+	et := reflect2.Type2(t.Elem())
+	ptr := et.UnsafeNew()
+	dec.decodeNonEmptyInterface(t.Elem(), tag, ptr)
+	*pp = ptr
+}
+
+// nonEmptyInterfaceDecoder is the implementation of ValueDecoder for the
+// interface with methods.
+type nonEmptyInterfaceDecoder struct {
+	t reflect.Type
+}
+
+func (valdec nonEmptyInterfaceDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	dec.decodeNonEmptyInterface(valdec.t, tag, reflect2.PtrOf(p))
+}
+
+// nonEmptyInterfacePtrDecoder is the implementation of ValueDecoder for the
+// pointer to the interface with methods.
+type nonEmptyInterfacePtrDecoder struct {
+	t reflect.Type
+}
+
+func (valdec nonEmptyInterfacePtrDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	dec.decodeNonEmptyInterfacePtr(valdec.t, tag, reflect2.PtrOf(p))
+}
@@ -0,0 +1,39 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/union_encoder.go                                      |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+// TypedUnion sets the encoder to typed union mode or not.
+//
+// In typed union mode, the values of the types registered by RegisterUnion
+// are written with their type names, see RegisterUnion for details.
+func (enc *Encoder) TypedUnion(typed bool) *Encoder {
+	enc.typedUnion = typed
+	return enc
+}
+
+// IsTypedUnion returns the encoder is in typed union mode or not.
+func (enc *Encoder) IsTypedUnion() bool {
+	return enc.typedUnion
+}
+
+func (enc *Encoder) writeUnion(info *unionInfo, v interface{}, encode func(m ValueEncoder, v interface{})) {
+	r := enc.WriteStructType(info.t, func() {
+		enc.AddReferenceCount(1)
+		enc.buf = append(enc.buf, info.metadata...)
+	})
+	enc.AddReferenceCount(1)
+	enc.WriteObjectHead(r)
+	enc.writeOtherValue(info.t, v, encode)
+	enc.WriteFoot()
+}
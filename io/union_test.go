@@ -0,0 +1,134 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/union_test.go                                         |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"testing"
+
+	"github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+type Square float64
+
+func (s Square) Area() float64 {
+	return float64(s * s)
+}
+
+type Meters int
+
+func (m Meters) Area() float64 {
+	return float64(m)
+}
+
+type Circle struct {
+	R float64
+}
+
+func (c Circle) Area() float64 {
+	return 3 * c.R * c.R
+}
+
+type Drawing struct {
+	Title  string
+	Shapes []Shape
+	Main   Shape
+}
+
+func init() {
+	io.RegisterUnion("", Square(0))
+	io.RegisterUnion("Meters", Meters(0))
+	io.Register(Circle{})
+	io.Register(Drawing{})
+}
+
+func TestEncodeTypedUnion(t *testing.T) {
+	shapes := []Shape{Square(1.5), Meters(3), Meters(4), Circle{1}}
+	data, err := io.Marshal(shapes)
+	assert.NoError(t, err)
+	assert.Equal(t, `a4{d1.5;34c6"Circle"1{s1"r"}o0{d1;}}`, string(data))
+
+	data, err = io.Formatter{Simple: true, TypedUnion: true}.Marshal(shapes)
+	assert.NoError(t, err)
+	assert.Equal(t, `a4{c6"Square"1{s5"value"}o0{d1.5;}c6"Meters"1{s5"value"}o1{3}o1{4}c6"Circle"1{s1"r"}o2{d1;}}`, string(data))
+}
+
+func TestDecodeTypedUnion(t *testing.T) {
+	shapes := []Shape{Square(1.5), Meters(3), Meters(4), Circle{1}, nil}
+	f := io.Formatter{TypedUnion: true}
+	data, err := f.Marshal(shapes)
+	assert.NoError(t, err)
+
+	var result []Shape
+	assert.NoError(t, f.Unmarshal(data, &result))
+	assert.Equal(t, []Shape{Square(1.5), Meters(3), Meters(4), &Circle{1}, nil}, result)
+
+	var values []interface{}
+	assert.NoError(t, f.Unmarshal(data, &values))
+	assert.Equal(t, []interface{}{Square(1.5), Meters(3), Meters(4), &Circle{1}, nil}, values)
+
+	var meters []Meters
+	data, err = f.Marshal([]Shape{Meters(3), Meters(4)})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Unmarshal(data, &meters))
+	assert.Equal(t, []Meters{3, 4}, meters)
+
+	var n int
+	assert.NoError(t, io.Unmarshal([]byte(`c6"Meters"1{s5"value"}o0{5}`), &n))
+	assert.Equal(t, 5, n)
+}
+
+func TestDecodeTypedUnionField(t *testing.T) {
+	drawing := Drawing{
+		Title:  "test",
+		Shapes: []Shape{Meters(1), Square(2)},
+		Main:   Meters(5),
+	}
+	f := io.Formatter{Simple: true, TypedUnion: true}
+	data, err := f.Marshal(drawing)
+	assert.NoError(t, err)
+	var result Drawing
+	assert.NoError(t, f.Unmarshal(data, &result))
+	assert.Equal(t, drawing, result)
+
+	var p *Shape
+	data, err = f.Marshal(Square(3))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Unmarshal(data, &p))
+	assert.Equal(t, Shape(Square(3)), *p)
+}
+
+func TestDecodeTypedUnionError(t *testing.T) {
+	var shape Shape
+	err := io.Unmarshal([]byte(`s5"hello"`), &shape)
+	assert.EqualError(t, err, "hprose/io: can not cast string to io_test.Shape")
+	var n int
+	err = io.Unmarshal([]byte(`c6"Circle"1{s1"r"}o0{1}`), &n)
+	assert.EqualError(t, err, "hprose/io: can not cast *io_test.Circle to int")
+}
+
+func TestRegisterUnionPanic(t *testing.T) {
+	assert.Panics(t, func() {
+		io.RegisterUnion("", Circle{})
+	})
+	assert.Panics(t, func() {
+		io.RegisterUnion("", []int{})
+	})
+	assert.Equal(t, io.GetUnionType("Meters"), io.GetUnionType("Meters"))
+	assert.Nil(t, io.GetUnionType("Unknown"))
+}
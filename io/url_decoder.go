@@ -0,0 +1,57 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/url_decoder.go                                        |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"net/url"
+	"reflect"
+
+	"github.com/modern-go/reflect2"
+)
+
+func (dec *Decoder) stringToURL(s string) url.URL {
+	u, err := url.Parse(s)
+	if err != nil {
+		if dec.Error == nil {
+			dec.Error = err
+		}
+		return url.URL{}
+	}
+	return *u
+}
+
+func (dec *Decoder) decodeURL(t reflect.Type, tag byte, p *url.URL) {
+	switch tag {
+	case TagNull, TagEmpty:
+		*p = url.URL{}
+	default:
+		if s, ok := dec.decodeText(t, tag, p); ok {
+			*p = dec.stringToURL(s)
+		}
+	}
+}
+
+// urlDecoder is the implementation of ValueDecoder for url.URL.
+type urlDecoder struct{}
+
+func (urlDecoder) Decode(dec *Decoder, p interface{}, tag byte) {
+	dec.decodeURL(reflect.TypeOf(p).Elem(), tag, (*url.URL)(reflect2.PtrOf(p)))
+}
+
+func init() {
+	registerValueDecoder(urlType, urlDecoder{})
+	RegisterConverter(stringType, urlType, func(dec *Decoder, o interface{}, p interface{}) {
+		*(*url.URL)(reflect2.PtrOf(p)) = dec.stringToURL(*(*string)(reflect2.PtrOf(o)))
+	})
+}
@@ -0,0 +1,48 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/url_decoder_test.go                                   |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeURL(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	src, _ := url.Parse("https://hprose.com/path?q=1")
+	enc.Encode(src)
+	enc.Encode(src)
+	enc.Encode(nil)
+	enc.Encode("")
+	enc.Encode("%")
+	dec := NewDecoder(([]byte)(sb.String())).Simple(false)
+	var u url.URL
+	dec.Decode(&u)
+	assert.Equal(t, *src, u)
+	var pu *url.URL
+	dec.Decode(&pu)
+	assert.Equal(t, src, pu)
+	dec.Decode(&pu)
+	assert.Nil(t, pu)
+	dec.Decode(&u)
+	assert.Equal(t, url.URL{}, u)
+	assert.NoError(t, dec.Error)
+	dec.Decode(&u)
+	assert.Error(t, dec.Error)
+}
@@ -0,0 +1,28 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/url_encoder.go                                        |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"net/url"
+	"unsafe"
+)
+
+// url.URL is encoded as a string in its textual form.
+func urlToString(p unsafe.Pointer) (string, bool) {
+	return (*url.URL)(p).String(), true
+}
+
+func init() {
+	RegisterValueEncoder((*url.URL)(nil), textEncoder(urlToString))
+}
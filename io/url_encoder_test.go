@@ -0,0 +1,35 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/url_encoder_test.go                                   |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeURL(t *testing.T) {
+	sb := &strings.Builder{}
+	enc := NewEncoder(sb).Simple(false)
+	u, _ := url.Parse("https://hprose.com/path?q=1")
+	assert.NoError(t, enc.Encode(u))
+	assert.NoError(t, enc.Encode(*u))
+	assert.NoError(t, enc.Encode(url.URL{}))
+	assert.NoError(t, enc.Encode((*url.URL)(nil)))
+	assert.Equal(t, `s27"https://hprose.com/path?q=1"`+
+		`s27"https://hprose.com/path?q=1"en`, sb.String())
+}
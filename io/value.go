@@ -0,0 +1,422 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/value.go                                              |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind is the kind of hprose value held by Value.
+type Kind uint8
+
+// Kind values.
+const (
+	KindNull   Kind = iota // n
+	KindEmpty              // e
+	KindBool               // t, f
+	KindInt                // 0-9, i
+	KindLong               // l
+	KindDouble             // d, N, I
+	KindChar               // u
+	KindString             // s
+	KindBytes              // b
+	KindGUID               // g
+	KindDate               // D
+	KindTime               // T
+	KindList               // a
+	KindMap                // m
+	KindObject             // c, o
+)
+
+var kindNames = [...]string{
+	KindNull:   "null",
+	KindEmpty:  "empty",
+	KindBool:   "bool",
+	KindInt:    "int",
+	KindLong:   "long",
+	KindDouble: "double",
+	KindChar:   "char",
+	KindString: "string",
+	KindBytes:  "bytes",
+	KindGUID:   "guid",
+	KindDate:   "date",
+	KindTime:   "time",
+	KindList:   "list",
+	KindMap:    "map",
+	KindObject: "object",
+}
+
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Value is a hprose value decoded without Go types.
+//
+// Value keeps what decoding into interface{} loses: the exact kind of every
+// tag, the class names of objects, the order of map entries and object
+// fields, and the identity of referenced values. A *Value read from hprose
+// data is re-encoded byte-identically until it is modified. The values
+// referenced more than once are the same *Value, and they are written as
+// references again.
+//
+// The zero Value and a nil *Value are null.
+type Value struct {
+	kind Kind
+	// tag is the tag of int Value, a digit or TagInteger.
+	tag byte
+	// text is the text of bool, int, long, double ("NaN", "+Inf" and "-Inf"
+	// for NaN and Infinity), char and string, and the original data of date
+	// and time read from hprose data.
+	text  string
+	bytes []byte
+	guid  uuid.UUID
+	time  time.Time
+	class string
+	// keys are the map keys or the object field names.
+	keys []*Value
+	// values are the list items, the map values or the object field values.
+	values []*Value
+}
+
+// NewNull returns a null Value.
+func NewNull() *Value {
+	return &Value{kind: KindNull}
+}
+
+// NewBool returns a bool Value.
+func NewBool(b bool) *Value {
+	return &Value{kind: KindBool, text: strconv.FormatBool(b)}
+}
+
+// NewInt returns an int Value, or a long Value if i is out of the range of int32.
+func NewInt(i int64) *Value {
+	if i > math.MaxInt32 || i < math.MinInt32 {
+		return &Value{kind: KindLong, text: strconv.FormatInt(i, 10)}
+	}
+	if i >= 0 && i <= 9 {
+		return &Value{kind: KindInt, tag: byte('0' + i), text: strconv.FormatInt(i, 10)}
+	}
+	return &Value{kind: KindInt, tag: TagInteger, text: strconv.FormatInt(i, 10)}
+}
+
+// NewBigInt returns a long Value.
+func NewBigInt(i *big.Int) *Value {
+	return &Value{kind: KindLong, text: i.String()}
+}
+
+// NewDouble returns a double Value.
+func NewDouble(f float64) *Value {
+	return &Value{kind: KindDouble, text: strconv.FormatFloat(f, 'g', -1, 64)}
+}
+
+// NewString returns an empty, char or string Value as the encoder writes s.
+func NewString(s string) *Value {
+	switch utf16Length(s) {
+	case 0:
+		return &Value{kind: KindEmpty}
+	case 1:
+		return &Value{kind: KindChar, text: s}
+	}
+	return &Value{kind: KindString, text: s}
+}
+
+// NewBytes returns a bytes Value.
+func NewBytes(data []byte) *Value {
+	return &Value{kind: KindBytes, bytes: data}
+}
+
+// NewGUID returns a guid Value.
+func NewGUID(u uuid.UUID) *Value {
+	return &Value{kind: KindGUID, guid: u}
+}
+
+// NewTime returns a date or time Value as the encoder writes t.
+func NewTime(t time.Time) *Value {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	if year == 1970 && month == 1 && day == 1 && (hour != 0 || min != 0 || sec != 0 || t.Nanosecond() != 0) {
+		return &Value{kind: KindTime, time: t}
+	}
+	return &Value{kind: KindDate, time: t}
+}
+
+// NewList returns a list Value of items.
+func NewList(items ...*Value) *Value {
+	return &Value{kind: KindList, values: items}
+}
+
+// NewMap returns an empty map Value.
+func NewMap() *Value {
+	return &Value{kind: KindMap}
+}
+
+// NewObject returns an object Value of class without fields.
+func NewObject(class string) *Value {
+	return &Value{kind: KindObject, class: class}
+}
+
+// ValueOf returns the Value of v by encoding and decoding it with references.
+func ValueOf(v interface{}) (*Value, error) {
+	if value, ok := v.(*Value); ok {
+		return value, nil
+	}
+	data, err := Formatter{}.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value *Value
+	err = Formatter{}.Unmarshal(data, &value)
+	return value, err
+}
+
+// Decode decodes v to the value that p points to by encoding and decoding
+// it with references.
+func (v *Value) Decode(p interface{}) error {
+	data, err := Formatter{}.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return Formatter{}.Unmarshal(data, p)
+}
+
+// Kind returns the kind of v.
+func (v *Value) Kind() Kind {
+	if v == nil {
+		return KindNull
+	}
+	return v.kind
+}
+
+func (v *Value) kindError(method string) error {
+	return errors.New("hprose/io: call of Value." + method + " on " + v.Kind().String() + " Value")
+}
+
+func (v *Value) mustBe(method string, kinds ...Kind) {
+	for _, kind := range kinds {
+		if v.Kind() == kind {
+			return
+		}
+	}
+	panic(v.kindError(method))
+}
+
+// Bool returns true if v is true.
+func (v *Value) Bool() bool {
+	return v.Kind() == KindBool && v.text == "true"
+}
+
+// Int returns the int64 value of int or long Value.
+func (v *Value) Int() (int64, error) {
+	switch v.Kind() {
+	case KindInt, KindLong:
+		return strconv.ParseInt(v.text, 10, 64)
+	}
+	return 0, v.kindError("Int")
+}
+
+// BigInt returns the *big.Int value of int or long Value.
+func (v *Value) BigInt() (*big.Int, error) {
+	switch v.Kind() {
+	case KindInt, KindLong:
+		if i, ok := new(big.Int).SetString(v.text, 10); ok {
+			return i, nil
+		}
+		return nil, DecodeError("hprose/io: invalid integer " + v.text)
+	}
+	return nil, v.kindError("BigInt")
+}
+
+// Float returns the float64 value of int, long or double Value.
+func (v *Value) Float() (float64, error) {
+	switch v.Kind() {
+	case KindInt, KindLong, KindDouble:
+		return strconv.ParseFloat(v.text, 64)
+	}
+	return 0, v.kindError("Float")
+}
+
+// Text returns the text of bool, int, long, double, char or string Value,
+// or the original data of date or time Value read from hprose data, for
+// other kinds it returns "".
+func (v *Value) Text() string {
+	if v == nil {
+		return ""
+	}
+	return v.text
+}
+
+// Bytes returns the data of bytes Value.
+func (v *Value) Bytes() []byte {
+	if v.Kind() != KindBytes {
+		return nil
+	}
+	return v.bytes
+}
+
+// GUID returns the uuid.UUID of guid Value.
+func (v *Value) GUID() uuid.UUID {
+	if v.Kind() != KindGUID {
+		return uuid.Nil
+	}
+	return v.guid
+}
+
+// Time returns the time.Time of date or time Value.
+func (v *Value) Time() time.Time {
+	switch v.Kind() {
+	case KindDate, KindTime:
+		return v.time
+	}
+	return time.Time{}
+}
+
+// Class returns the class name of object Value.
+func (v *Value) Class() string {
+	if v.Kind() != KindObject {
+		return ""
+	}
+	return v.class
+}
+
+// Len returns the number of items of list Value, or the number of entries
+// of map or object Value.
+func (v *Value) Len() int {
+	if v == nil {
+		return 0
+	}
+	return len(v.values)
+}
+
+// Index returns the ith item of list Value, or the value of the ith entry
+// of map or object Value.
+func (v *Value) Index(i int) *Value {
+	v.mustBe("Index", KindList, KindMap, KindObject)
+	return v.values[i]
+}
+
+// Key returns the key of the ith entry of map or object Value.
+func (v *Value) Key(i int) *Value {
+	v.mustBe("Key", KindMap, KindObject)
+	return v.keys[i]
+}
+
+// SetIndex sets the ith item of list Value, or the value of the ith entry
+// of map or object Value.
+func (v *Value) SetIndex(i int, e *Value) {
+	v.mustBe("SetIndex", KindList, KindMap, KindObject)
+	v.values[i] = e
+}
+
+// Append appends items to list Value.
+func (v *Value) Append(items ...*Value) {
+	v.mustBe("Append", KindList)
+	v.values = append(v.values, items...)
+}
+
+func isText(kind Kind) bool {
+	return kind <= KindString
+}
+
+// find returns the index of the entry with key, the keys of bool, number and
+// string kinds are compared by their kinds and texts, other keys are
+// compared by their identities.
+func (v *Value) find(key *Value) int {
+	for i, k := range v.keys {
+		if k == key || isText(key.Kind()) && k.Kind() == key.Kind() && k.Text() == key.Text() {
+			return i
+		}
+	}
+	return -1
+}
+
+// findString returns the index of the entry with the empty, char or string key.
+func (v *Value) findString(key string) int {
+	for i, k := range v.keys {
+		switch k.Kind() {
+		case KindEmpty, KindChar, KindString:
+			if k.text == key {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Get returns the value of the entry with string key of map or object Value,
+// or nil if the entry does not exist.
+func (v *Value) Get(key string) *Value {
+	v.mustBe("Get", KindMap, KindObject)
+	if i := v.findString(key); i >= 0 {
+		return v.values[i]
+	}
+	return nil
+}
+
+// Set sets the value of the entry with string key of map or object Value,
+// the entry is appended if it does not exist.
+func (v *Value) Set(key string, e *Value) {
+	v.mustBe("Set", KindMap, KindObject)
+	if i := v.findString(key); i >= 0 {
+		v.values[i] = e
+		return
+	}
+	k := NewString(key)
+	if v.kind == KindObject {
+		// the field names of objects are always written as strings.
+		k.kind = KindString
+	}
+	v.appendEntry(k, e)
+}
+
+// Put sets the value of the entry with key of map Value, the entry is
+// appended if it does not exist. The keys are compared by their kinds and
+// texts.
+func (v *Value) Put(key *Value, e *Value) {
+	v.mustBe("Put", KindMap)
+	if i := v.find(key); i >= 0 {
+		v.values[i] = e
+		return
+	}
+	v.appendEntry(key, e)
+}
+
+func (v *Value) appendEntry(key *Value, e *Value) {
+	// the field names may be shared by the objects of the same class.
+	n := len(v.keys)
+	v.keys = append(v.keys[:n:n], key)
+	v.values = append(v.values, e)
+}
+
+// Delete deletes the entry with string key of map or object Value,
+// it returns false if the entry does not exist.
+func (v *Value) Delete(key string) bool {
+	v.mustBe("Delete", KindMap, KindObject)
+	i := v.findString(key)
+	if i < 0 {
+		return false
+	}
+	keys := make([]*Value, 0, len(v.keys)-1)
+	v.keys = append(append(keys, v.keys[:i]...), v.keys[i+1:]...)
+	v.values = append(v.values[:i], v.values[i+1:]...)
+	return true
+}
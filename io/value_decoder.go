@@ -6,7 +6,7 @@
 |                                                          |
 | io/value_decoder.go                                      |
 |                                                          |
-| LastModified: Feb 18, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -30,8 +30,13 @@ func registerValueDecoder(t reflect.Type, valdec ValueDecoder) {
 }
 
 // RegisterValueDecoder valdec.
+// If type(v) is a struct registered by Register or RegisterName, valdec replaces its decoder too.
 func RegisterValueDecoder(v interface{}, valdec ValueDecoder) {
-	registerValueDecoder(reflect.TypeOf(v), valdec)
+	t := reflect.TypeOf(v)
+	if getNamedStructDecoder(t) != nil {
+		registerNamedStructDecoder(t, valdec)
+	}
+	registerValueDecoder(t, valdec)
 }
 
 func getRegisteredValueDecoder(t reflect.Type) (valdec ValueDecoder) {
@@ -84,7 +89,7 @@ func init() {
 		reflect.Array:         getArrayDecoder,
 		reflect.Chan:          invalidDecoder,
 		reflect.Func:          invalidDecoder,
-		reflect.Interface:     func(t reflect.Type) ValueDecoder { return interfaceDecoder{} },
+		reflect.Interface:     getInterfaceDecoder,
 		reflect.Map:           getMapDecoder,
 		reflect.Ptr:           getPtrDecoder,
 		reflect.Slice:         getSliceDecoder,
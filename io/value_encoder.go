@@ -6,7 +6,7 @@
 |                                                          |
 | io/value_encoder.go                                      |
 |                                                          |
-| LastModified: Mar 5, 2022                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -61,8 +61,13 @@ func checkType(v interface{}) reflect.Type {
 }
 
 // RegisterValueEncoder of type(v).
+// If type(v) is a struct registered by Register or RegisterName, valenc replaces its encoder too.
 func RegisterValueEncoder(v interface{}, valenc ValueEncoder) {
-	registerValueEncoder(checkType(v), valenc)
+	t := checkType(v)
+	if getNamedStructEncoder(t) != nil {
+		registerNamedStructEncoder(t, valenc)
+	}
+	registerValueEncoder(t, valenc)
 }
 
 // GetValueEncoder of type(v).
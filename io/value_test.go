@@ -0,0 +1,164 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| io/value_test.go                                         |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package io_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/hprose/hprose-golang/v3/io"
+	"github.com/stretchr/testify/assert"
+)
+
+func readValue(t *testing.T, data string) *Value {
+	var v *Value
+	assert.NoError(t, Formatter{}.Unmarshal([]byte(data), &v), data)
+	return v
+}
+
+func writeValue(t *testing.T, v interface{}) string {
+	data, err := Formatter{}.Marshal(v)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestValueRoundTrip(t *testing.T) {
+	tests := []string{
+		`n`, `e`, `t`, `f`, `5`, `i5;`, `i-123;`, `l123;`, `l12345678901234567890;`,
+		`d1.50;`, `N`, `I+`, `I-`, `a1{ux}`, `s1"x"`, `s5"hello"`, `b3"abc"`, `b""`,
+		`g{f47ac10b-58cc-4372-a567-0e02b2c3d479}`,
+		`D20201231Z`, `D20201231T235959.123Z`, `D19700101T120000;`, `T120000.123456Z`,
+		`D20201231T120000.123000Z`, `D20201231T000000Z`, `T120000.000000000;`, `a2{D20201231Zr1;}`,
+		`a{}`, `a3{1s2"ab"r0;}`, `m{}`, `m2{ux1s2"ab"a1{r1;}}`,
+		`a2{s2"ab"r0;}`, `a1{r0;}`,
+		`c5"Point"2{s1"x"s1"y"}o0{12}`,
+		`a2{c5"Point"2{s1"x"s1"y"}o0{12}o0{34}}`,
+		`a3{c5"Point"2{s1"x"s1"y"}o0{1r0;}r3;r1;}`,
+	}
+	for _, data := range tests {
+		v := readValue(t, data)
+		assert.Equal(t, data, writeValue(t, v), data)
+	}
+}
+
+func TestValueInvalidTime(t *testing.T) {
+	var v *Value
+	assert.EqualError(t, Formatter{}.Unmarshal([]byte(`D2020x`), &v), `hprose/io: invalid date "D2020x"`)
+}
+
+func TestValueKinds(t *testing.T) {
+	v := readValue(t, `a9{5l123;d1.5;uxs2"ab"b1"a"g{f47ac10b-58cc-4372-a567-0e02b2c3d479}D20201231ZT120000Z}`)
+	assert.Equal(t, KindList, v.Kind())
+	assert.Equal(t, 9, v.Len())
+	kinds := []Kind{KindInt, KindLong, KindDouble, KindChar, KindString, KindBytes, KindGUID, KindDate, KindTime}
+	for i, kind := range kinds {
+		assert.Equal(t, kind, v.Index(i).Kind())
+	}
+	i, err := v.Index(0).Int()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), i)
+	b, err := v.Index(1).BigInt()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(123), b)
+	f, err := v.Index(2).Float()
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, f)
+	assert.Equal(t, "x", v.Index(3).Text())
+	assert.Equal(t, "ab", v.Index(4).Text())
+	assert.Equal(t, []byte("a"), v.Index(5).Bytes())
+	assert.Equal(t, uuid.MustParse("f47ac10b-58cc-4372-a567-0e02b2c3d479"), v.Index(6).GUID())
+	assert.Equal(t, time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), v.Index(7).Time())
+	assert.Equal(t, time.Date(1970, 1, 1, 12, 0, 0, 0, time.UTC), v.Index(8).Time())
+	_, err = v.Index(3).Int()
+	assert.EqualError(t, err, "hprose/io: call of Value.Int on char Value")
+	assert.Equal(t, "object", KindObject.String())
+	assert.Equal(t, KindNull, (*Value)(nil).Kind())
+}
+
+func TestValueObject(t *testing.T) {
+	v := readValue(t, `a2{c5"Point"2{s1"x"s1"y"}o0{12}o0{34}}`)
+	p1, p2 := v.Index(0), v.Index(1)
+	assert.Equal(t, "Point", p1.Class())
+	assert.Equal(t, "x", p1.Key(0).Text())
+	i, _ := p2.Get("y").Int()
+	assert.Equal(t, int64(4), i)
+	p2.Set("y", NewInt(5))
+	p2.Set("z", NewInt(6))
+	assert.Nil(t, p1.Get("z"))
+	assert.Equal(t, `a2{c5"Point"2{s1"x"s1"y"}o0{12}c5"Point"3{r1;r2;s1"z"}o1{356}}`, writeValue(t, v))
+	assert.True(t, p2.Delete("z"))
+	assert.False(t, p2.Delete("z"))
+	assert.Equal(t, `a2{c5"Point"2{s1"x"s1"y"}o0{12}o0{35}}`, writeValue(t, v))
+}
+
+func TestValueReference(t *testing.T) {
+	v := readValue(t, `a3{s2"ab"a1{r2;}r1;}`)
+	assert.Same(t, v.Index(0), v.Index(2))
+	assert.Same(t, v.Index(1), v.Index(1).Index(0))
+	v.Index(1).Append(NewNull())
+	assert.Equal(t, `a3{s2"ab"a2{r2;n}r1;}`, writeValue(t, v))
+}
+
+func TestValueBuild(t *testing.T) {
+	m := NewMap()
+	m.Set("name", NewString("hprose"))
+	m.Set("n", NewInt(1<<40))
+	m.Put(NewInt(1), NewDouble(1.5))
+	m.Put(NewInt(1), NewBool(true))
+	list := NewList(NewString(""), NewNull())
+	list.Append(list)
+	m.Set("list", list)
+	m.Set("date", NewTime(time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)))
+	m.Set("time", NewTime(time.Date(1970, 1, 1, 12, 0, 0, 0, time.UTC)))
+	m.Set("guid", NewGUID(uuid.Nil))
+	m.Set("bytes", NewBytes([]byte("a")))
+	assert.Equal(t, `m8{s4"name"s6"hprose"unl1099511627776;1ts4"list"a3{enr4;}s4"date"D20201231Zs4"time"T120000Zs4"guid"g{00000000-0000-0000-0000-000000000000}s5"bytes"b1"a"}`, writeValue(t, m))
+	obj := NewObject("User")
+	obj.Set("name", NewString("Tom"))
+	assert.Equal(t, `c4"User"1{s4"name"}o0{s3"Tom"}`, writeValue(t, obj))
+	assert.Panics(t, func() { obj.Append(NewNull()) })
+}
+
+type valueTestUser struct {
+	Name string
+	Age  int
+}
+
+func TestValueConvert(t *testing.T) {
+	v, err := ValueOf(valueTestUser{"Tom", 18})
+	assert.NoError(t, err)
+	assert.Equal(t, KindObject, v.Kind())
+	v.Set("age", NewInt(19))
+	var user valueTestUser
+	assert.NoError(t, v.Decode(&user))
+	assert.Equal(t, valueTestUser{"Tom", 19}, user)
+	var m map[string]interface{}
+	assert.NoError(t, v.Decode(&m))
+	assert.Equal(t, map[string]interface{}{"name": "Tom", "age": 19}, m)
+}
+
+func TestValueInStruct(t *testing.T) {
+	type envelope struct {
+		ID      int
+		Payload *Value
+	}
+	data := writeValue(t, envelope{1, NewList(NewString("hello"), NewInt(2))})
+	var e envelope
+	assert.NoError(t, Formatter{}.Unmarshal([]byte(data), &e))
+	assert.Equal(t, 1, e.ID)
+	assert.Equal(t, "hello", e.Payload.Index(0).Text())
+	assert.Equal(t, data, writeValue(t, e))
+}
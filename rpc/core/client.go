@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/core/client.go                                       |
 |                                                          |
-| LastModified: Mar 6, 2022                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -58,6 +58,10 @@ type Client struct {
 	transports     map[string]Transport
 	cancelFuncs    *list.List
 	cancelLock     sync.Mutex
+	endpoints      []Endpoint
+	listeners      []EndpointsListener
+	endpointsLock  sync.RWMutex
+	listenersLock  sync.Mutex
 }
 
 // NewClient returns an instance of Client.
@@ -87,21 +91,62 @@ func (c *Client) GetTransport(name string) Transport {
 
 // SetURI for client.
 func (c *Client) SetURI(uri ...string) {
-	c.URLs = nil
-	for _, u := range uri {
-		if url, err := url.Parse(u); err == nil {
-			c.URLs = append(c.URLs, url)
-		}
+	c.SetEndpoints(MakeEndpoints(uri...))
+}
+
+// SetEndpoints replaces the endpoints and the URLs of the client, and pushes
+// the endpoints to the EndpointsListener plugins. The invocations which have
+// already started keep using the URLs they started with. The listeners are
+// called after the endpoints are replaced, so they can call Endpoints.
+func (c *Client) SetEndpoints(endpoints []Endpoint) {
+	endpoints = append([]Endpoint(nil), endpoints...)
+	urls := make([]*url.URL, len(endpoints))
+	for i := range endpoints {
+		urls[i] = endpoints[i].URL
+	}
+	c.listenersLock.Lock()
+	defer c.listenersLock.Unlock()
+	c.endpointsLock.Lock()
+	c.endpoints = endpoints
+	c.URLs = urls
+	listeners := append([]EndpointsListener(nil), c.listeners...)
+	c.endpointsLock.Unlock()
+	for _, listener := range listeners {
+		listener.SetEndpoints(endpoints)
 	}
 }
 
+// Endpoints returns the endpoints of the client.
+func (c *Client) Endpoints() []Endpoint {
+	c.endpointsLock.RLock()
+	defer c.endpointsLock.RUnlock()
+	return append([]Endpoint(nil), c.endpoints...)
+}
+
+// Discover watches the endpoints by discovery until ctx is done, and applies
+// them to the client by SetEndpoints. It blocks, so it is usually called in
+// a new goroutine.
+func (c *Client) Discover(ctx context.Context, discovery Discovery) error {
+	return discovery.Watch(ctx, c.SetEndpoints)
+}
+
+func (c *Client) getURLs() []*url.URL {
+	c.endpointsLock.RLock()
+	defer c.endpointsLock.RUnlock()
+	return c.URLs
+}
+
 // ShuffleURLs sorts the URLs in random order.
 func (c *Client) ShuffleURLs() *Client {
+	c.endpointsLock.Lock()
+	defer c.endpointsLock.Unlock()
 	if n := len(c.URLs); n > 0 {
+		urls := append([]*url.URL(nil), c.URLs...)
 		rand.Seed(time.Now().UTC().UnixNano())
 		rand.Shuffle(n, func(i, j int) {
-			c.URLs[i], c.URLs[j] = c.URLs[j], c.URLs[i]
+			urls[i], urls[j] = urls[j], urls[i]
 		})
+		c.URLs = urls
 	}
 	return c
 }
@@ -200,7 +245,8 @@ func (c *Client) Abort() {
 	wg.Wait()
 }
 
-// Use plugin handlers.
+// Use plugin handlers. The current endpoints are pushed to the plugins which
+// implement EndpointsListener, if the client has any endpoints.
 func (c *Client) Use(handler ...PluginHandler) *Client {
 	invokeHandlers, ioHandlers := SeparatePluginHandlers(handler)
 	if len(invokeHandlers) > 0 {
@@ -209,6 +255,23 @@ func (c *Client) Use(handler ...PluginHandler) *Client {
 	if len(ioHandlers) > 0 {
 		c.ioManager.Use(ioHandlers...)
 	}
+	c.listenersLock.Lock()
+	defer c.listenersLock.Unlock()
+	var listeners []EndpointsListener
+	c.endpointsLock.Lock()
+	for _, h := range handler {
+		if listener, ok := h.(EndpointsListener); ok {
+			c.listeners = append(c.listeners, listener)
+			listeners = append(listeners, listener)
+		}
+	}
+	endpoints := c.endpoints
+	c.endpointsLock.Unlock()
+	if len(endpoints) > 0 {
+		for _, listener := range listeners {
+			listener.SetEndpoints(endpoints)
+		}
+	}
 	return c
 }
 
@@ -221,5 +284,16 @@ func (c *Client) Unuse(handler ...PluginHandler) *Client {
 	if len(ioHandlers) > 0 {
 		c.ioManager.Unuse(ioHandlers...)
 	}
+	c.endpointsLock.Lock()
+	for _, h := range handler {
+		if listener, ok := h.(EndpointsListener); ok {
+			for i := len(c.listeners) - 1; i >= 0; i-- {
+				if c.listeners[i] == listener {
+					c.listeners = append(c.listeners[:i], c.listeners[i+1:]...)
+				}
+			}
+		}
+	}
+	c.endpointsLock.Unlock()
 	return c
 }
@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/core/client_codec.go                                 |
 |                                                          |
-| LastModified: Feb 18, 2024                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -14,6 +14,8 @@
 package core
 
 import (
+	"time"
+
 	"github.com/hprose/hprose-golang/v3/io"
 	"github.com/modern-go/reflect2"
 )
@@ -25,19 +27,39 @@ type ClientCodec interface {
 }
 
 type clientCodec struct {
-	Simple bool
+	Simple         bool
+	SimpleFallback bool
+	Canonical      bool
+	TypedUnion     bool
+	Location       *time.Location
+	Interner       io.StringInterner
+	Arena          bool
+	Cipher         io.Cipher
+	io.TimeZoneMode
 	io.LongType
 	io.RealType
 	io.MapType
 	io.StructType
 	io.ListType
+	io.Limits
 }
 
 // Encode request.
 func (c clientCodec) Encode(name string, args []interface{}, context *ClientContext) ([]byte, error) {
-	encoder := io.GetEncoder().Simple(c.Simple)
+	request, err := c.encode(name, args, context, c.Simple)
+	if c.Simple && c.SimpleFallback && io.IsCircularReference(err) {
+		context.RequestHeaders().Del("simple")
+		return c.encode(name, args, context, false)
+	}
+	return request, err
+}
+
+func (c clientCodec) encode(name string, args []interface{}, context *ClientContext, simple bool) ([]byte, error) {
+	encoder := io.GetEncoder().Simple(simple).Canonical(c.Canonical).TypedUnion(c.TypedUnion)
+	encoder.TimeZoneMode = c.TimeZoneMode
+	encoder.Cipher = c.Cipher
 	defer io.FreeEncoder(encoder)
-	if c.Simple {
+	if simple {
 		context.RequestHeaders().Set("simple", true)
 	}
 	if context.HasRequestHeaders() {
@@ -64,6 +86,11 @@ func (c clientCodec) Decode(response []byte, context *ClientContext) (result []i
 	decoder.MapType = c.MapType
 	decoder.StructType = c.StructType
 	decoder.ListType = c.ListType
+	decoder.Limits = c.Limits
+	decoder.Location = c.Location
+	decoder.Interner = c.Interner
+	decoder.Arena = c.Arena
+	decoder.Cipher = c.Cipher
 	tag := decoder.NextByte()
 	if tag == io.TagHeader {
 		var h map[string]interface{}
@@ -89,7 +116,7 @@ func (c clientCodec) Decode(response []byte, context *ClientContext) (result []i
 			tag = decoder.NextByte()
 			count := 1
 			if tag == io.TagList {
-				count = decoder.ReadInt()
+				count = decoder.ReadCount()
 				decoder.AddReference(nil)
 				for i := 0; i < n && i < count; i++ {
 					results[i] = decoder.Read(returnType[i])
@@ -112,6 +139,10 @@ func (c clientCodec) Decode(response []byte, context *ClientContext) (result []i
 			err = decoder.Error
 		case errstr == "timeout":
 			err = ErrTimeout
+		case errstr == "rate limited":
+			err = ErrRateLimited
+		case errstr == "overloaded":
+			err = ErrOverloaded
 		default:
 			err = io.DecodeError(errstr)
 		}
@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/core/client_codec_test.go                            |
 |                                                          |
-| LastModified: Apr 27, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -16,7 +16,9 @@ package core_test
 import (
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/hprose/hprose-golang/v3/io"
 	. "github.com/hprose/hprose-golang/v3/rpc/core"
 	"github.com/stretchr/testify/assert"
 )
@@ -94,3 +96,52 @@ func TestClientCodecDecode(t *testing.T) {
 	assert.Nil(t, result)
 	assert.EqualError(t, err, "hprose/rpc/core: invalid response:\r\n"+`{code:200,msg:"ok"}`)
 }
+
+func TestClientCodecDecodeWithLimits(t *testing.T) {
+	context := NewClientContext()
+	context.ReturnType = []reflect.Type{reflect.TypeOf("")}
+	response := ([]byte)(`Rs12"hello World!"z`)
+	limits := io.Limits{MaxStringLength: 5}
+	_, err := NewClientCodec(WithLimits(limits)).Decode(response, context)
+	assert.Equal(t, io.ErrStringTooLong, err)
+}
+
+type cycleNode struct {
+	Name string
+	Next *cycleNode
+}
+
+func TestClientCodecSimpleFallback(t *testing.T) {
+	node := &cycleNode{Name: "a"}
+	node.Next = node
+	context := NewClientContext()
+	_, err := NewClientCodec(WithSimple(true)).Encode("hello", []interface{}{node}, context)
+	assert.True(t, io.IsCircularReference(err))
+	context = NewClientContext()
+	result, err := NewClientCodec(WithSimple(true), WithSimpleFallback(true)).Encode("hello", []interface{}{node}, context)
+	assert.NoError(t, err)
+	assert.Equal(t, `Cs5"hello"a1{c9"cycleNode"2{s4"name"s4"next"}o0{uar3;}}z`, string(result))
+	assert.False(t, context.RequestHeaders().GetBool("simple"))
+}
+
+func TestClientCodecCanonical(t *testing.T) {
+	codec := NewClientCodec(WithSimple(true), WithCanonical(true))
+	m := map[string]int{"c": 3, "a": 1, "b": 2}
+	result, err := codec.Encode("hello", []interface{}{m}, NewClientContext())
+	assert.NoError(t, err)
+	assert.Equal(t, `Hm1{s6"simple"t}Cs5"hello"a1{m3{ua1ub2uc3}}z`, string(result))
+}
+
+func TestClientCodecTimeZone(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	codec := NewClientCodec(WithSimple(true), WithTimeZoneMode(io.TimeZoneUTC), WithLocation(loc))
+	t1 := time.Date(2021, 6, 5, 18, 57, 32, 0, loc)
+	request, err := codec.Encode("hello", []interface{}{t1}, NewClientContext())
+	assert.NoError(t, err)
+	assert.Equal(t, `Hm1{s6"simple"t}Cs5"hello"a1{D20210605T105732Z}z`, string(request))
+	context := NewClientContext()
+	context.ReturnType = []reflect.Type{reflect.TypeOf(t1)}
+	result, err := codec.Decode([]byte(`RD20210605T185732;z`), context)
+	assert.NoError(t, err)
+	assert.Equal(t, t1, result[0])
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/core/client_context.go                               |
 |                                                          |
-| LastModified: Feb 18, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -27,6 +27,7 @@ type ClientContext struct {
 	ReturnType []reflect.Type
 	Timeout    time.Duration
 	client     *Client
+	urls       []*url.URL
 }
 
 // NewClientContext returns a core.ClientContext.
@@ -39,8 +40,9 @@ func NewClientContext() *ClientContext {
 // Init this ClientContext.
 func (c *ClientContext) Init(client *Client, returnType ...reflect.Type) {
 	c.client = client
-	if urls := client.URLs; len(urls) > 0 {
-		c.URL = urls[0]
+	c.urls = client.getURLs()
+	if len(c.urls) > 0 {
+		c.URL = c.urls[0]
 	}
 	if c.ReturnType == nil {
 		c.ReturnType = returnType
@@ -53,6 +55,14 @@ func (c *ClientContext) Init(client *Client, returnType ...reflect.Type) {
 	}
 }
 
+// URLs returns the client URLs at the time the invocation started.
+func (c *ClientContext) URLs() []*url.URL {
+	if c.urls == nil && c.client != nil {
+		return c.client.getURLs()
+	}
+	return c.urls
+}
+
 // Client returns the Client reference.
 func (c *ClientContext) Client() *Client {
 	return c.client
@@ -66,6 +76,7 @@ func (c *ClientContext) Clone() Context {
 		c.ReturnType,
 		c.Timeout,
 		c.client,
+		c.urls,
 	}
 }
 
@@ -6,14 +6,18 @@
 |                                                          |
 | rpc/core/codec_option.go                                 |
 |                                                          |
-| LastModified: Feb 18, 2024                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
 
 package core
 
-import "github.com/hprose/hprose-golang/v3/io"
+import (
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/io"
+)
 
 // CodecOption for clientCodec & serviceCodec.
 type CodecOption func(interface{})
@@ -39,6 +43,113 @@ func WithSimple(simple bool) CodecOption {
 	}
 }
 
+// WithSimpleFallback returns a simpleFallback Option for clientCodec & serviceCodec,
+// the request or response is encoded with references again if it has circular
+// references in simple mode.
+func WithSimpleFallback(fallback bool) CodecOption {
+	return func(c interface{}) {
+		switch c := c.(type) {
+		case *serviceCodec:
+			c.SimpleFallback = fallback
+		case *clientCodec:
+			c.SimpleFallback = fallback
+		}
+	}
+}
+
+// WithCanonical returns a canonical Option for clientCodec & serviceCodec,
+// the request or response is encoded in canonical mode, so the same values
+// are always encoded to the same bytes.
+func WithCanonical(canonical bool) CodecOption {
+	return func(c interface{}) {
+		switch c := c.(type) {
+		case *serviceCodec:
+			c.Canonical = canonical
+		case *clientCodec:
+			c.Canonical = canonical
+		}
+	}
+}
+
+// WithTypedUnion returns a typedUnion Option for clientCodec & serviceCodec,
+// the values of the types registered by io.RegisterUnion are encoded with
+// their type names.
+func WithTypedUnion(typed bool) CodecOption {
+	return func(c interface{}) {
+		switch c := c.(type) {
+		case *serviceCodec:
+			c.TypedUnion = typed
+		case *clientCodec:
+			c.TypedUnion = typed
+		}
+	}
+}
+
+// WithTimeZoneMode returns a timeZoneMode Option for clientCodec & serviceCodec.
+func WithTimeZoneMode(mode io.TimeZoneMode) CodecOption {
+	return func(c interface{}) {
+		switch c := c.(type) {
+		case *serviceCodec:
+			c.TimeZoneMode = mode
+		case *clientCodec:
+			c.TimeZoneMode = mode
+		}
+	}
+}
+
+// WithLocation returns a location Option for clientCodec & serviceCodec,
+// the time without time zone is decoded in loc.
+func WithLocation(loc *time.Location) CodecOption {
+	return func(c interface{}) {
+		switch c := c.(type) {
+		case *serviceCodec:
+			c.Location = loc
+		case *clientCodec:
+			c.Location = loc
+		}
+	}
+}
+
+// WithInterner returns an interner Option for clientCodec & serviceCodec,
+// the decoded strings are interned by interner, which is shared by all the
+// decoders of the codec, so it must be safe for concurrent use.
+func WithInterner(interner io.StringInterner) CodecOption {
+	return func(c interface{}) {
+		switch c := c.(type) {
+		case *serviceCodec:
+			c.Interner = interner
+		case *clientCodec:
+			c.Interner = interner
+		}
+	}
+}
+
+// WithArena returns an arena Option for clientCodec & serviceCodec,
+// the decoded strings and bytes are allocated from shared blocks.
+func WithArena(arena bool) CodecOption {
+	return func(c interface{}) {
+		switch c := c.(type) {
+		case *serviceCodec:
+			c.Arena = arena
+		case *clientCodec:
+			c.Arena = arena
+		}
+	}
+}
+
+// WithCipher returns a cipher Option for clientCodec & serviceCodec,
+// the struct fields with the encrypt option are encrypted by cipher.
+func WithCipher(cipher io.Cipher) CodecOption {
+	return func(c interface{}) {
+		switch c := c.(type) {
+		case *serviceCodec:
+			c.Cipher = cipher
+		case *clientCodec:
+			c.Cipher = cipher
+		}
+	}
+}
+
 // WithLongType returns a longType Option for clientCodec & serviceCodec.
 func WithLongType(longType io.LongType) CodecOption {
 	return func(c interface{}) {
@@ -98,3 +209,15 @@ func WithListType(listType io.ListType) CodecOption {
 		}
 	}
 }
+
+// WithLimits returns a limits Option for clientCodec & serviceCodec.
+func WithLimits(limits io.Limits) CodecOption {
+	return func(c interface{}) {
+		switch c := c.(type) {
+		case *serviceCodec:
+			c.Limits = limits
+		case *clientCodec:
+			c.Limits = limits
+		}
+	}
+}
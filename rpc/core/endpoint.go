@@ -0,0 +1,56 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/core/endpoint.go                                     |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package core
+
+import (
+	"context"
+	"net/url"
+)
+
+// Endpoint is an address of the service with its weight and metadata.
+type Endpoint struct {
+	URL      *url.URL
+	Weight   int
+	Metadata map[string]string
+}
+
+// EndpointsListener is notified when the endpoints of the client are changed.
+//
+// The plugins used by the client which implement EndpointsListener, such as
+// the weighted load balancers, receive every endpoint set passed to
+// Client.SetEndpoints.
+type EndpointsListener interface {
+	SetEndpoints(endpoints []Endpoint)
+}
+
+// Discovery watches the endpoints of the service.
+//
+// Watch calls update with the current endpoints, and then calls it again each
+// time the endpoints are changed, until ctx is done or an unrecoverable error
+// occurs.
+type Discovery interface {
+	Watch(ctx context.Context, update func(endpoints []Endpoint)) error
+}
+
+// MakeEndpoints returns the endpoints of the uris with weight 1. The invalid
+// uris are ignored.
+func MakeEndpoints(uri ...string) []Endpoint {
+	endpoints := make([]Endpoint, 0, len(uri))
+	for _, u := range uri {
+		if url, err := url.Parse(u); err == nil {
+			endpoints = append(endpoints, Endpoint{URL: url, Weight: 1})
+		}
+	}
+	return endpoints
+}
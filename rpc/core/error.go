@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/core/error.go                                        |
 |                                                          |
-| LastModified: May 5, 2021                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -59,6 +59,44 @@ func (e timeoutError) Temporary() bool {
 // ErrTimeout represents a error.
 var ErrTimeout = timeoutError{}
 
+type rateLimitedError struct{}
+
+func (e rateLimitedError) Error() string {
+	return "rate limited"
+}
+
+func (e rateLimitedError) Temporary() bool {
+	return true
+}
+
+// ErrRateLimited represents a error.
+var ErrRateLimited = rateLimitedError{}
+
+// IsRateLimitedError returns true if err is ErrRateLimited.
+func IsRateLimitedError(err error) bool {
+	_, ok := err.(rateLimitedError)
+	return ok
+}
+
+type overloadedError struct{}
+
+func (e overloadedError) Error() string {
+	return "overloaded"
+}
+
+func (e overloadedError) Temporary() bool {
+	return true
+}
+
+// ErrOverloaded represents a error.
+var ErrOverloaded = overloadedError{}
+
+// IsOverloadedError returns true if err is ErrOverloaded.
+func IsOverloadedError(err error) bool {
+	_, ok := err.(overloadedError)
+	return ok
+}
+
 // ErrRequestEntityTooLarge represents a error.
 var ErrRequestEntityTooLarge = errors.New("hprose/rpc/core: request entity too large")
 
@@ -68,6 +106,9 @@ const RequestEntityTooLarge = "Request entity too large"
 // ErrClosed represents a error.
 var ErrClosed = errors.New("hprose/rpc/core: connection closed")
 
+// ErrNoAvailableURL is returned by the load balancers when the client has no URL.
+var ErrNoAvailableURL = errors.New("hprose/rpc/core: no available url")
+
 // InvalidRequestError represents a error.
 type InvalidRequestError struct {
 	Request []byte
@@ -0,0 +1,50 @@
+//go:build go1.21
+// +build go1.21
+
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/core/generic.go                                      |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package core
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/hprose/hprose-golang/v3/io"
+)
+
+// InvokeAs invokes the remote method and returns the result as type T.
+// It sets ReturnType of the ClientContext bound to ctx during the call, so
+// the result is decoded to T directly. It returns io.CastError if the result
+// is not a T, for example, when it is replaced by a plugin.
+func InvokeAs[T any](ctx context.Context, client *Client, name string, args ...interface{}) (result T, err error) {
+	clientContext := GetClientContext(ctx)
+	if clientContext == nil {
+		clientContext = NewClientContext()
+		ctx = WithContext(ctx, clientContext)
+	}
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	returnType := clientContext.ReturnType
+	clientContext.ReturnType = []reflect.Type{t}
+	defer func() {
+		clientContext.ReturnType = returnType
+	}()
+	results, err := client.InvokeContext(ctx, name, args)
+	if err == nil && len(results) > 0 && results[0] != nil {
+		var ok bool
+		if result, ok = results[0].(T); !ok {
+			err = io.CastError{Source: reflect.TypeOf(results[0]), Destination: t}
+		}
+	}
+	return
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/core/service_codec.go                                |
 |                                                          |
-| LastModified: Feb 18, 2024                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -16,6 +16,7 @@ package core
 import (
 	"errors"
 	"reflect"
+	"time"
 
 	"github.com/hprose/hprose-golang/v3/io"
 )
@@ -27,20 +28,40 @@ type ServiceCodec interface {
 }
 
 type serviceCodec struct {
-	Debug  bool
-	Simple bool
+	Debug          bool
+	Simple         bool
+	SimpleFallback bool
+	Canonical      bool
+	TypedUnion     bool
+	Location       *time.Location
+	Interner       io.StringInterner
+	Arena          bool
+	Cipher         io.Cipher
+	io.TimeZoneMode
 	io.LongType
 	io.RealType
 	io.MapType
 	io.StructType
 	io.ListType
+	io.Limits
 }
 
 // Encode response.
 func (c serviceCodec) Encode(result interface{}, context *ServiceContext) ([]byte, error) {
-	encoder := io.GetEncoder().Simple(c.Simple)
+	response, err := c.encode(result, context, c.Simple)
+	if c.Simple && c.SimpleFallback && io.IsCircularReference(err) {
+		context.ResponseHeaders().Del("simple")
+		return c.encode(result, context, false)
+	}
+	return response, err
+}
+
+func (c serviceCodec) encode(result interface{}, context *ServiceContext, simple bool) ([]byte, error) {
+	encoder := io.GetEncoder().Simple(simple).Canonical(c.Canonical).TypedUnion(c.TypedUnion)
+	encoder.TimeZoneMode = c.TimeZoneMode
+	encoder.Cipher = c.Cipher
 	defer io.FreeEncoder(encoder)
-	if c.Simple {
+	if simple {
 		context.ResponseHeaders().Set("simple", true)
 	}
 	if context.HasResponseHeaders() {
@@ -79,6 +100,11 @@ func (c serviceCodec) Decode(request []byte, context *ServiceContext) (name stri
 	decoder.MapType = c.MapType
 	decoder.StructType = c.StructType
 	decoder.ListType = c.ListType
+	decoder.Limits = c.Limits
+	decoder.Location = c.Location
+	decoder.Interner = c.Interner
+	decoder.Arena = c.Arena
+	decoder.Cipher = c.Cipher
 	tag := decoder.NextByte()
 	if tag == io.TagHeader {
 		var h map[string]interface{}
@@ -122,7 +148,7 @@ func (c serviceCodec) decodeArguments(method Method, decoder *io.Decoder) (args
 		decoder.Decode(&args, tag)
 		return args, decoder.Error
 	}
-	count := decoder.ReadInt()
+	count := decoder.ReadCount()
 	parameters := method.Parameters()
 	paramTypes := make([]reflect.Type, count)
 	if method.Func().Type().IsVariadic() {
@@ -0,0 +1,79 @@
+//go:build go1.21
+// +build go1.21
+
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/mock/generic_test.go                                 |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package mock_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hprose/hprose-golang/v3/io"
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+	. "github.com/hprose/hprose-golang/v3/rpc/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvokeAs(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(a, b int) int {
+		return a + b
+	}, "sum")
+	service.AddFunction(func(name string) []string {
+		return []string{"hello", name}
+	}, "hello")
+	server := Server{Address: "testInvokeAs"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	defer server.Close()
+	client := core.NewClient("mock://testInvokeAs")
+	sum, err := core.InvokeAs[int64](context.Background(), client, "sum", 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), sum)
+	hello, err := core.InvokeAs[[]string](context.Background(), client, "hello", "world")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hello", "world"}, hello)
+	_, err = core.InvokeAs[int](context.Background(), client, "missing")
+	assert.Error(t, err)
+
+	clientContext := core.NewClientContext()
+	clientContext.ReturnType = []reflect.Type{reflect.TypeOf("")}
+	ctx := core.WithContext(context.Background(), clientContext)
+	sum, err = core.InvokeAs[int64](ctx, client, "sum", 3, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), sum)
+	assert.Equal(t, []reflect.Type{reflect.TypeOf("")}, clientContext.ReturnType)
+}
+
+func TestInvokeAsCastError(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(a, b int) int {
+		return a + b
+	}, "sum")
+	server := Server{Address: "testInvokeAsCastError"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	defer server.Close()
+	client := core.NewClient("mock://testInvokeAsCastError")
+	client.Use(func(ctx context.Context, name string, args []interface{}, next core.NextInvokeHandler) (result []interface{}, err error) {
+		if result, err = next(ctx, name, args); err == nil {
+			result = []interface{}{"wrong"}
+		}
+		return
+	})
+	_, err = core.InvokeAs[int64](context.Background(), client, "sum", 1, 2)
+	assert.Equal(t, io.CastError{Source: reflect.TypeOf(""), Destination: reflect.TypeOf(int64(0))}, err)
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/mock/mock_test.go                                    |
 |                                                          |
-| LastModified: Feb 18, 2024                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -16,8 +16,14 @@ package mock_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sync"
 	"sync/atomic"
@@ -29,11 +35,13 @@ import (
 	. "github.com/hprose/hprose-golang/v3/rpc/mock"
 	"github.com/hprose/hprose-golang/v3/rpc/plugins/circuitbreaker"
 	"github.com/hprose/hprose-golang/v3/rpc/plugins/cluster"
+	"github.com/hprose/hprose-golang/v3/rpc/plugins/discovery"
 	"github.com/hprose/hprose-golang/v3/rpc/plugins/forward"
 	"github.com/hprose/hprose-golang/v3/rpc/plugins/limiter"
 	"github.com/hprose/hprose-golang/v3/rpc/plugins/loadbalance"
 	"github.com/hprose/hprose-golang/v3/rpc/plugins/log"
 	"github.com/hprose/hprose-golang/v3/rpc/plugins/oneway"
+	"github.com/hprose/hprose-golang/v3/rpc/plugins/outlier"
 	"github.com/hprose/hprose-golang/v3/rpc/plugins/timeout"
 	"github.com/stretchr/testify/assert"
 )
@@ -66,6 +74,68 @@ func TestHelloWorld(t *testing.T) {
 	server.Close()
 }
 
+func TestPrettyLog(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	server := Server{Address: "testPrettyLog"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	var lines []string
+	plugin := log.New(func(v ...interface{}) {
+		lines = append(lines, fmt.Sprint(v...))
+	})
+	plugin.Pretty = true
+	client := core.NewClient("mock://testPrettyLog")
+	client.Use(plugin.IOHandler)
+	result, err := client.Invoke("hello", []interface{}{"world"})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", result[0])
+	assert.Equal(t, []string{
+		"request:\ncall\n  string \"hello\" #0\n  list(1) #0 [\n    string \"world\" #1\n  ]\nend\n",
+		"response:\nresult\n  string \"hello world\" #0\nend\n",
+	}, lines)
+	server.Close()
+}
+
+type logLogin struct {
+	User     string `hprose:"user"`
+	Password string `hprose:"password,redact"`
+}
+
+func TestRedactLog(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(login logLogin) string {
+		return "hello " + login.User
+	}, "login")
+	server := Server{Address: "testRedactLog"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	var lines []string
+	plugin := log.New(func(v ...interface{}) {
+		lines = append(lines, fmt.Sprint(v...))
+	})
+	client := core.NewClient("mock://testRedactLog")
+	client.Use(plugin)
+	result, err := client.Invoke("login", []interface{}{logLogin{"Tom", "secret"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello Tom", result[0])
+	assert.Equal(t, []string{
+		"name:login",
+		`args:[{"User":"Tom","Password":"[REDACTED]"}]`,
+		`request:Cs5"login"a1{c8"logLogin"2{s4"user"s8"password"}o0{s3"Tom"s10"[REDACTED]"}}z`,
+		`response:Rs9"hello Tom"z`,
+		`result:["hello Tom"]`,
+	}, lines)
+	lines = nil
+	plugin.Redact = false
+	_, err = client.Invoke("login", []interface{}{logLogin{"Tom", "secret"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `args:[{"User":"Tom","Password":"secret"}]`, lines[1])
+	server.Close()
+}
+
 func TestClientTimeout(t *testing.T) {
 	service := core.NewService()
 	service.AddFunction(func(d time.Duration) {
@@ -306,6 +376,202 @@ func TestCircuitBreaker2(t *testing.T) {
 	server.Close()
 }
 
+func TestBreaker(t *testing.T) {
+	var servers []Server
+	for _, address := range []string{"testBreaker1", "testBreaker2"} {
+		service := core.NewService()
+		service.AddFunction(func(name string) string {
+			return "hello " + name
+		}, "hello")
+		server := Server{Address: address}
+		err := service.Bind(server)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+	}
+	var lock sync.Mutex
+	var transitions []string
+	breaker := circuitbreaker.NewBreaker(
+		circuitbreaker.WithMinRequests(1),
+		circuitbreaker.WithOpenTimeout(time.Millisecond*20),
+		circuitbreaker.WithStateChange(func(key string, from, to circuitbreaker.State) {
+			lock.Lock()
+			defer lock.Unlock()
+			transitions = append(transitions, key+" "+from.String()+" -> "+to.String())
+		}),
+	)
+	lb := loadbalance.NewRoundRobinLoadBalance()
+	client := core.NewClient("mock://testBreaker1", "mock://testBreaker2")
+	client.Use(loadbalance.SkipUnavailable(lb, breaker.Available), breaker)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	servers[0].Close()
+	_, err := proxy.Hello("world")
+	assert.EqualError(t, err, "server is stoped")
+	assert.Equal(t, circuitbreaker.StateOpen, breaker.State("mock://testBreaker1"))
+	for i := 0; i < 4; i++ {
+		result, err := proxy.Hello("world")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", result)
+	}
+	time.Sleep(time.Millisecond * 25)
+	s := core.NewService()
+	s.AddFunction(func(name string) string {
+		return "hi " + name
+	}, "hello")
+	_ = s.Bind(servers[0])
+	results := map[string]int{}
+	for i := 0; i < 4; i++ {
+		result, err := proxy.Hello("world")
+		assert.NoError(t, err)
+		results[result]++
+	}
+	assert.Equal(t, map[string]int{"hello world": 2, "hi world": 2}, results)
+	assert.Equal(t, circuitbreaker.StateClosed, breaker.State("mock://testBreaker1"))
+	assert.Equal(t, []string{
+		"mock://testBreaker1 closed -> open",
+		"mock://testBreaker1 open -> half-open",
+		"mock://testBreaker1 half-open -> closed",
+	}, transitions)
+	for _, server := range servers {
+		server.Close()
+	}
+}
+
+func TestBreakerByMethod(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	server := Server{Address: "testBreakerByMethod"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	breaker := circuitbreaker.NewBreaker(
+		circuitbreaker.WithKeyFunc(circuitbreaker.KeyByMethod),
+		circuitbreaker.WithMinRequests(2),
+		circuitbreaker.WithSlowCall(time.Nanosecond, 1),
+		circuitbreaker.WithFallback(func(ctx context.Context, name string, args []interface{}) (result []interface{}, err error) {
+			return []interface{}{name + " breaked"}, nil
+		}),
+	)
+	client := core.NewClient("mock://testBreakerByMethod")
+	client.Use(breaker)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	for i := 0; i < 2; i++ {
+		result, err := proxy.Hello("world")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", result)
+	}
+	assert.Equal(t, circuitbreaker.StateOpen, breaker.State("Hello"))
+	result, err := proxy.Hello("world")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello breaked", result)
+	assert.Equal(t, "half-open", circuitbreaker.StateHalfOpen.String())
+	server.Close()
+}
+
+func TestBreakerMethodError(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	service.AddFunction(func() error {
+		return errors.New("boom")
+	}, "fail")
+	server := Server{Address: "testBreakerMethodError"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	breaker := circuitbreaker.NewBreaker(
+		circuitbreaker.WithKeyFunc(circuitbreaker.KeyByMethod),
+		circuitbreaker.WithMinRequests(2),
+	)
+	client := core.NewClient("mock://testBreakerMethodError")
+	client.Use(breaker)
+	var proxy struct {
+		Hello func(name string) (string, error)
+		Fail  func() error
+	}
+	client.UseService(&proxy)
+	for i := 0; i < 2; i++ {
+		assert.EqualError(t, proxy.Fail(), "boom")
+		result, err := proxy.Hello("world")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", result)
+	}
+	assert.Equal(t, circuitbreaker.StateOpen, breaker.State("Fail"))
+	assert.Equal(t, circuitbreaker.StateClosed, breaker.State("Hello"))
+	assert.Equal(t, circuitbreaker.ErrBreaker, proxy.Fail())
+	server.Close()
+}
+
+func TestBreakerWithHedging(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func() error {
+		return errors.New("boom")
+	}, "fail")
+	var servers []Server
+	for _, name := range []string{"A", "B"} {
+		server := Server{Address: "testBreakerWithHedging" + name}
+		err := service.Bind(server)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+	}
+	breaker := circuitbreaker.NewBreaker(
+		circuitbreaker.WithKeyFunc(circuitbreaker.KeyByMethod),
+		circuitbreaker.WithMinRequests(5),
+		circuitbreaker.WithOpenTimeout(time.Millisecond*20),
+		circuitbreaker.WithHalfOpenCalls(1),
+	)
+	client := core.NewClient("mock://testBreakerWithHedgingA", "mock://testBreakerWithHedgingB")
+	client.Use(cluster.NewHedging(cluster.WithIdempotent(true)), breaker)
+	var proxy struct {
+		Fail func() error
+	}
+	client.UseService(&proxy)
+	for i := 0; i < 10; i++ {
+		assert.Error(t, proxy.Fail())
+	}
+	assert.Equal(t, circuitbreaker.StateOpen, breaker.State("Fail"))
+	time.Sleep(time.Millisecond * 30)
+	assert.EqualError(t, proxy.Fail(), "boom")
+	assert.Equal(t, circuitbreaker.StateOpen, breaker.State("Fail"))
+	for _, server := range servers {
+		server.Close()
+	}
+}
+
+func TestBreakerMaxKeys(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	server := Server{Address: "testBreakerMaxKeys"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	breaker := circuitbreaker.NewBreaker(
+		circuitbreaker.WithKeyFunc(circuitbreaker.KeyByURLAndMethod),
+		circuitbreaker.WithMaxKeys(2),
+	)
+	client := core.NewClient("mock://testBreakerMaxKeys")
+	client.Use(breaker)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	for i := 0; i < 5; i++ {
+		client.SetURI(fmt.Sprintf("mock://testBreakerMaxKeys?%d", i))
+		result, err := proxy.Hello("world")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", result)
+		assert.LessOrEqual(t, breaker.Keys(), 2)
+	}
+	server.Close()
+}
+
 func TestClusterFailover1(t *testing.T) {
 	service := core.NewService()
 	service.AddFunction(func(name string) string {
@@ -905,6 +1171,462 @@ func TestRateLimiterInvokeHandler(t *testing.T) {
 	server.Close()
 }
 
+func TestKeyedRateLimiter(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	service.AddFunction(func() string {
+		return "pong"
+	}, "ping")
+	service.Get("ping").Options().Set(limiter.RateLimitOption, 0)
+	krl := limiter.NewKeyedRateLimiter(10,
+		limiter.WithKeyFunc(limiter.KeyByHeader("id")),
+		limiter.WithKeyedMaxPermits(1),
+		limiter.WithKeyedTimeout(time.Millisecond),
+		limiter.WithMaxKeys(2))
+	service.Use(krl.InvokeHandler)
+	server := Server{Address: "testKeyedRateLimiter"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	client := core.NewClient("mock://testKeyedRateLimiter")
+	var proxy struct {
+		Hello func(ctx context.Context, name string) (string, error)
+		Ping  func(ctx context.Context) (string, error)
+	}
+	client.UseService(&proxy)
+	assert.Equal(t, int64(10), krl.PermitsPerSecond())
+	assert.Equal(t, float64(1), krl.MaxPermits())
+	assert.Equal(t, time.Millisecond, krl.Timeout())
+	assert.Equal(t, 2, krl.MaxKeys())
+	withID := func(id string) context.Context {
+		clientContext := core.NewClientContext()
+		clientContext.RequestHeaders().Set("id", id)
+		return core.WithContext(context.Background(), clientContext)
+	}
+	result, err := proxy.Hello(withID("a"), "a")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello a", result)
+	_, err = proxy.Hello(withID("a"), "a")
+	assert.Equal(t, core.ErrRateLimited, err)
+	assert.True(t, core.IsRateLimitedError(err))
+	assert.True(t, core.IsTemporaryError(err))
+	result, err = proxy.Hello(withID("b"), "b")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello b", result)
+	for i := 0; i < 3; i++ {
+		result, err = proxy.Ping(withID("a"))
+		assert.NoError(t, err)
+		assert.Equal(t, "pong", result)
+	}
+	_, _ = proxy.Hello(withID("c"), "c")
+	assert.Equal(t, 2, krl.Keys())
+	server.Close()
+}
+
+func TestKeyedRateLimiterDefault(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	krl := limiter.NewKeyedRateLimiter(10,
+		limiter.WithKeyFunc(limiter.KeyByHeader("id")),
+		limiter.WithKeyedMaxPermits(1),
+		limiter.WithMaxKeys(2))
+	service.Use(krl.InvokeHandler)
+	server := Server{Address: "testKeyedRateLimiterDefault"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	client := core.NewClient("mock://testKeyedRateLimiterDefault")
+	var proxy struct {
+		Hello func(ctx context.Context, name string) (string, error)
+	}
+	client.UseService(&proxy)
+	assert.Equal(t, time.Duration(0), krl.Timeout())
+	withID := func(id string) context.Context {
+		clientContext := core.NewClientContext()
+		clientContext.RequestHeaders().Set("id", id)
+		return core.WithContext(context.Background(), clientContext)
+	}
+	_, err = proxy.Hello(withID("a"), "a")
+	assert.NoError(t, err)
+	_, err = proxy.Hello(withID("a"), "a")
+	assert.Equal(t, core.ErrRateLimited, err)
+	_, err = proxy.Hello(withID("b"), "b")
+	assert.NoError(t, err)
+	// the buckets of a and b are busy, so the rotated keys share a bucket.
+	_, err = proxy.Hello(withID("c"), "c")
+	assert.NoError(t, err)
+	_, err = proxy.Hello(withID("d"), "d")
+	assert.Equal(t, core.ErrRateLimited, err)
+	assert.Equal(t, 2, krl.Keys())
+	time.Sleep(150 * time.Millisecond)
+	_, err = proxy.Hello(withID("d"), "d")
+	assert.NoError(t, err)
+	_, err = proxy.Hello(withID("d"), "d")
+	assert.Equal(t, core.ErrRateLimited, err)
+	assert.Equal(t, 2, krl.Keys())
+	server.Close()
+}
+
+func TestKeyedRateLimiterWait(t *testing.T) {
+	krl := limiter.NewKeyedRateLimiter(10,
+		limiter.WithKeyFunc(limiter.KeyByMethod),
+		limiter.WithKeyedMaxPermits(1),
+		limiter.WithKeyedTimeout(time.Second))
+	ctx := context.Background()
+	assert.NoError(t, krl.Acquire(ctx, "hello"))
+	start := time.Now()
+	assert.NoError(t, krl.Acquire(ctx, "hello"))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, krl.Acquire(ctx, "hello"))
+}
+
+func TestDistributedLimiterAlgorithms(t *testing.T) {
+	ctx := context.Background()
+	rate := limiter.Rate{PermitsPerSecond: 10, Burst: 3}
+	tests := []struct {
+		algorithm limiter.Algorithm
+		first     int
+		next      int
+	}{
+		{limiter.TokenBucket, 3, 1},
+		{limiter.GCRA, 3, 1},
+		{limiter.SlidingWindowLog, 10, 0},
+	}
+	now := time.Now()
+	for _, test := range tests {
+		store := limiter.NewMemoryStore()
+		count := func(now time.Time) (n int) {
+			for i := 0; i < 20; i++ {
+				ok, err := test.algorithm(ctx, store, "key", now, rate)
+				assert.NoError(t, err)
+				if ok {
+					n++
+				}
+			}
+			return
+		}
+		assert.Equal(t, test.first, count(now))
+		assert.Equal(t, test.next, count(now.Add(time.Millisecond*150)))
+		assert.Equal(t, 1, store.Len())
+	}
+	store := limiter.NewMemoryStore()
+	n := 0
+	for i := 0; i < 20; i++ {
+		ok, err := limiter.SlidingWindowCounter(ctx, store, "key", now, rate)
+		assert.NoError(t, err)
+		if ok {
+			n++
+		}
+	}
+	assert.Equal(t, 10, n)
+}
+
+func TestDistributedLimiter(t *testing.T) {
+	storeService := core.NewService()
+	limiter.AddStore(storeService, limiter.NewMemoryStore())
+	storeServer := Server{Address: "testDistributedLimiterStore"}
+	err := storeService.Bind(storeServer)
+	assert.NoError(t, err)
+	store := limiter.NewServiceStore(core.NewClient("mock://testDistributedLimiterStore"))
+
+	var servers []Server
+	for _, address := range []string{"testDistributedLimiter1", "testDistributedLimiter2"} {
+		service := core.NewService()
+		service.AddFunction(func(name string) string {
+			return "hello " + name
+		}, "hello")
+		dl := limiter.NewDistributedLimiter(store, 1,
+			limiter.WithAlgorithm(limiter.GCRA),
+			limiter.WithBurst(1),
+			limiter.WithDistributedKeyFunc(limiter.KeyByMethod),
+			limiter.WithKeyPrefix("test:"))
+		assert.Equal(t, int64(1), dl.PermitsPerSecond())
+		assert.Equal(t, int64(1), dl.Burst())
+		service.Use(dl.InvokeHandler)
+		server := Server{Address: address}
+		err := service.Bind(server)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+	}
+	client := core.NewClient("mock://testDistributedLimiter1", "mock://testDistributedLimiter2")
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	result, err := proxy.Hello("world")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", result)
+	client.SetURI("mock://testDistributedLimiter2")
+	_, err = proxy.Hello("world")
+	assert.Equal(t, core.ErrRateLimited, err)
+	for _, server := range servers {
+		server.Close()
+	}
+	storeServer.Close()
+}
+
+func TestDistributedLimiterInvalidRate(t *testing.T) {
+	store := limiter.NewMemoryStore()
+	assert.PanicsWithValue(t, "limiter: permitsPerSecond must be great than 0", func() {
+		limiter.NewDistributedLimiter(store, 0)
+	})
+	assert.PanicsWithValue(t, "limiter: permitsPerSecond must be great than 0", func() {
+		limiter.NewDistributedLimiter(store, -1)
+	})
+	assert.PanicsWithValue(t, "limiter: burst must be great than 0", func() {
+		limiter.NewDistributedLimiter(store, 10, limiter.WithBurst(0))
+	})
+}
+
+func TestLimitAlgorithms(t *testing.T) {
+	aimd := &limiter.AIMD{Timeout: time.Second}
+	assert.Equal(t, float64(11), aimd.Update(10, limiter.Sample{RTT: time.Millisecond, InFlight: 5}))
+	assert.Equal(t, float64(10), aimd.Update(10, limiter.Sample{RTT: time.Millisecond, InFlight: 1}))
+	assert.Equal(t, float64(9), aimd.Update(10, limiter.Sample{RTT: time.Millisecond, InFlight: 5, Dropped: true}))
+	assert.Equal(t, float64(9), aimd.Update(10, limiter.Sample{RTT: time.Second * 2, InFlight: 5}))
+
+	vegas := &limiter.Vegas{}
+	assert.Equal(t, float64(11), vegas.Update(10, limiter.Sample{RTT: time.Millisecond, InFlight: 10}))
+	assert.Equal(t, float64(9), vegas.Update(10, limiter.Sample{RTT: time.Millisecond * 10, InFlight: 10}))
+	assert.Equal(t, float64(9), vegas.Update(10, limiter.Sample{RTT: time.Millisecond, InFlight: 10, Dropped: true}))
+
+	gradient := &limiter.Gradient{}
+	limit := float64(100)
+	for i := 0; i < 10; i++ {
+		limit = gradient.Update(limit, limiter.Sample{RTT: time.Millisecond, InFlight: 100})
+	}
+	assert.True(t, limit > 100)
+	for i := 0; i < 10; i++ {
+		limit = gradient.Update(limit, limiter.Sample{RTT: time.Millisecond * 10, InFlight: 100})
+	}
+	assert.True(t, limit < 100)
+	assert.Equal(t, float64(90), gradient.Update(100, limiter.Sample{Dropped: true}))
+}
+
+func TestAdaptiveLimiter(t *testing.T) {
+	service := core.NewService()
+	started := make(chan struct{})
+	done := make(chan struct{})
+	service.AddFunction(func(name string) string {
+		if name == "wait" {
+			started <- struct{}{}
+			<-done
+		}
+		return "hello " + name
+	}, "hello")
+	al := limiter.NewAdaptiveLimiter(limiter.WithInitialLimit(1), limiter.WithMaxLimit(2))
+	service.Use(al.IOHandler)
+	server := Server{Address: "testAdaptiveLimiter"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	client := core.NewClient("mock://testAdaptiveLimiter")
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	assert.Equal(t, 1, al.Limit())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		result, err := proxy.Hello("wait")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello wait", result)
+	}()
+	<-started
+	assert.Equal(t, 1, al.InFlight())
+	_, err = proxy.Hello("world")
+	assert.Equal(t, core.ErrOverloaded, err)
+	assert.True(t, core.IsOverloadedError(err))
+	close(done)
+	wg.Wait()
+	assert.Equal(t, 0, al.InFlight())
+	assert.Equal(t, 2, al.Limit())
+	server.Close()
+}
+
+func TestAdaptiveLimiterMethodError(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) (string, error) {
+		if name == "" {
+			return "", errors.New("empty name")
+		}
+		return "hello " + name, nil
+	}, "hello")
+	algorithm := &limiter.AIMD{BackoffRatio: 0.5}
+	serviceLimiter := limiter.NewAdaptiveLimiter(limiter.WithLimitAlgorithm(algorithm), limiter.WithInitialLimit(10))
+	service.Use(serviceLimiter)
+	server := Server{Address: "testAdaptiveLimiterMethodError"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	client := core.NewClient("mock://testAdaptiveLimiterMethodError")
+	clientLimiter := limiter.NewAdaptiveLimiter(limiter.WithLimitAlgorithm(algorithm), limiter.WithInitialLimit(10))
+	client.Use(clientLimiter)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	result, err := proxy.Hello("world")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", result)
+	assert.Equal(t, 10, serviceLimiter.Limit())
+	assert.Equal(t, 10, clientLimiter.Limit())
+	_, err = proxy.Hello("")
+	assert.EqualError(t, err, "empty name")
+	assert.Equal(t, 5, serviceLimiter.Limit())
+	assert.Equal(t, 5, clientLimiter.Limit())
+	assert.Equal(t, 0, serviceLimiter.InFlight())
+	assert.Equal(t, 0, clientLimiter.InFlight())
+	server.Close()
+}
+
+func TestAdaptiveLimiterWithCluster(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	var servers []Server
+	for _, name := range []string{"A", "B"} {
+		server := Server{Address: "testAdaptiveLimiterWithCluster" + name}
+		err := service.Bind(server)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+	}
+	for _, plugin := range []core.PluginHandler{cluster.NewHedging(cluster.WithIdempotent(true)), cluster.Forking} {
+		clientLimiter := limiter.NewAdaptiveLimiter(limiter.WithInitialLimit(5), limiter.WithMaxLimit(5))
+		client := core.NewClient("mock://testAdaptiveLimiterWithClusterA", "mock://testAdaptiveLimiterWithClusterB")
+		client.Use(plugin, clientLimiter)
+		var proxy struct {
+			Hello func(name string) (string, error)
+		}
+		client.UseService(&proxy)
+		for i := 0; i < 20; i++ {
+			result, err := proxy.Hello("world")
+			assert.NoError(t, err)
+			assert.Equal(t, "hello world", result)
+		}
+		assert.Eventually(t, func() bool {
+			return clientLimiter.InFlight() == 0
+		}, time.Second, time.Millisecond)
+	}
+	for _, server := range servers {
+		server.Close()
+	}
+}
+
+func TestPriorityLimiterAcquire(t *testing.T) {
+	ctx := context.Background()
+	pl := limiter.NewPriorityLimiter(1, 1, limiter.WithQueueTimeout(time.Millisecond*50))
+	assert.Equal(t, 1, pl.MaxConcurrentRequests())
+	assert.Equal(t, 1, pl.MaxQueueLength())
+	assert.Equal(t, time.Millisecond*50, pl.Timeout())
+	assert.NoError(t, pl.Acquire(ctx, 0))
+	low := make(chan error)
+	go func() {
+		low <- pl.Acquire(ctx, 0)
+	}()
+	for pl.QueueLength() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	high := make(chan error)
+	go func() {
+		high <- pl.Acquire(ctx, 10)
+	}()
+	assert.Equal(t, core.ErrOverloaded, <-low)
+	assert.Equal(t, map[int]int{10: 1}, pl.QueueLengths())
+	assert.Equal(t, core.ErrOverloaded, pl.Acquire(ctx, 5))
+	assert.Equal(t, uint64(2), pl.Rejected())
+	pl.Release()
+	assert.NoError(t, <-high)
+	assert.Equal(t, 1, pl.InFlight())
+	assert.Equal(t, 0, pl.QueueLength())
+	assert.Equal(t, core.ErrTimeout, pl.Acquire(ctx, 0))
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Equal(t, context.Canceled, pl.Acquire(cancelCtx, 0))
+	deadlineCtx, cancel := context.WithTimeout(ctx, time.Millisecond*10)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, pl.Acquire(deadlineCtx, 0))
+	assert.Equal(t, 0, pl.QueueLength())
+	pl.Release()
+	assert.Equal(t, 0, pl.InFlight())
+}
+
+func TestPriorityFunc(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func() string {
+		return "ok"
+	}, "health")
+	service.Get("health").Options().Set(limiter.PriorityOption, 100)
+	serviceContext := core.NewServiceContext(service)
+	serviceContext.Method = service.Get("health")
+	ctx := core.WithContext(context.Background(), serviceContext)
+	assert.Equal(t, 100, limiter.DefaultPriority(ctx, "health"))
+	assert.Equal(t, 100, limiter.HeaderPriority(ctx, "health"))
+	serviceContext.RequestHeaders().Set(limiter.PriorityOption, 1000)
+	assert.Equal(t, 100, limiter.DefaultPriority(ctx, "health"))
+	assert.Equal(t, 100, limiter.HeaderPriority(ctx, "health"))
+	serviceContext.RequestHeaders().Set(limiter.PriorityOption, 1)
+	assert.Equal(t, 100, limiter.DefaultPriority(ctx, "health"))
+	assert.Equal(t, 1, limiter.HeaderPriority(ctx, "health"))
+}
+
+func TestPriorityLimiter(t *testing.T) {
+	service := core.NewService()
+	started := make(chan struct{})
+	done := make(chan struct{})
+	service.AddFunction(func() string {
+		started <- struct{}{}
+		<-done
+		return "done"
+	}, "wait")
+	service.AddFunction(func() string {
+		return "ok"
+	}, "health")
+	service.Get("health").Options().Set(limiter.PriorityOption, 100)
+	pl := limiter.NewPriorityLimiter(1, 1, limiter.WithPriorityFunc(limiter.HeaderPriority))
+	service.Use(pl.InvokeHandler)
+	server := Server{Address: "testPriorityLimiter"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	client := core.NewClient("mock://testPriorityLimiter")
+	var proxy struct {
+		Wait   func() (string, error)
+		Health func() (string, error)
+		Hello  func(ctx context.Context) (string, error) `name:"health"`
+	}
+	client.UseService(&proxy)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		result, err := proxy.Wait()
+		assert.NoError(t, err)
+		assert.Equal(t, "done", result)
+	}()
+	<-started
+	go func() {
+		defer wg.Done()
+		result, err := proxy.Health()
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", result)
+	}()
+	for pl.QueueLength() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clientContext := core.NewClientContext()
+	clientContext.RequestHeaders().Set(limiter.PriorityOption, 1)
+	_, err = proxy.Hello(core.WithContext(context.Background(), clientContext))
+	assert.Equal(t, core.ErrOverloaded, err)
+	close(done)
+	wg.Wait()
+	server.Close()
+}
+
 func TestRandomLoadBalance(t *testing.T) {
 	service := core.NewService()
 	service.AddFunction(func(name string) string {
@@ -1265,6 +1987,804 @@ func TestWeightedLeastActiveLoadBalance(t *testing.T) {
 	server4.Close()
 }
 
+func TestConsistentHashLoadBalance(t *testing.T) {
+	var servers []Server
+	var uris []string
+	for i := 1; i <= 3; i++ {
+		address := fmt.Sprintf("testConsistentHashLoadBalance%d", i)
+		service := core.NewService()
+		service.AddFunction(func(key string) string {
+			return address
+		}, "hello")
+		server := Server{Address: address}
+		err := service.Bind(server)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+		uris = append(uris, "mock://"+address)
+	}
+	client := core.NewClient(uris...)
+	lb := loadbalance.NewConsistentHashLoadBalance(loadbalance.HashKeyByArg(0))
+	assert.Equal(t, 160, lb.VirtualNodes())
+	assert.Equal(t, float64(0), lb.LoadFactor())
+	client.Use(lb)
+	var proxy struct {
+		Hello func(key string) (string, error)
+	}
+	client.UseService(&proxy)
+	routes := map[string]string{}
+	counts := map[string]int{}
+	for i := 0; i < 60; i++ {
+		key := fmt.Sprintf("key%d", i)
+		result, err := proxy.Hello(key)
+		assert.NoError(t, err)
+		routes[key] = result
+		counts[result]++
+		result, err = proxy.Hello(key)
+		assert.NoError(t, err)
+		assert.Equal(t, routes[key], result)
+	}
+	assert.Len(t, counts, 3)
+	client.SetURI(uris[:2]...)
+	for key, route := range routes {
+		result, err := proxy.Hello(key)
+		assert.NoError(t, err)
+		if route != "testConsistentHashLoadBalance3" {
+			assert.Equal(t, route, result)
+		}
+	}
+	for _, server := range servers {
+		server.Close()
+	}
+}
+
+func TestConsistentHashLoadBalanceBoundedLoads(t *testing.T) {
+	var servers []Server
+	var uris []string
+	started := make(chan struct{})
+	done := make(chan struct{})
+	for i := 1; i <= 2; i++ {
+		address := fmt.Sprintf("testConsistentHashLoadBalanceBoundedLoads%d", i)
+		service := core.NewService()
+		service.AddFunction(func(wait bool) string {
+			if wait {
+				started <- struct{}{}
+				<-done
+			}
+			return address
+		}, "hello")
+		server := Server{Address: address}
+		err := service.Bind(server)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+		uris = append(uris, "mock://"+address)
+	}
+	client := core.NewClient(uris...)
+	lb := loadbalance.NewConsistentHashLoadBalance(loadbalance.HashKeyByHeader("shard"), loadbalance.WithLoadFactor(1))
+	client.Use(lb)
+	var proxy struct {
+		Hello func(ctx context.Context, wait bool) (string, error)
+	}
+	client.UseService(&proxy)
+	withShard := func() context.Context {
+		clientContext := core.NewClientContext()
+		clientContext.RequestHeaders().Set("shard", "user:1")
+		return core.WithContext(context.Background(), clientContext)
+	}
+	home, err := proxy.Hello(withShard(), false)
+	assert.NoError(t, err)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		result, err := proxy.Hello(withShard(), true)
+		assert.NoError(t, err)
+		assert.Equal(t, home, result)
+	}()
+	<-started
+	result, err := proxy.Hello(withShard(), false)
+	assert.NoError(t, err)
+	assert.NotEqual(t, home, result)
+	close(done)
+	wg.Wait()
+	for _, server := range servers {
+		server.Close()
+	}
+}
+
+func TestConsistentHashLoadBalanceNoURL(t *testing.T) {
+	client := core.NewClient()
+	client.Use(loadbalance.NewConsistentHashLoadBalance(loadbalance.HashKeyByArg(0)))
+	var proxy struct {
+		Hello func(key string) (string, error)
+	}
+	client.UseService(&proxy)
+	_, err := proxy.Hello("a")
+	assert.Equal(t, core.ErrNoAvailableURL, err)
+}
+
+func TestPeakEWMALoadBalance(t *testing.T) {
+	var servers []Server
+	for _, address := range []string{"testPeakEWMALoadBalance1", "testPeakEWMALoadBalance2"} {
+		service := core.NewService()
+		service.AddFunction(func(name string) string {
+			return "hello " + name
+		}, "hello")
+		server := Server{Address: address}
+		err := service.Bind(server)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+	}
+	servers[1].Close()
+	client := core.NewClient("mock://testPeakEWMALoadBalance1", "mock://testPeakEWMALoadBalance2")
+	lb := loadbalance.NewPeakEWMALoadBalance(loadbalance.WithPenalty(time.Second), loadbalance.WithDecayTime(time.Minute))
+	assert.Equal(t, time.Second, lb.Penalty())
+	assert.Equal(t, time.Minute, lb.DecayTime())
+	client.Use(lb.Handler)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	failures := 0
+	for i := 0; i < 10; i++ {
+		if _, err := proxy.Hello("world"); err != nil {
+			failures++
+		}
+	}
+	assert.True(t, failures <= 1)
+	scores := lb.Scores()
+	if failures == 1 {
+		assert.True(t, scores["mock://testPeakEWMALoadBalance2"] > scores["mock://testPeakEWMALoadBalance1"])
+		assert.True(t, scores["mock://testPeakEWMALoadBalance2"] > float64(time.Millisecond*900))
+	}
+	servers[0].Close()
+}
+
+func TestPeakEWMALoadBalancePending(t *testing.T) {
+	var servers []Server
+	release := make(chan struct{})
+	var calls sync.Map
+	for _, address := range []string{"testPeakEWMALoadBalancePending1", "testPeakEWMALoadBalancePending2"} {
+		address := address
+		service := core.NewService()
+		service.AddFunction(func() {
+			calls.Store(address, true)
+			<-release
+		}, "wait")
+		server := Server{Address: address}
+		err := service.Bind(server)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+	}
+	client := core.NewClient("mock://testPeakEWMALoadBalancePending1", "mock://testPeakEWMALoadBalancePending2")
+	lb := loadbalance.NewPeakEWMALoadBalance()
+	client.Use(lb.Handler)
+	var proxy struct {
+		Wait func() error
+	}
+	client.UseService(&proxy)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, proxy.Wait())
+		}()
+		assert.Eventually(t, func() bool {
+			n := 0
+			calls.Range(func(key, value interface{}) bool {
+				n++
+				return true
+			})
+			return n == i+1
+		}, time.Second, time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	assert.Len(t, lb.Scores(), 2)
+	client.SetURI("mock://testPeakEWMALoadBalancePending1")
+	assert.NoError(t, proxy.Wait())
+	assert.Len(t, lb.Scores(), 1)
+	for _, server := range servers {
+		server.Close()
+	}
+}
+
+func TestPeakEWMALoadBalanceNoURL(t *testing.T) {
+	client := core.NewClient()
+	client.Use(loadbalance.NewPeakEWMALoadBalance().Handler)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	_, err := proxy.Hello("world")
+	assert.Equal(t, core.ErrNoAvailableURL, err)
+}
+
+func TestClientSetEndpoints(t *testing.T) {
+	var servers []Server
+	for _, name := range []string{"A", "B", "C"} {
+		name := name
+		service := core.NewService()
+		service.AddFunction(func() string {
+			return name
+		}, "name")
+		server := Server{Address: "testClientSetEndpoints" + name}
+		err := service.Bind(server)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+	}
+	client := core.NewClient("mock://testClientSetEndpointsA")
+	lb := loadbalance.NewWeightedRoundRobinLoadBalance(map[string]int{
+		"mock://testClientSetEndpointsA": 1,
+	})
+	client.Use(lb)
+	var proxy struct {
+		Name func() (string, error)
+	}
+	client.UseService(&proxy)
+	name, err := proxy.Name()
+	assert.NoError(t, err)
+	assert.Equal(t, "A", name)
+	endpoints := core.MakeEndpoints("mock://testClientSetEndpointsB", "mock://testClientSetEndpointsC")
+	endpoints[0].Weight = 2
+	endpoints[1].Metadata = map[string]string{"zone": "c"}
+	client.SetEndpoints(endpoints)
+	assert.Equal(t, endpoints, client.Endpoints())
+	assert.Equal(t, []*url.URL{endpoints[0].URL, endpoints[1].URL}, client.URLs)
+	assert.Equal(t, []*url.URL{endpoints[0].URL, endpoints[1].URL}, lb.URLs)
+	counts := make(map[string]int)
+	for i := 0; i < 6; i++ {
+		name, err := proxy.Name()
+		assert.NoError(t, err)
+		counts[name]++
+	}
+	assert.Equal(t, map[string]int{"B": 4, "C": 2}, counts)
+	client.Unuse(lb)
+	client.SetURI("mock://testClientSetEndpointsA")
+	assert.Equal(t, []*url.URL{endpoints[0].URL, endpoints[1].URL}, lb.URLs)
+	for _, server := range servers {
+		server.Close()
+	}
+}
+
+type endpointsRecorder struct {
+	client    *core.Client
+	endpoints [][]core.Endpoint
+}
+
+func (r *endpointsRecorder) SetEndpoints(endpoints []core.Endpoint) {
+	r.endpoints = append(r.endpoints, r.client.Endpoints())
+}
+
+func (r *endpointsRecorder) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
+	return next(ctx, request)
+}
+
+func TestClientEndpointsListener(t *testing.T) {
+	client := core.NewClient("mock://testClientEndpointsListenerA")
+	lb := loadbalance.NewWeightedRoundRobinLoadBalance(map[string]int{
+		"mock://testClientEndpointsListenerB": 1,
+	})
+	client.Use(loadbalance.SkipUnavailable(lb, func(ctx context.Context, u *url.URL) bool {
+		return true
+	}))
+	assert.Equal(t, client.URLs, lb.URLs)
+	recorder := &endpointsRecorder{client: client}
+	client.Use(recorder)
+	endpoints := core.MakeEndpoints("mock://testClientEndpointsListenerC")
+	client.SetEndpoints(endpoints)
+	assert.Equal(t, [][]core.Endpoint{
+		core.MakeEndpoints("mock://testClientEndpointsListenerA"),
+		endpoints,
+	}, recorder.endpoints)
+	assert.Equal(t, client.URLs, lb.URLs)
+}
+
+func TestStaticDiscovery(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	server := Server{Address: "testStaticDiscovery"}
+	err := service.Bind(server)
+	assert.NoError(t, err)
+	client := core.NewClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- client.Discover(ctx, discovery.NewStaticDiscovery(core.MakeEndpoints("mock://testStaticDiscovery")...))
+	}()
+	assert.Eventually(t, func() bool {
+		return len(client.Endpoints()) == 1
+	}, time.Second, time.Millisecond)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	result, err := proxy.Hello("world")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello world", result)
+	cancel()
+	assert.NoError(t, <-done)
+	server.Close()
+}
+
+func TestFileDiscovery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "discovery")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "endpoints")
+	d := discovery.NewFileDiscovery(path, discovery.WithFileInterval(time.Millisecond*10))
+	assert.Equal(t, path, d.Path())
+	assert.Equal(t, time.Millisecond*10, d.Interval())
+	assert.Error(t, d.Watch(context.Background(), func(endpoints []core.Endpoint) {}))
+
+	err = ioutil.WriteFile(path, []byte("# endpoints\ntcp://10.0.0.1:8412 10 zone=a\n\ntcp://10.0.0.2:8412\n"), 0644)
+	assert.NoError(t, err)
+	updates := make(chan []core.Endpoint, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- d.Watch(ctx, func(endpoints []core.Endpoint) {
+			updates <- endpoints
+		})
+	}()
+	endpoints := <-updates
+	assert.Len(t, endpoints, 2)
+	assert.Equal(t, "tcp://10.0.0.1:8412", endpoints[0].URL.String())
+	assert.Equal(t, 10, endpoints[0].Weight)
+	assert.Equal(t, map[string]string{"zone": "a"}, endpoints[0].Metadata)
+	assert.Equal(t, "tcp://10.0.0.2:8412", endpoints[1].URL.String())
+	assert.Equal(t, 1, endpoints[1].Weight)
+	assert.Nil(t, endpoints[1].Metadata)
+
+	err = ioutil.WriteFile(path, []byte("tcp://10.0.0.3:8412 zone=c\n"), 0644)
+	assert.NoError(t, err)
+	endpoints = <-updates
+	assert.Len(t, endpoints, 1)
+	assert.Equal(t, "tcp://10.0.0.3:8412", endpoints[0].URL.String())
+	assert.Equal(t, map[string]string{"zone": "c"}, endpoints[0].Metadata)
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Len(t, updates, 0)
+
+	_, err = discovery.ParseEndpoints([]byte("tcp://10.0.0.1:8412 0\n"))
+	assert.EqualError(t, err, `line 1: invalid weight "0"`)
+	_, err = discovery.ParseEndpoints([]byte("tcp://10.0.0.1:8412 1 zone\n"))
+	assert.EqualError(t, err, `line 1: invalid metadata "zone"`)
+}
+
+type fakeResolver struct {
+	sync.Mutex
+	addrs []*net.SRV
+	err   error
+}
+
+func (r *fakeResolver) set(err error, addrs ...*net.SRV) {
+	r.Lock()
+	defer r.Unlock()
+	r.addrs, r.err = addrs, err
+}
+
+func (r *fakeResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	r.Lock()
+	defer r.Unlock()
+	if r.err != nil {
+		return "", nil, r.err
+	}
+	if service != "hprose" || proto != "tcp" || name != "example.com" {
+		return "", nil, errors.New("no such host")
+	}
+	addrs := make([]*net.SRV, len(r.addrs))
+	for i, addr := range r.addrs {
+		srv := *addr
+		addrs[len(addrs)-1-i] = &srv
+	}
+	return "_hprose._tcp.example.com.", addrs, nil
+}
+
+func TestDNSDiscovery(t *testing.T) {
+	resolver := &fakeResolver{}
+	resolver.set(nil,
+		&net.SRV{Target: "a.example.com.", Port: 8412, Priority: 10, Weight: 5},
+		&net.SRV{Target: "b.example.com.", Port: 8412, Priority: 10, Weight: 0},
+		&net.SRV{Target: "c.example.com.", Port: 8413, Priority: 20, Weight: 1},
+	)
+	errs := make(chan error, 10)
+	d := discovery.NewDNSDiscovery("tcp", "hprose", "tcp", "example.com",
+		discovery.WithResolver(resolver),
+		discovery.WithDNSInterval(time.Millisecond*10),
+		discovery.WithDNSErrorHandler(func(err error) {
+			errs <- err
+		}),
+	)
+	assert.Equal(t, time.Millisecond*10, d.Interval())
+	updates := make(chan []core.Endpoint, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- d.Watch(ctx, func(endpoints []core.Endpoint) {
+			updates <- endpoints
+		})
+	}()
+	endpoints := <-updates
+	assert.Equal(t, []core.Endpoint{
+		{URL: &url.URL{Scheme: "tcp", Host: "a.example.com:8412"}, Weight: 5, Metadata: map[string]string{discovery.PriorityMetadata: "10"}},
+		{URL: &url.URL{Scheme: "tcp", Host: "b.example.com:8412"}, Weight: 1, Metadata: map[string]string{discovery.PriorityMetadata: "10"}},
+		{URL: &url.URL{Scheme: "tcp", Host: "c.example.com:8413"}, Weight: 1, Metadata: map[string]string{discovery.PriorityMetadata: "20"}},
+	}, endpoints)
+
+	lookupError := errors.New("lookup timeout")
+	resolver.set(lookupError)
+	assert.Equal(t, lookupError, <-errs)
+	resolver.set(nil, &net.SRV{Target: "b.example.com.", Port: 8412, Priority: 10, Weight: 2})
+	endpoints = <-updates
+	assert.Equal(t, []core.Endpoint{
+		{URL: &url.URL{Scheme: "tcp", Host: "b.example.com:8412"}, Weight: 2, Metadata: map[string]string{discovery.PriorityMetadata: "10"}},
+	}, endpoints)
+	cancel()
+	assert.NoError(t, <-done)
+
+	d = discovery.NewDNSDiscovery("tcp", "hprose", "udp", "example.com", discovery.WithResolver(resolver))
+	assert.EqualError(t, d.Watch(context.Background(), func(endpoints []core.Endpoint) {}), "no such host")
+}
+
+func TestOutlierDetector(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	var servers []Server
+	for _, name := range []string{"A", "B", "C"} {
+		server := Server{Address: "testOutlierDetector" + name}
+		err := service.Bind(server)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+	}
+	var lock sync.Mutex
+	var changes []string
+	detector := outlier.NewDetector(
+		outlier.WithConsecutiveFailures(2),
+		outlier.WithEjectionTime(time.Millisecond*100, time.Second),
+		outlier.WithMaxEjectionRatio(0.5),
+		outlier.WithEjectionChange(func(u string, ejected bool) {
+			lock.Lock()
+			defer lock.Unlock()
+			changes = append(changes, fmt.Sprintf("%s %v", u, ejected))
+		}),
+	)
+	client := core.NewClient("mock://testOutlierDetectorA", "mock://testOutlierDetectorB", "mock://testOutlierDetectorC")
+	lb := loadbalance.NewRoundRobinLoadBalance()
+	client.Use(loadbalance.SkipUnavailable(lb, detector.Available), detector)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	servers[1].Close()
+	servers[2].Close()
+	failures := 0
+	for i := 0; i < 12; i++ {
+		if _, err := proxy.Hello("world"); err != nil {
+			assert.EqualError(t, err, "server is stoped")
+			failures++
+		}
+	}
+	assert.Equal(t, []string{"mock://testOutlierDetectorB"}, detector.Ejected())
+	assert.Equal(t, 7, failures)
+	assert.False(t, detector.Available(context.Background(), client.URLs[1]))
+	assert.True(t, detector.Available(context.Background(), client.URLs[2]))
+	time.Sleep(time.Millisecond * 150)
+	assert.Empty(t, detector.Ejected())
+	lock.Lock()
+	assert.Equal(t, []string{"mock://testOutlierDetectorB true", "mock://testOutlierDetectorB false"}, changes)
+	lock.Unlock()
+	servers[0].Close()
+}
+
+func TestOutlierDetectorProbe(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	server1 := Server{Address: "testOutlierDetectorProbe1"}
+	err := service.Bind(server1)
+	assert.NoError(t, err)
+	server2 := Server{Address: "testOutlierDetectorProbe2"}
+	client := core.NewClient("mock://testOutlierDetectorProbe1", "mock://testOutlierDetectorProbe2")
+	probe := outlier.MethodProbe(client, "")
+	assert.NoError(t, probe(context.Background(), client.URLs[0]))
+	assert.EqualError(t, probe(context.Background(), client.URLs[1]), "server is stoped")
+	detector := outlier.NewDetector(
+		outlier.WithConsecutiveFailures(1),
+		outlier.WithEjectionTime(time.Hour, time.Hour),
+		outlier.WithProbe(probe, time.Millisecond*10),
+	)
+	lb := loadbalance.NewRoundRobinLoadBalance()
+	client.Use(loadbalance.SkipUnavailable(lb, detector.Available), detector)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	for i := 0; i < 4; i++ {
+		_, _ = proxy.Hello("world")
+	}
+	assert.Equal(t, []string{"mock://testOutlierDetectorProbe2"}, detector.Ejected())
+	time.Sleep(time.Millisecond * 50)
+	assert.Equal(t, []string{"mock://testOutlierDetectorProbe2"}, detector.Ejected())
+	err = service.Bind(server2)
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return len(detector.Ejected()) == 0
+	}, time.Second, time.Millisecond*10)
+	for i := 0; i < 4; i++ {
+		result, err := proxy.Hello("world")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", result)
+	}
+	server1.Close()
+	server2.Close()
+}
+
+func TestOutlierDetectorProbeRemoved(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	var servers []Server
+	for _, name := range []string{"A", "B"} {
+		server := Server{Address: "testOutlierDetectorProbeRemoved" + name}
+		err := service.Bind(server)
+		assert.NoError(t, err)
+		servers = append(servers, server)
+	}
+	probing := make(chan struct{}, 1)
+	release := make(chan struct{})
+	probe := func(ctx context.Context, u *url.URL) error {
+		select {
+		case probing <- struct{}{}:
+		default:
+		}
+		<-release
+		return nil
+	}
+	var lock sync.Mutex
+	var changes []string
+	detector := outlier.NewDetector(
+		outlier.WithConsecutiveFailures(1),
+		outlier.WithEjectionTime(time.Hour, time.Hour),
+		outlier.WithProbe(probe, time.Millisecond*10),
+		outlier.WithEjectionChange(func(u string, ejected bool) {
+			lock.Lock()
+			defer lock.Unlock()
+			changes = append(changes, fmt.Sprintf("%s %v", u, ejected))
+		}),
+	)
+	client := core.NewClient("mock://testOutlierDetectorProbeRemovedA", "mock://testOutlierDetectorProbeRemovedB")
+	lb := loadbalance.NewRoundRobinLoadBalance()
+	client.Use(loadbalance.SkipUnavailable(lb, detector.Available), detector)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	servers[1].Close()
+	for i := 0; i < 2; i++ {
+		_, _ = proxy.Hello("world")
+	}
+	assert.Equal(t, []string{"mock://testOutlierDetectorProbeRemovedB"}, detector.Ejected())
+	<-probing
+	client.SetURI("mock://testOutlierDetectorProbeRemovedA")
+	close(release)
+	time.Sleep(time.Millisecond * 50)
+	assert.Empty(t, detector.Ejected())
+	lock.Lock()
+	assert.Equal(t, []string{"mock://testOutlierDetectorProbeRemovedB true"}, changes)
+	lock.Unlock()
+	servers[0].Close()
+}
+
+func TestOutlierDetectorErrorRate(t *testing.T) {
+	detector := outlier.NewDetector(
+		outlier.WithConsecutiveFailures(0),
+		outlier.WithErrorRate(1, 10),
+		outlier.WithInterval(time.Millisecond*50),
+	)
+	client := core.NewClient("mock://a", "mock://b", "mock://c", "mock://d")
+	call := func(u *url.URL, failed bool) {
+		clientContext := core.NewClientContext()
+		clientContext.Init(client)
+		clientContext.URL = u
+		_, _ = detector.Handler(core.WithContext(context.Background(), clientContext), nil,
+			func(ctx context.Context, request []byte) ([]byte, error) {
+				if failed {
+					return nil, core.ErrTimeout
+				}
+				return nil, nil
+			})
+	}
+	for i := 0; i < 10; i++ {
+		call(client.URLs[0], false)
+		call(client.URLs[1], false)
+		call(client.URLs[2], i == 0)
+		call(client.URLs[3], i%2 == 0)
+	}
+	assert.Empty(t, detector.Ejected())
+	time.Sleep(time.Millisecond * 60)
+	call(client.URLs[0], false)
+	assert.Equal(t, []string{"mock://d"}, detector.Ejected())
+}
+
+func TestOutlierDetectorNoURL(t *testing.T) {
+	client := core.NewClient()
+	detector := outlier.NewDetector(outlier.WithConsecutiveFailures(1))
+	client.Use(detector)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	assert.NotPanics(t, func() {
+		_, err := proxy.Hello("world")
+		assert.Error(t, err)
+	})
+	assert.Empty(t, detector.Ejected())
+}
+
+func TestFailoverWithAvailable(t *testing.T) {
+	service := core.NewService()
+	service.AddFunction(func(name string) string {
+		return "hello " + name
+	}, "hello")
+	server1 := Server{Address: "testFailoverWithAvailable1"}
+	err := service.Bind(server1)
+	assert.NoError(t, err)
+	server2 := Server{Address: "testFailoverWithAvailable2"}
+	err = service.Bind(server2)
+	assert.NoError(t, err)
+	client := core.NewClient("mock://testFailoverWithAvailable0", "mock://testFailoverWithAvailable1", "mock://testFailoverWithAvailable2")
+	detector := outlier.NewDetector(outlier.WithConsecutiveFailures(1), outlier.WithMaxEjectionRatio(1))
+	var attempts int32
+	client.Use(
+		cluster.New(cluster.FailoverConfig(
+			cluster.WithIdempotent(true),
+			cluster.WithAvailable(detector.Available),
+			cluster.WithMinInterval(time.Millisecond),
+		)),
+		detector,
+		func(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
+			atomic.AddInt32(&attempts, 1)
+			return next(ctx, request)
+		},
+	)
+	var proxy struct {
+		Hello func(name string) (string, error)
+	}
+	client.UseService(&proxy)
+	for i := 0; i < 6; i++ {
+		result, err := proxy.Hello("world")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", result)
+	}
+	assert.Equal(t, int32(12), atomic.LoadInt32(&attempts))
+	assert.Equal(t, []string{"mock://testFailoverWithAvailable0"}, detector.Ejected())
+	server1.Close()
+	server2.Close()
+}
+
+func TestHedging(t *testing.T) {
+	var canceled int32
+	slowService := core.NewService()
+	slowService.AddFunction(func(ctx context.Context) string {
+		select {
+		case <-time.After(time.Millisecond * 300):
+		case <-ctx.Done():
+			atomic.AddInt32(&canceled, 1)
+		}
+		return "slow"
+	}, "name")
+	slowServer := Server{Address: "testHedgingSlow"}
+	err := slowService.Bind(slowServer)
+	assert.NoError(t, err)
+	fastService := core.NewService()
+	fastService.AddFunction(func() string {
+		return "fast"
+	}, "name")
+	fastServer := Server{Address: "testHedgingFast"}
+	err = fastService.Bind(fastServer)
+	assert.NoError(t, err)
+	var proxy struct {
+		Name func() (string, error)
+	}
+
+	client := core.NewClient("mock://testHedgingSlow", "mock://testHedgingFast")
+	hedging := cluster.NewHedging(
+		cluster.WithIdempotent(true),
+		cluster.WithMinInterval(time.Millisecond*5),
+		cluster.WithMaxInterval(time.Millisecond*50),
+	)
+	assert.Equal(t, time.Millisecond*50, hedging.Delay())
+	client.Use(hedging)
+	client.UseService(&proxy)
+	start := time.Now()
+	name, err := proxy.Name()
+	assert.NoError(t, err)
+	assert.Equal(t, "fast", name)
+	assert.Less(t, int64(time.Since(start)), int64(time.Millisecond*300))
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&canceled) == 1
+	}, time.Second, time.Millisecond)
+
+	client = core.NewClient("mock://testHedgingFast", "mock://testHedgingSlow")
+	client.Use(hedging)
+	client.UseService(&proxy)
+	for i := 0; i < 20; i++ {
+		name, err := proxy.Name()
+		assert.NoError(t, err)
+		assert.Equal(t, "fast", name)
+	}
+	assert.Equal(t, time.Millisecond*5, hedging.Delay())
+
+	client = core.NewClient("mock://testHedgingStopped", "mock://testHedgingFast")
+	client.Use(cluster.NewHedging(
+		cluster.WithIdempotent(true),
+		cluster.WithMaxInterval(time.Second),
+	))
+	client.UseService(&proxy)
+	start = time.Now()
+	name, err = proxy.Name()
+	assert.NoError(t, err)
+	assert.Equal(t, "fast", name)
+	assert.Less(t, int64(time.Since(start)), int64(time.Millisecond*500))
+
+	client = core.NewClient("mock://testHedgingSlow", "mock://testHedgingFast")
+	client.Use(cluster.NewHedging(cluster.WithMaxInterval(time.Millisecond * 10)))
+	client.UseService(&proxy)
+	name, err = proxy.Name()
+	assert.NoError(t, err)
+	assert.Equal(t, "slow", name)
+
+	slowServer.Close()
+	fastServer.Close()
+}
+
+func TestHedgingRecordsFirstRequests(t *testing.T) {
+	slowService := core.NewService()
+	slowService.AddFunction(func(ctx context.Context) string {
+		select {
+		case <-time.After(time.Millisecond * 300):
+		case <-ctx.Done():
+		}
+		return "slow"
+	}, "name")
+	slowServer := Server{Address: "testHedgingRecordsSlow"}
+	err := slowService.Bind(slowServer)
+	assert.NoError(t, err)
+	fastService := core.NewService()
+	fastService.AddFunction(func() string {
+		return "fast"
+	}, "name")
+	fastServer := Server{Address: "testHedgingRecordsFast"}
+	err = fastService.Bind(fastServer)
+	assert.NoError(t, err)
+	var proxy struct {
+		Name func() (string, error)
+	}
+	client := core.NewClient("mock://testHedgingRecordsSlow", "mock://testHedgingRecordsFast")
+	hedging := cluster.NewHedging(
+		cluster.WithIdempotent(true),
+		cluster.WithMinInterval(time.Millisecond),
+		cluster.WithMaxInterval(time.Millisecond*20),
+	)
+	client.Use(hedging)
+	client.UseService(&proxy)
+	for i := 0; i < 20; i++ {
+		name, err := proxy.Name()
+		assert.NoError(t, err)
+		assert.Equal(t, "fast", name)
+	}
+	assert.Equal(t, time.Millisecond*20, hedging.Delay())
+	slowServer.Close()
+	fastServer.Close()
+}
+
 func TestOneway(t *testing.T) {
 	service := core.NewService()
 	service.Codec = core.NewServiceCodec(core.WithDebug(true))
@@ -0,0 +1,483 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/circuitbreaker/breaker.go                    |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package circuitbreaker
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+// State of the circuit.
+type State int32
+
+const (
+	// StateClosed means the calls are allowed.
+	StateClosed State = iota
+	// StateOpen means the calls are rejected by ErrBreaker.
+	StateOpen
+	// StateHalfOpen means a limited number of trial calls are allowed.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "state(" + strconv.Itoa(int(s)) + ")"
+}
+
+// KeyFunc returns the circuit key of the call to url for the method.
+type KeyFunc func(u *url.URL, method string) string
+
+// KeyByURL returns the url as the key, every endpoint has its own circuit.
+func KeyByURL(u *url.URL, method string) string {
+	if u == nil {
+		return ""
+	}
+	return u.String()
+}
+
+// KeyByMethod returns the method name as the key, every method has its own
+// circuit.
+func KeyByMethod(u *url.URL, method string) string {
+	return method
+}
+
+// KeyByURLAndMethod returns the url and the method name as the key, every
+// method of every endpoint has its own circuit.
+func KeyByURLAndMethod(u *url.URL, method string) string {
+	return KeyByURL(u, method) + "#" + method
+}
+
+const bucketCount = 10
+
+type bucket struct {
+	epoch    int64
+	total    int
+	failures int
+	slow     int
+}
+
+type circuit struct {
+	state      State
+	usedAt     time.Time
+	generation uint64
+	openedAt   time.Time
+	buckets    [bucketCount]bucket
+	trials     int
+	successes  int
+}
+
+// Breaker plugin for hprose.
+//
+// Breaker keeps a circuit for every key returned by its KeyFunc, the
+// circuits are closed, open or half-open. A closed circuit is opened when the
+// failure rate or the slow call rate over the window reaches the threshold,
+// an open circuit turns to half-open after openTimeout, and a half-open
+// circuit allows halfOpenCalls trial calls, it is closed if all the trial
+// calls succeed, otherwise it is opened again.
+//
+// The transport errors are recorded by IOHandler. Use both IOHandler and
+// InvokeHandler of Breaker, by client.Use(breaker), to break the calls by the
+// method names, and to record the errors returned by the remote methods.
+//
+// When there are maxKeys circuits, the closed circuits which are not used in
+// the window are removed, and then the least recently used closed circuits
+// if there are still too many. Breaker.Available can be used by
+// loadbalance.SkipUnavailable to skip the endpoints with open circuits.
+type Breaker struct {
+	key           KeyFunc
+	window        time.Duration
+	minRequests   int
+	failureRate   float64
+	slowCallTime  time.Duration
+	slowCallRate  float64
+	openTimeout   time.Duration
+	halfOpenCalls int
+	onStateChange func(key string, from, to State)
+	mockService   MockService
+	maxKeys       int
+	circuits      map[string]*circuit
+	lock          sync.Mutex
+}
+
+// BreakerOption for Breaker.
+type BreakerOption func(*Breaker)
+
+// WithKeyFunc returns a key Option for Breaker.
+func WithKeyFunc(key KeyFunc) BreakerOption {
+	return func(b *Breaker) {
+		b.key = key
+	}
+}
+
+// WithWindow returns a window Option for Breaker, the failure rate and the
+// slow call rate are computed over the calls in window.
+func WithWindow(window time.Duration) BreakerOption {
+	return func(b *Breaker) {
+		b.window = window
+	}
+}
+
+// WithMinRequests returns a minRequests Option for Breaker, the circuit is
+// not opened until there are minRequests calls in the window.
+func WithMinRequests(minRequests int) BreakerOption {
+	return func(b *Breaker) {
+		b.minRequests = minRequests
+	}
+}
+
+// WithFailureRate returns a failureRate Option for Breaker.
+func WithFailureRate(failureRate float64) BreakerOption {
+	return func(b *Breaker) {
+		b.failureRate = failureRate
+	}
+}
+
+// WithSlowCall returns a slow call Option for Breaker, the calls longer than
+// slowCallTime are slow, and the circuit is opened when the slow call rate
+// reaches slowCallRate.
+func WithSlowCall(slowCallTime time.Duration, slowCallRate float64) BreakerOption {
+	return func(b *Breaker) {
+		b.slowCallTime = slowCallTime
+		b.slowCallRate = slowCallRate
+	}
+}
+
+// WithOpenTimeout returns an openTimeout Option for Breaker.
+func WithOpenTimeout(openTimeout time.Duration) BreakerOption {
+	return func(b *Breaker) {
+		b.openTimeout = openTimeout
+	}
+}
+
+// WithHalfOpenCalls returns a halfOpenCalls Option for Breaker.
+func WithHalfOpenCalls(halfOpenCalls int) BreakerOption {
+	return func(b *Breaker) {
+		b.halfOpenCalls = halfOpenCalls
+	}
+}
+
+// WithStateChange returns an onStateChange Option for Breaker, it is called
+// when the state of the circuit of key changes.
+func WithStateChange(onStateChange func(key string, from, to State)) BreakerOption {
+	return func(b *Breaker) {
+		b.onStateChange = onStateChange
+	}
+}
+
+// WithMaxKeys returns a maxKeys Option for Breaker.
+func WithMaxKeys(maxKeys int) BreakerOption {
+	return func(b *Breaker) {
+		b.maxKeys = maxKeys
+	}
+}
+
+// WithFallback returns a mockService Option for Breaker, it is called
+// instead of the broken calls.
+func WithFallback(mockService MockService) BreakerOption {
+	return func(b *Breaker) {
+		b.mockService = mockService
+	}
+}
+
+// NewBreaker returns a Breaker instance.
+func NewBreaker(options ...BreakerOption) *Breaker {
+	b := &Breaker{
+		key:           KeyByURL,
+		window:        time.Second * 10,
+		minRequests:   10,
+		failureRate:   0.5,
+		openTimeout:   time.Second * 30,
+		halfOpenCalls: 1,
+		maxKeys:       DefaultMaxKeys,
+		circuits:      make(map[string]*circuit),
+	}
+	for _, option := range options {
+		option(b)
+	}
+	return b
+}
+
+// DefaultMaxKeys is the default maximum number of circuits.
+const DefaultMaxKeys = 10000
+
+func (b *Breaker) circuit(key string, now time.Time) *circuit {
+	c, ok := b.circuits[key]
+	if !ok {
+		if b.maxKeys > 0 && len(b.circuits) >= b.maxKeys {
+			b.prune(now)
+		}
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+	c.usedAt = now
+	return c
+}
+
+func (b *Breaker) prune(now time.Time) {
+	var closed []string
+	for key, c := range b.circuits {
+		if c.state == StateClosed {
+			if now.Sub(c.usedAt) >= b.window {
+				delete(b.circuits, key)
+			} else {
+				closed = append(closed, key)
+			}
+		}
+	}
+	if n := len(b.circuits) - b.maxKeys + 1; n > 0 && len(closed) > 0 {
+		sort.Slice(closed, func(i, j int) bool {
+			return b.circuits[closed[i]].usedAt.Before(b.circuits[closed[j]].usedAt)
+		})
+		if n > len(closed) {
+			n = len(closed)
+		}
+		for _, key := range closed[:n] {
+			delete(b.circuits, key)
+		}
+	}
+}
+
+// transition of the circuit state, it is reported after unlocking, so
+// onStateChange can call the methods of Breaker.
+type transition struct {
+	key      string
+	from, to State
+}
+
+func (b *Breaker) report(t *transition) {
+	if t != nil && b.onStateChange != nil {
+		b.onStateChange(t.key, t.from, t.to)
+	}
+}
+
+func (b *Breaker) setState(key string, c *circuit, state State, now time.Time) *transition {
+	from := c.state
+	c.state = state
+	c.generation++
+	c.buckets = [bucketCount]bucket{}
+	c.trials = 0
+	c.successes = 0
+	if state == StateOpen {
+		c.openedAt = now
+	}
+	return &transition{key, from, state}
+}
+
+// allow returns the generation of the circuit if the call is allowed.
+func (b *Breaker) allow(key string, now time.Time) (generation uint64, ok bool) {
+	var t *transition
+	defer func() { b.report(t) }()
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	c := b.circuit(key, now)
+	if c.state == StateOpen {
+		if now.Sub(c.openedAt) < b.openTimeout {
+			return 0, false
+		}
+		t = b.setState(key, c, StateHalfOpen, now)
+	}
+	if c.state == StateHalfOpen {
+		if c.trials >= b.halfOpenCalls {
+			return 0, false
+		}
+		c.trials++
+	}
+	return c.generation, true
+}
+
+func (b *Breaker) record(key string, generation uint64, failed bool, rtt time.Duration, now time.Time) {
+	slow := b.slowCallTime > 0 && rtt > b.slowCallTime
+	var t *transition
+	defer func() { b.report(t) }()
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	c, ok := b.circuits[key]
+	if !ok || c.generation != generation {
+		return
+	}
+	if c.state == StateHalfOpen {
+		if failed || slow {
+			t = b.setState(key, c, StateOpen, now)
+		} else if c.successes++; c.successes >= b.halfOpenCalls {
+			t = b.setState(key, c, StateClosed, now)
+		}
+		return
+	}
+	size := int64(b.window) / bucketCount
+	if size <= 0 {
+		size = 1
+	}
+	epoch := now.UnixNano() / size
+	current := &c.buckets[epoch%bucketCount]
+	if current.epoch != epoch {
+		*current = bucket{epoch: epoch}
+	}
+	current.total++
+	if failed {
+		current.failures++
+	}
+	if slow {
+		current.slow++
+	}
+	var total, failures, slows int
+	for _, e := range c.buckets {
+		if e.epoch > epoch-bucketCount {
+			total += e.total
+			failures += e.failures
+			slows += e.slow
+		}
+	}
+	if total < b.minRequests {
+		return
+	}
+	if float64(failures)/float64(total) >= b.failureRate ||
+		(b.slowCallRate > 0 && float64(slows)/float64(total) >= b.slowCallRate) {
+		t = b.setState(key, c, StateOpen, now)
+	}
+}
+
+const (
+	methodKey  = "circuitbreaker.method"
+	handoffKey = "circuitbreaker.handoff"
+)
+
+// pending is the result of the successful transport, which is recorded by
+// InvokeHandler after the response is decoded.
+type pending struct {
+	key        string
+	generation uint64
+	rtt        time.Duration
+}
+
+// handoff passes the pending results from IOHandler to InvokeHandler. It is
+// stored in the items before the invocation, so it is shared by the contexts
+// cloned by Hedging and Forking, which may run IOHandler more than once.
+type handoff struct {
+	pendings []pending
+	done     bool
+	lock     sync.Mutex
+}
+
+// add returns false if the invocation has completed.
+func (h *handoff) add(p pending) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.done {
+		return false
+	}
+	h.pendings = append(h.pendings, p)
+	return true
+}
+
+// complete returns the pending results, the later results are not added.
+func (h *handoff) complete() []pending {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.done = true
+	pendings := h.pendings
+	h.pendings = nil
+	return pendings
+}
+
+func method(clientContext *core.ClientContext) string {
+	return clientContext.Items().GetString(methodKey)
+}
+
+// State returns the state of the circuit of key.
+func (b *Breaker) State(key string) State {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if c, ok := b.circuits[key]; ok {
+		return c.state
+	}
+	return StateClosed
+}
+
+// Available returns false if the circuit of the call to u is open and
+// doesn't allow trial calls now.
+func (b *Breaker) Available(ctx context.Context, u *url.URL) bool {
+	key := b.key(u, method(core.GetClientContext(ctx)))
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	c, ok := b.circuits[key]
+	if !ok {
+		return true
+	}
+	switch c.state {
+	case StateOpen:
+		return time.Since(c.openedAt) >= b.openTimeout
+	case StateHalfOpen:
+		return c.trials < b.halfOpenCalls
+	}
+	return true
+}
+
+// IOHandler for Breaker.
+func (b *Breaker) IOHandler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
+	clientContext := core.GetClientContext(ctx)
+	key := b.key(clientContext.URL, method(clientContext))
+	generation, ok := b.allow(key, time.Now())
+	if !ok {
+		return nil, ErrBreaker
+	}
+	start := time.Now()
+	defer func() {
+		if e := recover(); e != nil {
+			err = core.NewPanicError(e)
+		}
+		rtt := time.Since(start)
+		if h, ok := clientContext.Items().Get(handoffKey); ok && err == nil && h.(*handoff).add(pending{key, generation, rtt}) {
+			return
+		}
+		b.record(key, generation, err != nil, rtt, time.Now())
+	}()
+	return next(ctx, request)
+}
+
+// InvokeHandler for Breaker.
+func (b *Breaker) InvokeHandler(ctx context.Context, name string, args []interface{}, next core.NextInvokeHandler) (result []interface{}, err error) {
+	items := core.GetClientContext(ctx).Items()
+	items.Set(methodKey, name)
+	h := &handoff{}
+	items.Set(handoffKey, h)
+	result, err = next(ctx, name, args)
+	items.Del(handoffKey)
+	for _, p := range h.complete() {
+		b.record(p.key, p.generation, err != nil, p.rtt, time.Now())
+	}
+	if err == ErrBreaker && b.mockService != nil {
+		return b.mockService(ctx, name, args)
+	}
+	return
+}
+
+// Keys returns the number of the circuits in Breaker.
+func (b *Breaker) Keys() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return len(b.circuits)
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/plugins/cluster/cluster.go                           |
 |                                                          |
-| LastModified: Mar 6, 2021                                |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,6 +15,7 @@ package cluster
 
 import (
 	"context"
+	"net/url"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -31,6 +32,8 @@ type Config struct {
 	OnRetry     func(context.Context) time.Duration
 	minInterval time.Duration
 	maxInterval time.Duration
+	available   func(ctx context.Context, u *url.URL) bool
+	percentile  float64
 }
 
 // Option for cluster config.
@@ -64,6 +67,15 @@ func WithMaxInterval(maxInterval time.Duration) Option {
 	}
 }
 
+// WithAvailable returns an Option for failover cluster config, which skips
+// the urls that available returns false on failure, such as the endpoints
+// ejected by outlier.Detector.
+func WithAvailable(available func(ctx context.Context, u *url.URL) bool) Option {
+	return func(c *Config) {
+		c.available = available
+	}
+}
+
 func getIndex(index *int64, n int64) int64 {
 	if n > 1 {
 		if i := atomic.AddInt64(index, 1); i < n {
@@ -85,14 +97,18 @@ func FailoverConfig(options ...Option) (config Config) {
 	var index int64
 	config.OnFailure = func(ctx context.Context) {
 		clientContext := core.GetClientContext(ctx)
-		urls := clientContext.Client().URLs
-		clientContext.URL = urls[getIndex(&index, int64(len(urls)))]
+		urls := clientContext.URLs()
+		n := int64(len(urls))
+		clientContext.URL = urls[getIndex(&index, n)]
+		for i := int64(1); i < n && config.available != nil && !config.available(ctx, clientContext.URL); i++ {
+			clientContext.URL = urls[getIndex(&index, n)]
+		}
 	}
 	config.OnRetry = func(ctx context.Context) time.Duration {
 		clientContext := core.GetClientContext(ctx)
 		retried := clientContext.Items().GetInt("retried") + 1
 		clientContext.Items().Set("retried", retried)
-		interval := config.minInterval * time.Duration(retried-len(clientContext.Client().URLs))
+		interval := config.minInterval * time.Duration(retried-len(clientContext.URLs()))
 		if interval > config.maxInterval {
 			interval = config.maxInterval
 		}
@@ -184,7 +200,7 @@ func (c *Cluster) Handler(ctx context.Context, request []byte, next core.NextIOH
 // Forking on Cluster.
 func Forking(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
 	clientContext := core.GetClientContext(ctx)
-	urls := clientContext.Client().URLs
+	urls := clientContext.URLs()
 	n := len(urls)
 	if n == 0 {
 		return next(ctx, request)
@@ -227,7 +243,7 @@ func Forking(ctx context.Context, request []byte, next core.NextIOHandler) (resp
 // Broadcast on Cluster.
 func Broadcast(ctx context.Context, name string, args []interface{}, next core.NextInvokeHandler) (result []interface{}, err error) {
 	clientContext := core.GetClientContext(ctx)
-	urls := clientContext.Client().URLs
+	urls := clientContext.URLs()
 	n := len(urls)
 	if n == 0 {
 		return next(ctx, name, args)
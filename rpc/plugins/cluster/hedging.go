@@ -0,0 +1,197 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/cluster/hedging.go                           |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package cluster
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+const (
+	maxSamples = 1000
+	minSamples = 10
+)
+
+// WithPercentile returns a percentile Option for hedging, the backup request
+// is sent when no response arrives within this percentile of the latencies.
+func WithPercentile(percentile float64) Option {
+	return func(c *Config) {
+		c.percentile = percentile
+	}
+}
+
+// Hedging plugin for hprose.
+//
+// Hedging sends the request to the selected url, and if no response arrives
+// within the delay, sends a backup request to the next url, at most Retry
+// backup requests are sent. A backup request is also sent at once when a
+// request fails. The first success response is returned, and the other
+// requests are canceled.
+//
+// The delay is the percentile of the recent latencies of the first requests,
+// between minInterval and maxInterval, it is maxInterval until enough
+// latencies are recorded. The latencies of the backup requests are not
+// recorded, and a first request canceled by a backup request is recorded
+// with its elapsed time, which is the lower bound of its latency, so the
+// delay does not drift below the latencies of the first requests.
+// Only the idempotent calls are hedged.
+type Hedging struct {
+	Config
+	samples []time.Duration
+	index   int
+	added   int
+	delay   time.Duration
+	lock    sync.Mutex
+}
+
+// NewHedging returns a Hedging instance.
+func NewHedging(options ...Option) *Hedging {
+	h := &Hedging{}
+	h.Retry = 1
+	h.minInterval = time.Millisecond
+	h.maxInterval = time.Second
+	h.percentile = 0.95
+	for _, option := range options {
+		option(&h.Config)
+	}
+	h.delay = h.maxInterval
+	return h
+}
+
+// Delay returns the current delay of the backup requests.
+func (h *Hedging) Delay() time.Duration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.delay
+}
+
+func (h *Hedging) record(latency time.Duration) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if len(h.samples) < maxSamples {
+		h.samples = append(h.samples, latency)
+	} else {
+		h.samples[h.index] = latency
+		h.index = (h.index + 1) % maxSamples
+	}
+	h.added++
+	if n := len(h.samples); n >= minSamples && (n < maxSamples || h.added%minSamples == 0) {
+		samples := append([]time.Duration(nil), h.samples...)
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		delay := samples[int(h.percentile*float64(n-1))]
+		switch {
+		case delay < h.minInterval:
+			delay = h.minInterval
+		case delay > h.maxInterval:
+			delay = h.maxInterval
+		}
+		h.delay = delay
+	}
+}
+
+type hedgingResult struct {
+	url      *url.URL
+	response []byte
+	err      error
+}
+
+// Handler for Hedging.
+func (h *Hedging) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
+	clientContext := core.GetClientContext(ctx)
+	urls := clientContext.URLs()
+	idempotent := clientContext.Items().GetBool("idempotent", h.Idempotent)
+	backups := clientContext.Items().GetInt("retry", h.Retry)
+	if backups > len(urls)-1 {
+		backups = len(urls) - 1
+	}
+	if !idempotent || backups <= 0 {
+		start := time.Now()
+		if response, err = next(ctx, request); err == nil {
+			h.record(time.Since(start))
+		}
+		return
+	}
+	start := 0
+	for i, u := range urls {
+		if u == clientContext.URL {
+			start = i
+			break
+		}
+	}
+	results := make(chan hedgingResult, backups+1)
+	cancels := make([]context.CancelFunc, 0, backups+1)
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+	send := func(u *url.URL, first bool) {
+		hedgingContext := clientContext.Clone().(*core.ClientContext)
+		hedgingContext.URL = u
+		hedgingCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		go func(ctx context.Context) {
+			var result hedgingResult
+			defer func() {
+				if e := recover(); e != nil {
+					result.err = core.NewPanicError(e)
+				}
+				results <- result
+			}()
+			begin := time.Now()
+			result.url = u
+			result.response, result.err = next(ctx, request)
+			if first && (result.err == nil || ctx.Err() != nil) {
+				h.record(time.Since(begin))
+			}
+		}(core.WithContext(hedgingCtx, hedgingContext))
+	}
+	if clientContext.URL == nil {
+		clientContext.URL = urls[start]
+	}
+	send(clientContext.URL, true)
+	sent, pending := 1, 1
+	timer := time.NewTimer(h.Delay())
+	defer timer.Stop()
+	for {
+		select {
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				clientContext.URL = result.url
+				return result.response, nil
+			}
+			err = result.err
+			if sent <= backups && ctx.Err() == nil {
+				send(urls[(start+sent)%len(urls)], false)
+				sent++
+				pending++
+			} else if pending == 0 {
+				return nil, err
+			}
+		case <-timer.C:
+			if sent <= backups && ctx.Err() == nil {
+				send(urls[(start+sent)%len(urls)], false)
+				sent++
+				pending++
+				timer.Reset(h.Delay())
+			}
+		}
+	}
+}
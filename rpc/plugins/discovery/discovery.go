@@ -0,0 +1,84 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/discovery/discovery.go                       |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package discovery
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+// StaticDiscovery is a core.Discovery with a fixed list of endpoints.
+type StaticDiscovery struct {
+	endpoints []core.Endpoint
+}
+
+// NewStaticDiscovery returns a StaticDiscovery instance.
+func NewStaticDiscovery(endpoints ...core.Endpoint) *StaticDiscovery {
+	return &StaticDiscovery{endpoints: endpoints}
+}
+
+// Watch calls update with the endpoints once, and waits until ctx is done.
+func (d *StaticDiscovery) Watch(ctx context.Context, update func(endpoints []core.Endpoint)) error {
+	update(d.endpoints)
+	<-ctx.Done()
+	return nil
+}
+
+// poll calls resolve every interval until ctx is done, and calls update when
+// the endpoints are changed. The error of the first resolve is returned, the
+// later errors are passed to onError, and the last endpoints are kept.
+func poll(ctx context.Context, interval time.Duration, onError func(error), resolve func(ctx context.Context) ([]core.Endpoint, error), update func(endpoints []core.Endpoint)) error {
+	endpoints, err := resolve(ctx)
+	if err != nil {
+		return err
+	}
+	update(endpoints)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		current, err := resolve(ctx)
+		switch {
+		case err != nil:
+			if onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		case !equal(endpoints, current):
+			endpoints = current
+			update(endpoints)
+		}
+	}
+}
+
+func equal(x, y []core.Endpoint) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i].URL.String() != y[i].URL.String() ||
+			x[i].Weight != y[i].Weight ||
+			len(x[i].Metadata) != len(y[i].Metadata) ||
+			(len(x[i].Metadata) > 0 && !reflect.DeepEqual(x[i].Metadata, y[i].Metadata)) {
+			return false
+		}
+	}
+	return true
+}
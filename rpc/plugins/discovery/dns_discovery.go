@@ -0,0 +1,134 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/discovery/dns_discovery.go                   |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package discovery
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+// PriorityMetadata is the metadata key of the SRV record priority.
+const PriorityMetadata = "priority"
+
+// Resolver looks up the SRV records. *net.Resolver implements it.
+type Resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (cname string, addrs []*net.SRV, err error)
+}
+
+// DNSDiscovery is a core.Discovery which watches the endpoints in the DNS SRV
+// records.
+//
+// Each SRV record is an endpoint scheme://target:port, its weight is the
+// record weight (1 when it is 0), and its priority is in the metadata with
+// the key PriorityMetadata.
+type DNSDiscovery struct {
+	scheme   string
+	service  string
+	proto    string
+	name     string
+	resolver Resolver
+	interval time.Duration
+	onError  func(error)
+}
+
+// DNSOption for DNSDiscovery.
+type DNSOption func(*DNSDiscovery)
+
+// WithResolver returns a DNSOption that sets the resolver, net.DefaultResolver
+// is used by default.
+func WithResolver(resolver Resolver) DNSOption {
+	return func(d *DNSDiscovery) {
+		d.resolver = resolver
+	}
+}
+
+// WithDNSInterval returns a DNSOption that sets the interval of looking up.
+func WithDNSInterval(interval time.Duration) DNSOption {
+	return func(d *DNSDiscovery) {
+		d.interval = interval
+	}
+}
+
+// WithDNSErrorHandler returns a DNSOption that sets the handler of the errors
+// which occur after the first looking up.
+func WithDNSErrorHandler(onError func(error)) DNSOption {
+	return func(d *DNSDiscovery) {
+		d.onError = onError
+	}
+}
+
+// NewDNSDiscovery returns a DNSDiscovery instance which looks up
+// _service._proto.name, and makes the endpoints with scheme.
+func NewDNSDiscovery(scheme, service, proto, name string, options ...DNSOption) *DNSDiscovery {
+	d := &DNSDiscovery{
+		scheme:   scheme,
+		service:  service,
+		proto:    proto,
+		name:     name,
+		resolver: net.DefaultResolver,
+		interval: time.Second * 30,
+	}
+	for _, option := range options {
+		option(d)
+	}
+	return d
+}
+
+// Interval returns the interval of looking up.
+func (d *DNSDiscovery) Interval() time.Duration {
+	return d.interval
+}
+
+// Watch the SRV records until ctx is done. It returns the error of the first
+// looking up.
+func (d *DNSDiscovery) Watch(ctx context.Context, update func(endpoints []core.Endpoint)) error {
+	return poll(ctx, d.interval, d.onError, d.lookup, update)
+}
+
+func (d *DNSDiscovery) lookup(ctx context.Context) ([]core.Endpoint, error) {
+	_, addrs, err := d.resolver.LookupSRV(ctx, d.service, d.proto, d.name)
+	if err != nil {
+		return nil, err
+	}
+	// the records are shuffled by the resolver, sort them to detect changes.
+	sort.Slice(addrs, func(i, j int) bool {
+		if addrs[i].Priority != addrs[j].Priority {
+			return addrs[i].Priority < addrs[j].Priority
+		}
+		if addrs[i].Target != addrs[j].Target {
+			return addrs[i].Target < addrs[j].Target
+		}
+		return addrs[i].Port < addrs[j].Port
+	})
+	endpoints := make([]core.Endpoint, len(addrs))
+	for i, addr := range addrs {
+		host := net.JoinHostPort(strings.TrimSuffix(addr.Target, "."), strconv.Itoa(int(addr.Port)))
+		endpoints[i] = core.Endpoint{
+			URL:      &url.URL{Scheme: d.scheme, Host: host},
+			Weight:   int(addr.Weight),
+			Metadata: map[string]string{PriorityMetadata: strconv.Itoa(int(addr.Priority))},
+		}
+		if endpoints[i].Weight == 0 {
+			endpoints[i].Weight = 1
+		}
+	}
+	return endpoints, nil
+}
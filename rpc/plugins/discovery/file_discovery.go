@@ -0,0 +1,151 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/discovery/file_discovery.go                  |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package discovery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+// FileDiscovery is a core.Discovery which watches the endpoints in a file.
+//
+// Each non-empty line of the file is an endpoint, the lines starting with #
+// are comments:
+//
+//	# uri [weight] [key=value ...]
+//	tcp://10.0.0.1:8412 10 zone=a
+//	tcp://10.0.0.2:8412 5 zone=b
+//
+// The weight is 1 when omitted. The file is checked every interval, and is
+// reloaded when its size or modification time is changed.
+type FileDiscovery struct {
+	path     string
+	interval time.Duration
+	onError  func(error)
+	modTime  time.Time
+	size     int64
+	last     []core.Endpoint
+}
+
+// FileOption for FileDiscovery.
+type FileOption func(*FileDiscovery)
+
+// WithFileInterval returns a FileOption that sets the interval of checking the file.
+func WithFileInterval(interval time.Duration) FileOption {
+	return func(d *FileDiscovery) {
+		d.interval = interval
+	}
+}
+
+// WithFileErrorHandler returns a FileOption that sets the handler of the errors
+// which occur after the file is loaded for the first time.
+func WithFileErrorHandler(onError func(error)) FileOption {
+	return func(d *FileDiscovery) {
+		d.onError = onError
+	}
+}
+
+// NewFileDiscovery returns a FileDiscovery instance.
+func NewFileDiscovery(path string, options ...FileOption) *FileDiscovery {
+	d := &FileDiscovery{
+		path:     path,
+		interval: time.Second * 5,
+	}
+	for _, option := range options {
+		option(d)
+	}
+	return d
+}
+
+// Path returns the path of the file.
+func (d *FileDiscovery) Path() string {
+	return d.path
+}
+
+// Interval returns the interval of checking the file.
+func (d *FileDiscovery) Interval() time.Duration {
+	return d.interval
+}
+
+// Watch the file until ctx is done. It returns the error if the file can't be
+// loaded for the first time.
+func (d *FileDiscovery) Watch(ctx context.Context, update func(endpoints []core.Endpoint)) error {
+	d.modTime, d.size, d.last = time.Time{}, -1, nil
+	return poll(ctx, d.interval, d.onError, d.load, update)
+}
+
+func (d *FileDiscovery) load(ctx context.Context) ([]core.Endpoint, error) {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return nil, err
+	}
+	if info.ModTime().Equal(d.modTime) && info.Size() == d.size {
+		return d.last, nil
+	}
+	data, err := ioutil.ReadFile(d.path)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := ParseEndpoints(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", d.path, err)
+	}
+	d.modTime, d.size, d.last = info.ModTime(), info.Size(), endpoints
+	return endpoints, nil
+}
+
+// ParseEndpoints parses the endpoints in the FileDiscovery format.
+func ParseEndpoints(data []byte) (endpoints []core.Endpoint, err error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(line)
+		endpoint := core.Endpoint{Weight: 1}
+		if endpoint.URL, err = url.Parse(fields[0]); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		fields = fields[1:]
+		if len(fields) > 0 && !strings.Contains(fields[0], "=") {
+			if endpoint.Weight, err = strconv.Atoi(fields[0]); err != nil || endpoint.Weight <= 0 {
+				return nil, fmt.Errorf("line %d: invalid weight %q", n, fields[0])
+			}
+			fields = fields[1:]
+		}
+		for _, field := range fields {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 || kv[0] == "" {
+				return nil, fmt.Errorf("line %d: invalid metadata %q", n, field)
+			}
+			if endpoint.Metadata == nil {
+				endpoint.Metadata = make(map[string]string)
+			}
+			endpoint.Metadata[kv[0]] = kv[1]
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, scanner.Err()
+}
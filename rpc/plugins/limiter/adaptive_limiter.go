@@ -0,0 +1,357 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/limiter/adaptive_limiter.go                  |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package limiter
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+// Sample of a completed request for LimitAlgorithm.
+type Sample struct {
+	// RTT is the latency of the request.
+	RTT time.Duration
+	// InFlight is the number of the in-flight requests when the request started.
+	InFlight int
+	// Dropped is true if the request failed.
+	Dropped bool
+}
+
+// LimitAlgorithm updates the concurrency limit of AdaptiveLimiter by the
+// samples, Update is called serially by AdaptiveLimiter.
+type LimitAlgorithm interface {
+	Update(limit float64, sample Sample) float64
+}
+
+// AIMD (additive increase multiplicative decrease) LimitAlgorithm, it
+// increases the limit by 1 when a request succeeds while the limit is used
+// up by half, and decreases the limit by BackoffRatio when a request fails or
+// its latency exceeds Timeout.
+type AIMD struct {
+	// BackoffRatio is 0.9 by default.
+	BackoffRatio float64
+	// Timeout is the max latency of the requests, 0 means no max latency.
+	Timeout time.Duration
+}
+
+// Update implements the LimitAlgorithm interface.
+func (a *AIMD) Update(limit float64, sample Sample) float64 {
+	if sample.Dropped || (a.Timeout > 0 && sample.RTT > a.Timeout) {
+		ratio := a.BackoffRatio
+		if ratio <= 0 || ratio >= 1 {
+			ratio = 0.9
+		}
+		return limit * ratio
+	}
+	if float64(sample.InFlight)*2 >= limit {
+		return limit + 1
+	}
+	return limit
+}
+
+// Gradient LimitAlgorithm, it compares the latency of every request with
+// the long-term average latency, and adjusts the limit by their ratio.
+type Gradient struct {
+	// Tolerance of the latency increase, it is 1.5 by default.
+	Tolerance float64
+	// Smoothing factor of the limit, it is 0.2 by default.
+	Smoothing float64
+	longRTT   float64
+}
+
+// Update implements the LimitAlgorithm interface.
+func (g *Gradient) Update(limit float64, sample Sample) float64 {
+	tolerance, smoothing := g.Tolerance, g.Smoothing
+	if tolerance < 1 {
+		tolerance = 1.5
+	}
+	if smoothing <= 0 || smoothing > 1 {
+		smoothing = 0.2
+	}
+	if sample.Dropped {
+		return limit * (1 - smoothing/2)
+	}
+	rtt := float64(sample.RTT)
+	if rtt <= 0 {
+		return limit
+	}
+	if g.longRTT == 0 {
+		g.longRTT = rtt
+	} else {
+		g.longRTT = g.longRTT*0.95 + rtt*0.05
+	}
+	// The requests don't use up the limit, their latency doesn't show
+	// whether the limit is too high.
+	if float64(sample.InFlight)*2 < limit {
+		return limit
+	}
+	gradient := math.Max(0.5, math.Min(1, tolerance*g.longRTT/rtt))
+	newLimit := limit*gradient + math.Sqrt(limit)
+	return limit*(1-smoothing) + newLimit*smoothing
+}
+
+// Vegas LimitAlgorithm, like TCP Vegas, it estimates the queue size by the
+// minimum latency, increases the limit if the queue size is less than Alpha,
+// and decreases the limit if the queue size is greater than Beta.
+type Vegas struct {
+	// Alpha is 3 by default.
+	Alpha float64
+	// Beta is 6 by default.
+	Beta   float64
+	minRTT time.Duration
+}
+
+// Update implements the LimitAlgorithm interface.
+func (v *Vegas) Update(limit float64, sample Sample) float64 {
+	alpha, beta := v.Alpha, v.Beta
+	if alpha <= 0 {
+		alpha = 3
+	}
+	if beta <= alpha {
+		beta = alpha * 2
+	}
+	if sample.Dropped {
+		return limit - math.Max(1, limit/10)
+	}
+	if sample.RTT <= 0 {
+		return limit
+	}
+	if v.minRTT == 0 || sample.RTT < v.minRTT {
+		v.minRTT = sample.RTT
+	}
+	queue := limit * (1 - float64(v.minRTT)/float64(sample.RTT))
+	switch {
+	case queue < alpha:
+		if float64(sample.InFlight)*2 >= limit {
+			return limit + 1
+		}
+	case queue > beta:
+		return limit - 1
+	}
+	return limit
+}
+
+// AdaptiveLimiter plugin for hprose.
+//
+// AdaptiveLimiter limits the concurrent requests like ConcurrentLimiter,
+// but its limit is adjusted by LimitAlgorithm from the latency and the
+// errors of the requests. The requests exceeding the limit are rejected by
+// core.ErrOverloaded immediately. It can be used by both core.Client and
+// core.Service. The errors returned by the methods are sent in the responses,
+// so IOHandler can't see them, use the AdaptiveLimiter itself as the plugin
+// to add InvokeHandler too, then these errors are treated as failures.
+type AdaptiveLimiter struct {
+	algorithm LimitAlgorithm
+	limit     float64
+	minLimit  int
+	maxLimit  int
+	inFlight  int
+	dropped   func(err error) bool
+	lock      sync.Mutex
+}
+
+// AdaptiveOption for AdaptiveLimiter.
+type AdaptiveOption func(*AdaptiveLimiter)
+
+// WithLimitAlgorithm returns an algorithm Option for AdaptiveLimiter.
+func WithLimitAlgorithm(algorithm LimitAlgorithm) AdaptiveOption {
+	return func(l *AdaptiveLimiter) {
+		l.algorithm = algorithm
+	}
+}
+
+// WithInitialLimit returns an initial limit Option for AdaptiveLimiter.
+func WithInitialLimit(limit int) AdaptiveOption {
+	return func(l *AdaptiveLimiter) {
+		l.limit = float64(limit)
+	}
+}
+
+// WithMinLimit returns a minLimit Option for AdaptiveLimiter.
+func WithMinLimit(minLimit int) AdaptiveOption {
+	return func(l *AdaptiveLimiter) {
+		l.minLimit = minLimit
+	}
+}
+
+// WithMaxLimit returns a maxLimit Option for AdaptiveLimiter.
+func WithMaxLimit(maxLimit int) AdaptiveOption {
+	return func(l *AdaptiveLimiter) {
+		l.maxLimit = maxLimit
+	}
+}
+
+// WithDropped returns an Option for AdaptiveLimiter which decides whether
+// a request failed by its error, any error is a failure by default.
+func WithDropped(dropped func(err error) bool) AdaptiveOption {
+	return func(l *AdaptiveLimiter) {
+		l.dropped = dropped
+	}
+}
+
+// NewAdaptiveLimiter returns an AdaptiveLimiter instance, it uses AIMD with
+// the initial limit 20, the min limit 1 and the max limit 1000 by default.
+func NewAdaptiveLimiter(options ...AdaptiveOption) *AdaptiveLimiter {
+	l := &AdaptiveLimiter{
+		algorithm: &AIMD{},
+		limit:     20,
+		minLimit:  1,
+		maxLimit:  1000,
+		dropped: func(err error) bool {
+			return err != nil
+		},
+	}
+	for _, option := range options {
+		option(l)
+	}
+	l.limit = l.clamp(l.limit)
+	return l
+}
+
+func (l *AdaptiveLimiter) clamp(limit float64) float64 {
+	return math.Max(float64(l.minLimit), math.Min(float64(l.maxLimit), limit))
+}
+
+// Acquire returns core.ErrOverloaded if the in-flight requests reach the
+// limit, otherwise it returns a release function which must be called with
+// the error of the request when the request is completed.
+func (l *AdaptiveLimiter) Acquire() (release func(err error), err error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if float64(l.inFlight) >= math.Floor(l.limit) {
+		return nil, core.ErrOverloaded
+	}
+	l.inFlight++
+	inFlight := l.inFlight
+	start := time.Now()
+	return func(err error) {
+		sample := Sample{
+			RTT:      time.Since(start),
+			InFlight: inFlight,
+			Dropped:  l.dropped(err),
+		}
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		l.inFlight--
+		l.limit = l.clamp(l.algorithm.Update(l.limit, sample))
+	}, nil
+}
+
+const (
+	adaptiveErrorKey   = "limiter.adaptive.error"
+	adaptiveHandoffKey = "limiter.adaptive.handoff"
+)
+
+// adaptiveHandoff passes the releases from IOHandler to InvokeHandler on the
+// client. It is stored in the items before the invocation, so it is shared by
+// the contexts cloned by Hedging and Forking, which may run IOHandler more
+// than once.
+type adaptiveHandoff struct {
+	releases []func(error)
+	done     bool
+	lock     sync.Mutex
+}
+
+// add returns false if the invocation has completed.
+func (h *adaptiveHandoff) add(release func(error)) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.done {
+		return false
+	}
+	h.releases = append(h.releases, release)
+	return true
+}
+
+// complete calls the releases with err, the later releases are not added.
+func (h *adaptiveHandoff) complete(err error) {
+	h.lock.Lock()
+	h.done = true
+	releases := h.releases
+	h.releases = nil
+	h.lock.Unlock()
+	for _, release := range releases {
+		release(err)
+	}
+}
+
+// IOHandler for AdaptiveLimiter.
+func (l *AdaptiveLimiter) IOHandler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
+	release, err := l.Acquire()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if rpcContext, ok := core.FromContext(ctx); ok && err == nil {
+			items := rpcContext.Items()
+			switch rpcContext.(type) {
+			case *core.ServiceContext:
+				if e, ok := items.Get(adaptiveErrorKey); ok {
+					items.Del(adaptiveErrorKey)
+					release(e.(error))
+					return
+				}
+			case *core.ClientContext:
+				if h, ok := items.Get(adaptiveHandoffKey); ok && h.(*adaptiveHandoff).add(release) {
+					return
+				}
+			}
+		}
+		release(err)
+	}()
+	return next(ctx, request)
+}
+
+// InvokeHandler for AdaptiveLimiter, it passes the errors of the methods to
+// IOHandler on the service, and releases the request after the response is
+// decoded on the client.
+func (l *AdaptiveLimiter) InvokeHandler(ctx context.Context, name string, args []interface{}, next core.NextInvokeHandler) (result []interface{}, err error) {
+	rpcContext, ok := core.FromContext(ctx)
+	if !ok {
+		return next(ctx, name, args)
+	}
+	items := rpcContext.Items()
+	if _, ok := rpcContext.(*core.ServiceContext); ok {
+		result, err = next(ctx, name, args)
+		if err != nil {
+			items.Set(adaptiveErrorKey, err)
+		}
+		return
+	}
+	h := &adaptiveHandoff{}
+	items.Set(adaptiveHandoffKey, h)
+	defer func() {
+		items.Del(adaptiveHandoffKey)
+		h.complete(err)
+	}()
+	return next(ctx, name, args)
+}
+
+// Limit returns the current limit of AdaptiveLimiter.
+func (l *AdaptiveLimiter) Limit() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return int(l.limit)
+}
+
+// InFlight returns the number of the in-flight requests of AdaptiveLimiter.
+func (l *AdaptiveLimiter) InFlight() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.inFlight
+}
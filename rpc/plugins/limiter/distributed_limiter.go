@@ -0,0 +1,263 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/limiter/distributed_limiter.go               |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package limiter
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+// casRetries is the max number of the retries when CompareAndSwap of Store
+// fails by the concurrent updates.
+const casRetries = 16
+
+// Rate of DistributedLimiter.
+type Rate struct {
+	// PermitsPerSecond is the number of the permits per second.
+	PermitsPerSecond int64
+	// Burst is the max number of the permits which can be acquired at once.
+	Burst int64
+}
+
+// Algorithm acquires a permit of key from store at now, it returns false if
+// the permit is denied.
+type Algorithm func(ctx context.Context, store Store, key string, now time.Time, rate Rate) (bool, error)
+
+func (r Rate) interval() time.Duration {
+	return time.Duration(float64(time.Second) / float64(r.PermitsPerSecond))
+}
+
+func putInt64(buf []byte, v int64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(v))
+	return append(buf, b[:]...)
+}
+
+func getInt64(buf []byte) int64 {
+	return int64(binary.BigEndian.Uint64(buf))
+}
+
+// TokenBucket Algorithm, the bucket holds at most Burst tokens and is
+// refilled by PermitsPerSecond tokens per second.
+func TokenBucket(ctx context.Context, store Store, key string, now time.Time, rate Rate) (bool, error) {
+	burst := float64(rate.Burst)
+	ttl := time.Duration(burst * float64(rate.interval()))
+	for i := 0; i < casRetries; i++ {
+		old, err := store.Get(ctx, key)
+		if err != nil {
+			return false, err
+		}
+		tokens := burst
+		if len(old) == 16 {
+			tokens = math.Float64frombits(uint64(getInt64(old)))
+			if elapsed := now.Sub(time.Unix(0, getInt64(old[8:]))); elapsed > 0 {
+				tokens = math.Min(burst, tokens+elapsed.Seconds()*float64(rate.PermitsPerSecond))
+			}
+		}
+		if tokens < 1 {
+			return false, nil
+		}
+		state := putInt64(make([]byte, 0, 16), int64(math.Float64bits(tokens-1)))
+		state = putInt64(state, now.UnixNano())
+		if ok, err := store.CompareAndSwap(ctx, key, old, state, ttl); ok || err != nil {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// GCRA (generic cell rate algorithm), it keeps the theoretical arrival time
+// of key, and allows Burst permits ahead of it.
+func GCRA(ctx context.Context, store Store, key string, now time.Time, rate Rate) (bool, error) {
+	interval := rate.interval()
+	tolerance := interval * time.Duration(rate.Burst)
+	for i := 0; i < casRetries; i++ {
+		old, err := store.Get(ctx, key)
+		if err != nil {
+			return false, err
+		}
+		tat := now
+		if len(old) == 8 {
+			if t := time.Unix(0, getInt64(old)); t.After(now) {
+				tat = t
+			}
+		}
+		tat = tat.Add(interval)
+		ttl := tat.Sub(now)
+		if ttl > tolerance {
+			return false, nil
+		}
+		if ok, err := store.CompareAndSwap(ctx, key, old, putInt64(nil, tat.UnixNano()), ttl); ok || err != nil {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// SlidingWindowLog Algorithm, it keeps the log of the acquired permits in
+// the last second, and allows PermitsPerSecond permits in any second. The
+// log has PermitsPerSecond entries at most, so it is suitable for the low
+// rates.
+func SlidingWindowLog(ctx context.Context, store Store, key string, now time.Time, rate Rate) (bool, error) {
+	start := now.Add(-time.Second).UnixNano()
+	for i := 0; i < casRetries; i++ {
+		old, err := store.Get(ctx, key)
+		if err != nil {
+			return false, err
+		}
+		log := make([]byte, 0, len(old)+8)
+		for j := 0; j+8 <= len(old); j += 8 {
+			if getInt64(old[j:]) > start {
+				log = append(log, old[j:j+8]...)
+			}
+		}
+		if int64(len(log)/8) >= rate.PermitsPerSecond {
+			return false, nil
+		}
+		log = putInt64(log, now.UnixNano())
+		if ok, err := store.CompareAndSwap(ctx, key, old, log, time.Second); ok || err != nil {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// SlidingWindowCounter Algorithm, it counts the permits of every second by
+// Increment, and estimates the permits in the last second by the counts of
+// the current and the previous second.
+func SlidingWindowCounter(ctx context.Context, store Store, key string, now time.Time, rate Rate) (bool, error) {
+	window := now.Unix()
+	current := key + ":" + strconv.FormatInt(window, 10)
+	previous := key + ":" + strconv.FormatInt(window-1, 10)
+	count, err := store.Increment(ctx, current, 1, time.Second*2)
+	if err != nil {
+		return false, err
+	}
+	last, err := store.Increment(ctx, previous, 0, time.Second*2)
+	if err != nil {
+		return false, err
+	}
+	weight := 1 - float64(now.Sub(time.Unix(window, 0)))/float64(time.Second)
+	if float64(last)*weight+float64(count) > float64(rate.PermitsPerSecond) {
+		_, err = store.Increment(ctx, current, -1, time.Second*2)
+		return false, err
+	}
+	return true, nil
+}
+
+// DistributedLimiter plugin for hprose.
+//
+// DistributedLimiter keeps its states in a Store shared by the replicas of
+// the service, so the permits per second is the quota of the cluster.
+type DistributedLimiter struct {
+	store     Store
+	algorithm Algorithm
+	rate      Rate
+	key       KeyFunc
+	prefix    string
+}
+
+// DistributedOption for DistributedLimiter.
+type DistributedOption func(*DistributedLimiter)
+
+// WithAlgorithm returns an algorithm Option for DistributedLimiter.
+func WithAlgorithm(algorithm Algorithm) DistributedOption {
+	return func(l *DistributedLimiter) {
+		l.algorithm = algorithm
+	}
+}
+
+// WithBurst returns a burst Option for DistributedLimiter.
+func WithBurst(burst int64) DistributedOption {
+	return func(l *DistributedLimiter) {
+		l.rate.Burst = burst
+	}
+}
+
+// WithDistributedKeyFunc returns a key Option for DistributedLimiter.
+func WithDistributedKeyFunc(key KeyFunc) DistributedOption {
+	return func(l *DistributedLimiter) {
+		l.key = key
+	}
+}
+
+// WithKeyPrefix returns a prefix Option for DistributedLimiter.
+func WithKeyPrefix(prefix string) DistributedOption {
+	return func(l *DistributedLimiter) {
+		l.prefix = prefix
+	}
+}
+
+// NewDistributedLimiter returns a DistributedLimiter instance, it uses
+// TokenBucket with the burst of permitsPerSecond, and all the invocations
+// share one key by default. It panics if permitsPerSecond or the burst is
+// not greater than 0.
+func NewDistributedLimiter(store Store, permitsPerSecond int64, options ...DistributedOption) *DistributedLimiter {
+	if permitsPerSecond <= 0 {
+		panic("limiter: permitsPerSecond must be great than 0")
+	}
+	l := &DistributedLimiter{
+		store:     store,
+		algorithm: TokenBucket,
+		rate:      Rate{permitsPerSecond, permitsPerSecond},
+		prefix:    "hprose:limiter:",
+	}
+	for _, option := range options {
+		option(l)
+	}
+	if l.rate.Burst <= 0 {
+		panic("limiter: burst must be great than 0")
+	}
+	return l
+}
+
+// Acquire a permit of the invocation from the store, it returns
+// core.ErrRateLimited if the permit is denied.
+func (l *DistributedLimiter) Acquire(ctx context.Context, name string) error {
+	key := l.prefix
+	if l.key != nil {
+		key += l.key(ctx, name)
+	}
+	ok, err := l.algorithm(ctx, l.store, key, time.Now(), l.rate)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return core.ErrRateLimited
+	}
+	return nil
+}
+
+// InvokeHandler for DistributedLimiter.
+func (l *DistributedLimiter) InvokeHandler(ctx context.Context, name string, args []interface{}, next core.NextInvokeHandler) (result []interface{}, err error) {
+	if err = l.Acquire(ctx, name); err != nil {
+		return
+	}
+	return next(ctx, name, args)
+}
+
+// PermitsPerSecond property of DistributedLimiter.
+func (l *DistributedLimiter) PermitsPerSecond() int64 {
+	return l.rate.PermitsPerSecond
+}
+
+// Burst property of DistributedLimiter.
+func (l *DistributedLimiter) Burst() int64 {
+	return l.rate.Burst
+}
@@ -0,0 +1,259 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/limiter/keyed_rate_limiter.go                |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package limiter
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+const (
+	// RateLimitOption is the name of the method option which sets the permits
+	// per second of the method for KeyedRateLimiter.
+	RateLimitOption = "rateLimit"
+	// MaxPermitsOption is the name of the method option which sets the max
+	// permits of the method for KeyedRateLimiter.
+	MaxPermitsOption = "maxPermits"
+	// DefaultMaxKeys is the default max number of the keys of KeyedRateLimiter.
+	DefaultMaxKeys = 10000
+)
+
+// KeyFunc returns the key of the invocation for KeyedRateLimiter.
+type KeyFunc func(ctx context.Context, name string) string
+
+// KeyByMethod returns the method name as the key.
+func KeyByMethod(ctx context.Context, name string) string {
+	return name
+}
+
+// KeyByRemoteAddr returns the host of the remote address as the key.
+func KeyByRemoteAddr(ctx context.Context, name string) string {
+	serviceContext := core.GetServiceContext(ctx)
+	if serviceContext == nil || serviceContext.RemoteAddr == nil {
+		return ""
+	}
+	addr := serviceContext.RemoteAddr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// KeyByHeader returns a KeyFunc which returns the request header as the key,
+// for example, the id or the auth header.
+func KeyByHeader(header string) KeyFunc {
+	return func(ctx context.Context, name string) string {
+		serviceContext := core.GetServiceContext(ctx)
+		if serviceContext == nil {
+			return ""
+		}
+		return serviceContext.RequestHeaders().GetString(header)
+	}
+}
+
+// JoinKeys returns a KeyFunc which joins the keys returned by keys.
+func JoinKeys(keys ...KeyFunc) KeyFunc {
+	return func(ctx context.Context, name string) string {
+		parts := make([]string, len(keys))
+		for i, key := range keys {
+			parts[i] = key(ctx, name)
+		}
+		return strings.Join(parts, "\x00")
+	}
+}
+
+type bucket struct {
+	key     string
+	limiter *RateLimiter
+}
+
+// KeyedRateLimiter plugin for hprose.
+//
+// KeyedRateLimiter keeps a token bucket for every key returned by its
+// KeyFunc, so the callers with different keys don't starve each other.
+// The method options RateLimitOption and MaxPermitsOption override the
+// permits per second and the max permits for the method, and the method
+// with RateLimitOption has its own buckets.
+//
+// The invocation is rejected with core.ErrRateLimited at once if no permit is
+// available, unless a timeout is set by WithKeyedTimeout, then it waits for
+// the permit at most timeout.
+//
+// The buckets are stored in a LRU list. When the number of the keys reaches
+// maxKeys, the least recently used bucket is dropped only if it has no permit
+// reserved, so a dropped key starts with a bucket which is not more generous
+// than before. Otherwise the new key is limited by a bucket shared by all the
+// keys which are not stored, so rotating the keys can't skip the limit.
+type KeyedRateLimiter struct {
+	key              KeyFunc
+	permitsPerSecond int64
+	maxPermits       float64
+	timeout          time.Duration
+	maxKeys          int
+	buckets          map[string]*list.Element
+	shared           map[string]*RateLimiter
+	lru              *list.List
+	lock             sync.Mutex
+}
+
+// KeyedOption for KeyedRateLimiter.
+type KeyedOption func(*KeyedRateLimiter)
+
+// WithKeyFunc returns a key Option for KeyedRateLimiter.
+func WithKeyFunc(key KeyFunc) KeyedOption {
+	return func(l *KeyedRateLimiter) {
+		l.key = key
+	}
+}
+
+// WithKeyedMaxPermits returns a maxPermits Option for KeyedRateLimiter.
+func WithKeyedMaxPermits(maxPermits float64) KeyedOption {
+	return func(l *KeyedRateLimiter) {
+		l.maxPermits = maxPermits
+	}
+}
+
+// WithKeyedTimeout returns a timeout Option for KeyedRateLimiter, the
+// invocation waits for the permit at most timeout.
+func WithKeyedTimeout(timeout time.Duration) KeyedOption {
+	return func(l *KeyedRateLimiter) {
+		l.timeout = timeout
+	}
+}
+
+// WithMaxKeys returns a maxKeys Option for KeyedRateLimiter.
+func WithMaxKeys(maxKeys int) KeyedOption {
+	return func(l *KeyedRateLimiter) {
+		l.maxKeys = maxKeys
+	}
+}
+
+// NewKeyedRateLimiter returns a KeyedRateLimiter instance, the key is the
+// host of the remote address by default.
+func NewKeyedRateLimiter(permitsPerSecond int64, options ...KeyedOption) *KeyedRateLimiter {
+	l := &KeyedRateLimiter{
+		key:              KeyByRemoteAddr,
+		permitsPerSecond: permitsPerSecond,
+		maxPermits:       math.Inf(0),
+		timeout:          0,
+		maxKeys:          DefaultMaxKeys,
+		buckets:          make(map[string]*list.Element),
+		shared:           make(map[string]*RateLimiter),
+		lru:              list.New(),
+	}
+	for _, option := range options {
+		option(l)
+	}
+	return l
+}
+
+func (l *KeyedRateLimiter) limiter(key, shared string, permitsPerSecond int64, maxPermits float64) *RateLimiter {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if e, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(e)
+		return e.Value.(*bucket).limiter
+	}
+	if l.maxKeys > 0 && l.lru.Len() >= l.maxKeys {
+		back := l.lru.Back()
+		if !back.Value.(*bucket).limiter.idle(time.Now().UnixNano()) {
+			limiter, ok := l.shared[shared]
+			if !ok {
+				limiter = NewRateLimiter(permitsPerSecond, WithMaxPermits(maxPermits))
+				l.shared[shared] = limiter
+			}
+			return limiter
+		}
+		delete(l.buckets, l.lru.Remove(back).(*bucket).key)
+	}
+	limiter := NewRateLimiter(permitsPerSecond, WithMaxPermits(maxPermits))
+	l.buckets[key] = l.lru.PushFront(&bucket{key, limiter})
+	return limiter
+}
+
+// Acquire a permit from the bucket of the invocation, it returns
+// core.ErrRateLimited if the permit can't be acquired before timeout,
+// and ctx.Err() if ctx is done while waiting.
+func (l *KeyedRateLimiter) Acquire(ctx context.Context, name string) error {
+	key, shared := l.key(ctx, name), ""
+	permitsPerSecond, maxPermits := l.permitsPerSecond, l.maxPermits
+	if serviceContext := core.GetServiceContext(ctx); serviceContext != nil && serviceContext.Method != nil {
+		if options := serviceContext.Method.Options(); options != nil {
+			if _, ok := options.Get(RateLimitOption); ok {
+				permitsPerSecond = options.GetInt64(RateLimitOption)
+				maxPermits = options.GetFloat(MaxPermitsOption, maxPermits)
+				key, shared = name+"\x00"+key, name
+			}
+		}
+	}
+	if permitsPerSecond <= 0 {
+		return nil
+	}
+	delay, ok := l.limiter(key, shared, permitsPerSecond, maxPermits).reserve(1, l.timeout)
+	if !ok {
+		return core.ErrRateLimited
+	}
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// InvokeHandler for KeyedRateLimiter.
+func (l *KeyedRateLimiter) InvokeHandler(ctx context.Context, name string, args []interface{}, next core.NextInvokeHandler) (result []interface{}, err error) {
+	if err = l.Acquire(ctx, name); err != nil {
+		return
+	}
+	return next(ctx, name, args)
+}
+
+// PermitsPerSecond property of KeyedRateLimiter.
+func (l *KeyedRateLimiter) PermitsPerSecond() int64 {
+	return l.permitsPerSecond
+}
+
+// MaxPermits property of KeyedRateLimiter.
+func (l *KeyedRateLimiter) MaxPermits() float64 {
+	return l.maxPermits
+}
+
+// Timeout property of KeyedRateLimiter.
+func (l *KeyedRateLimiter) Timeout() time.Duration {
+	return l.timeout
+}
+
+// MaxKeys property of KeyedRateLimiter.
+func (l *KeyedRateLimiter) MaxKeys() int {
+	return l.maxKeys
+}
+
+// Keys returns the number of the keys in KeyedRateLimiter.
+func (l *KeyedRateLimiter) Keys() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.lru.Len()
+}
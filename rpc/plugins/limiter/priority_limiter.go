@@ -0,0 +1,262 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/limiter/priority_limiter.go                  |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package limiter
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+// PriorityOption is the name of the method option and the request header
+// which sets the priority of the invocation for PriorityLimiter, the request
+// header is only used by HeaderPriority.
+const PriorityOption = "priority"
+
+// PriorityFunc returns the priority of the invocation, the greater the more
+// important.
+type PriorityFunc func(ctx context.Context, name string) int
+
+// DefaultPriority returns the priority in the method options, or 0 if it
+// does not exist. The priority in the request header is ignored, so the
+// clients can not raise the priorities of their invocations.
+func DefaultPriority(ctx context.Context, name string) int {
+	serviceContext := core.GetServiceContext(ctx)
+	if serviceContext == nil {
+		return 0
+	}
+	if serviceContext.Method != nil {
+		if options := serviceContext.Method.Options(); options != nil {
+			return options.GetInt(PriorityOption)
+		}
+	}
+	return 0
+}
+
+// HeaderPriority returns the priority in the request header if it exists and
+// is less than the priority returned by DefaultPriority, otherwise returns the
+// latter. It lets the clients lower the priorities of their invocations, and
+// it is enabled by WithPriorityFunc(HeaderPriority).
+func HeaderPriority(ctx context.Context, name string) int {
+	priority := DefaultPriority(ctx, name)
+	serviceContext := core.GetServiceContext(ctx)
+	if serviceContext == nil {
+		return priority
+	}
+	if _, ok := serviceContext.RequestHeaders().Get(PriorityOption); ok {
+		if p := serviceContext.RequestHeaders().GetInt(PriorityOption); p < priority {
+			return p
+		}
+	}
+	return priority
+}
+
+type waiter struct {
+	priority int
+	result   chan error
+}
+
+// PriorityLimiter plugin for hprose.
+//
+// PriorityLimiter limits the concurrent invocations of the service, the
+// invocations exceeding the limit wait in a bounded queue ordered by their
+// priorities. When the queue is full, the invocation with the lowest
+// priority is rejected by core.ErrOverloaded, so the important invocations,
+// like the health checks, keep working under overload.
+type PriorityLimiter struct {
+	priority              PriorityFunc
+	maxConcurrentRequests int
+	maxQueueLength        int
+	timeout               time.Duration
+	inFlight              int
+	queue                 []*waiter
+	rejected              uint64
+	lock                  sync.Mutex
+}
+
+// PriorityLimiterOption for PriorityLimiter.
+type PriorityLimiterOption func(*PriorityLimiter)
+
+// WithPriorityFunc returns a priority Option for PriorityLimiter.
+func WithPriorityFunc(priority PriorityFunc) PriorityLimiterOption {
+	return func(l *PriorityLimiter) {
+		l.priority = priority
+	}
+}
+
+// WithQueueTimeout returns a timeout Option for PriorityLimiter, the
+// invocations waiting in the queue longer than timeout fail with
+// core.ErrTimeout.
+func WithQueueTimeout(timeout time.Duration) PriorityLimiterOption {
+	return func(l *PriorityLimiter) {
+		l.timeout = timeout
+	}
+}
+
+// NewPriorityLimiter returns a PriorityLimiter instance.
+func NewPriorityLimiter(maxConcurrentRequests int, maxQueueLength int, options ...PriorityLimiterOption) *PriorityLimiter {
+	l := &PriorityLimiter{
+		priority:              DefaultPriority,
+		maxConcurrentRequests: maxConcurrentRequests,
+		maxQueueLength:        maxQueueLength,
+	}
+	for _, option := range options {
+		option(l)
+	}
+	return l
+}
+
+// enqueue inserts w after the waiters with the same or higher priority.
+func (l *PriorityLimiter) enqueue(w *waiter) {
+	i := sort.Search(len(l.queue), func(i int) bool {
+		return l.queue[i].priority < w.priority
+	})
+	l.queue = append(l.queue, nil)
+	copy(l.queue[i+1:], l.queue[i:])
+	l.queue[i] = w
+}
+
+func (l *PriorityLimiter) remove(w *waiter) bool {
+	for i, e := range l.queue {
+		if e == w {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (l *PriorityLimiter) reject() error {
+	atomic.AddUint64(&l.rejected, 1)
+	return core.ErrOverloaded
+}
+
+// Acquire returns immediately if the concurrent requests are less than
+// maxConcurrentRequests, otherwise it waits in the queue until any request
+// is completed. It returns core.ErrOverloaded if the invocation is rejected,
+// core.ErrTimeout if the invocation waits longer than timeout, and ctx.Err()
+// if ctx is done while waiting.
+func (l *PriorityLimiter) Acquire(ctx context.Context, priority int) error {
+	l.lock.Lock()
+	if l.inFlight < l.maxConcurrentRequests {
+		l.inFlight++
+		l.lock.Unlock()
+		return nil
+	}
+	if len(l.queue) >= l.maxQueueLength {
+		n := len(l.queue)
+		if n == 0 || l.queue[n-1].priority >= priority {
+			l.lock.Unlock()
+			return l.reject()
+		}
+		l.queue[n-1].result <- l.reject()
+		l.queue = l.queue[:n-1]
+	}
+	w := &waiter{priority, make(chan error, 1)}
+	l.enqueue(w)
+	l.lock.Unlock()
+	var timeout <-chan time.Time
+	if l.timeout > 0 {
+		timer := time.NewTimer(l.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var err error = core.ErrTimeout
+	select {
+	case err := <-w.result:
+		return err
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+	}
+	l.lock.Lock()
+	removed := l.remove(w)
+	l.lock.Unlock()
+	if removed {
+		return err
+	}
+	return <-w.result
+}
+
+// Release hands over the slot of the completed request to the waiting
+// invocation with the highest priority.
+func (l *PriorityLimiter) Release() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if len(l.queue) > 0 {
+		w := l.queue[0]
+		l.queue = l.queue[1:]
+		w.result <- nil
+		return
+	}
+	l.inFlight--
+}
+
+// InvokeHandler for PriorityLimiter.
+func (l *PriorityLimiter) InvokeHandler(ctx context.Context, name string, args []interface{}, next core.NextInvokeHandler) (result []interface{}, err error) {
+	if err = l.Acquire(ctx, l.priority(ctx, name)); err != nil {
+		return
+	}
+	defer l.Release()
+	return next(ctx, name, args)
+}
+
+// InFlight returns the number of the concurrent requests of PriorityLimiter.
+func (l *PriorityLimiter) InFlight() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.inFlight
+}
+
+// QueueLength returns the number of the waiting invocations of PriorityLimiter.
+func (l *PriorityLimiter) QueueLength() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return len(l.queue)
+}
+
+// QueueLengths returns the numbers of the waiting invocations of every priority.
+func (l *PriorityLimiter) QueueLengths() map[int]int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	lengths := make(map[int]int)
+	for _, w := range l.queue {
+		lengths[w.priority]++
+	}
+	return lengths
+}
+
+// Rejected returns the number of the rejected invocations of PriorityLimiter.
+func (l *PriorityLimiter) Rejected() uint64 {
+	return atomic.LoadUint64(&l.rejected)
+}
+
+// MaxConcurrentRequests property of PriorityLimiter.
+func (l *PriorityLimiter) MaxConcurrentRequests() int {
+	return l.maxConcurrentRequests
+}
+
+// MaxQueueLength property of PriorityLimiter.
+func (l *PriorityLimiter) MaxQueueLength() int {
+	return l.maxQueueLength
+}
+
+// Timeout property of PriorityLimiter.
+func (l *PriorityLimiter) Timeout() time.Duration {
+	return l.timeout
+}
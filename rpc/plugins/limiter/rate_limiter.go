@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/plugins/limiter/rate_limiter.go                      |
 |                                                          |
-| LastModified: Apr 29, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -85,6 +85,35 @@ func (l *RateLimiter) Acquire(ctx context.Context, tokens int) (err error) {
 	return
 }
 
+// reserve tokens if they are available within timeout. It returns the delay
+// before the tokens are available, and false without reserving anything if
+// the delay is longer than timeout.
+func (l *RateLimiter) reserve(tokens int, timeout time.Duration) (time.Duration, bool) {
+	for {
+		now := time.Now().UnixNano()
+		last := atomic.LoadInt64(&l.next)
+		var delay time.Duration
+		if last > now {
+			delay = time.Duration(last - now)
+		}
+		if delay > timeout {
+			return delay, false
+		}
+		permits := float64(now-last)/l.interval - float64(tokens)
+		if permits > l.maxPermits {
+			permits = l.maxPermits
+		}
+		if atomic.CompareAndSwapInt64(&l.next, last, now-int64(permits*l.interval)) {
+			return delay, true
+		}
+	}
+}
+
+// idle returns true if no tokens are reserved beyond now.
+func (l *RateLimiter) idle(now int64) bool {
+	return atomic.LoadInt64(&l.next) <= now
+}
+
 // IOHandler for RateLimiter.
 func (l *RateLimiter) IOHandler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
 	if err = l.Acquire(ctx, len(request)); err != nil {
@@ -0,0 +1,173 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/limiter/store.go                             |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package limiter
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+// Store keeps the states of DistributedLimiter, it is shared by the
+// replicas of the service. The keys of the counters and the values are
+// different, a key should be used only by Increment or by Get and
+// CompareAndSwap.
+type Store interface {
+	// Increment adds delta to the counter of key atomically, and returns the
+	// new value. A new counter starts from 0 and expires after ttl.
+	Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+	// Get returns the value of key, or nil if key doesn't exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// CompareAndSwap sets the value of key to new which expires after ttl
+	// atomically if the value of key is old, nil old means key doesn't exist.
+	CompareAndSwap(ctx context.Context, key string, old, new []byte, ttl time.Duration) (bool, error)
+}
+
+type storeEntry struct {
+	counter int64
+	value   []byte
+	expires time.Time
+}
+
+// MemoryStore is an in-process Store, it can be shared by the replicas
+// through AddStore and ServiceStore.
+type MemoryStore struct {
+	entries map[string]*storeEntry
+	ops     int
+	lock    sync.Mutex
+}
+
+// NewMemoryStore returns a MemoryStore instance.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*storeEntry),
+	}
+}
+
+// entry returns the unexpired entry of key, it sweeps the expired entries
+// every 1024 operations.
+func (s *MemoryStore) entry(key string, now time.Time) *storeEntry {
+	if s.ops++; s.ops >= 1024 {
+		s.ops = 0
+		for k, e := range s.entries {
+			if !now.Before(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+	}
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		return e
+	}
+	return nil
+}
+
+// Increment implements the Store interface.
+func (s *MemoryStore) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	now := time.Now()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e := s.entry(key, now)
+	if e == nil {
+		e = &storeEntry{expires: now.Add(ttl)}
+		s.entries[key] = e
+	}
+	e.counter += delta
+	return e.counter, nil
+}
+
+// Get implements the Store interface.
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	now := time.Now()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if e := s.entry(key, now); e != nil {
+		return e.value, nil
+	}
+	return nil, nil
+}
+
+// CompareAndSwap implements the Store interface.
+func (s *MemoryStore) CompareAndSwap(ctx context.Context, key string, old, new []byte, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e := s.entry(key, now)
+	switch {
+	case e == nil && old != nil:
+		return false, nil
+	case e != nil && (old == nil || !bytes.Equal(e.value, old)):
+		return false, nil
+	}
+	value := make([]byte, len(new))
+	copy(value, new)
+	s.entries[key] = &storeEntry{value: value, expires: now.Add(ttl)}
+	return true, nil
+}
+
+// Len returns the number of the keys in MemoryStore, including the expired
+// keys which are not swept.
+func (s *MemoryStore) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.entries)
+}
+
+// AddStore adds the functions of store to service, so the replicas can
+// share store by ServiceStore over the hprose transports.
+func AddStore(service *core.Service, store Store) *core.Service {
+	return service.
+		AddFunction(store.Increment, "limiter_increment").
+		AddFunction(store.Get, "limiter_get").
+		AddFunction(store.CompareAndSwap, "limiter_cas")
+}
+
+// ServiceStore is a Store which calls the Store added to a service by AddStore.
+type ServiceStore struct {
+	proxy struct {
+		increment      func(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)    `name:"limiter_increment"`
+		get            func(ctx context.Context, key string) ([]byte, error)                                   `name:"limiter_get"`
+		compareAndSwap func(ctx context.Context, key string, old, new []byte, ttl time.Duration) (bool, error) `name:"limiter_cas"`
+	}
+}
+
+// NewServiceStore returns a ServiceStore instance which calls the Store by client.
+func NewServiceStore(client *core.Client) *ServiceStore {
+	s := &ServiceStore{}
+	client.UseService(&s.proxy)
+	return s
+}
+
+// withClientContext binds a new core.ClientContext to ctx, ctx may be bound
+// to a core.ServiceContext when DistributedLimiter is used by a service.
+func withClientContext(ctx context.Context) context.Context {
+	return core.WithContext(ctx, core.NewClientContext())
+}
+
+// Increment implements the Store interface.
+func (s *ServiceStore) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return s.proxy.increment(withClientContext(ctx), key, delta, ttl)
+}
+
+// Get implements the Store interface.
+func (s *ServiceStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.proxy.get(withClientContext(ctx), key)
+}
+
+// CompareAndSwap implements the Store interface.
+func (s *ServiceStore) CompareAndSwap(ctx context.Context, key string, old, new []byte, ttl time.Duration) (bool, error) {
+	return s.proxy.compareAndSwap(withClientContext(ctx), key, old, new, ttl)
+}
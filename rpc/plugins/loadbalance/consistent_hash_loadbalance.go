@@ -0,0 +1,237 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/loadbalance/consistent_hash_loadbalance.go   |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package loadbalance
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+// HashKeyFunc returns the hash key of the invocation for ConsistentHashLoadBalance.
+type HashKeyFunc func(ctx context.Context, name string, args []interface{}) string
+
+// HashKeyByHeader returns a HashKeyFunc which returns the request header as the key.
+func HashKeyByHeader(header string) HashKeyFunc {
+	return func(ctx context.Context, name string, args []interface{}) string {
+		return core.GetClientContext(ctx).RequestHeaders().GetString(header)
+	}
+}
+
+// HashKeyByItem returns a HashKeyFunc which returns the context item as the key.
+func HashKeyByItem(item string) HashKeyFunc {
+	return func(ctx context.Context, name string, args []interface{}) string {
+		return core.GetClientContext(ctx).Items().GetString(item)
+	}
+}
+
+// HashKeyByArg returns a HashKeyFunc which returns the argument at index as the key.
+func HashKeyByArg(index int) HashKeyFunc {
+	return func(ctx context.Context, name string, args []interface{}) string {
+		if index < 0 || index >= len(args) {
+			return ""
+		}
+		return fmt.Sprint(args[index])
+	}
+}
+
+const hashKeyItem = "loadbalance.hashKey"
+
+type virtualNode struct {
+	hash uint64
+	url  *url.URL
+}
+
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	// fnv doesn't mix the last bytes well, so the hash is finalized by the
+	// mixer of splitmix64.
+	x := h.Sum64()
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// ConsistentHashLoadBalance plugin for hprose.
+//
+// ConsistentHashLoadBalance sends the invocations with the same hash key to
+// the same url. The urls are placed on a hash ring with virtual nodes by
+// their strings, so only about 1/n of the keys are moved when a url is added
+// to or removed from Client.URLs. The invocations without the hash key are
+// sent to a random url.
+//
+// If loadFactor is greater than or equal to 1, ConsistentHashLoadBalance
+// bounds the loads, a url can't have more than ceil(loadFactor * average)
+// in-flight invocations, and the invocation is sent to the next url on the
+// ring.
+//
+// Use both IOHandler and InvokeHandler of ConsistentHashLoadBalance, by
+// client.Use(lb).
+type ConsistentHashLoadBalance struct {
+	key          HashKeyFunc
+	virtualNodes int
+	loadFactor   float64
+	urls         []*url.URL
+	ring         []virtualNode
+	actives      map[string]int
+	total        int
+	lock         sync.Mutex
+}
+
+// ConsistentHashOption for ConsistentHashLoadBalance.
+type ConsistentHashOption func(*ConsistentHashLoadBalance)
+
+// WithVirtualNodes returns a virtualNodes Option for ConsistentHashLoadBalance.
+func WithVirtualNodes(virtualNodes int) ConsistentHashOption {
+	return func(lb *ConsistentHashLoadBalance) {
+		lb.virtualNodes = virtualNodes
+	}
+}
+
+// WithLoadFactor returns a loadFactor Option for ConsistentHashLoadBalance.
+func WithLoadFactor(loadFactor float64) ConsistentHashOption {
+	return func(lb *ConsistentHashLoadBalance) {
+		lb.loadFactor = loadFactor
+	}
+}
+
+// NewConsistentHashLoadBalance returns a ConsistentHashLoadBalance instance,
+// it has 160 virtual nodes for every url, and doesn't bound the loads by
+// default.
+func NewConsistentHashLoadBalance(key HashKeyFunc, options ...ConsistentHashOption) *ConsistentHashLoadBalance {
+	lb := &ConsistentHashLoadBalance{
+		key:          key,
+		virtualNodes: 160,
+		actives:      make(map[string]int),
+	}
+	for _, option := range options {
+		option(lb)
+	}
+	return lb
+}
+
+func sameURLs(a, b []*url.URL) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// update rebuilds the ring when urls change.
+func (lb *ConsistentHashLoadBalance) update(urls []*url.URL) {
+	if sameURLs(lb.urls, urls) {
+		return
+	}
+	lb.urls = append(lb.urls[:0:0], urls...)
+	lb.ring = make([]virtualNode, 0, len(urls)*lb.virtualNodes)
+	for _, u := range urls {
+		s := u.String()
+		for i := 0; i < lb.virtualNodes; i++ {
+			lb.ring = append(lb.ring, virtualNode{hash(s + "#" + strconv.Itoa(i)), u})
+		}
+	}
+	sort.Slice(lb.ring, func(i, j int) bool {
+		return lb.ring[i].hash < lb.ring[j].hash
+	})
+}
+
+func (lb *ConsistentHashLoadBalance) capacity() int {
+	if lb.loadFactor < 1 {
+		return math.MaxInt32
+	}
+	return int(math.Ceil(lb.loadFactor * float64(lb.total+1) / float64(len(lb.urls))))
+}
+
+func (lb *ConsistentHashLoadBalance) selectURL(key string, urls []*url.URL) *url.URL {
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+	lb.update(urls)
+	if len(urls) == 0 {
+		return nil
+	}
+	var u *url.URL
+	if key == "" || len(lb.ring) == 0 {
+		u = urls[rand.Intn(len(urls))]
+	} else {
+		h := hash(key)
+		n := len(lb.ring)
+		start := sort.Search(n, func(i int) bool {
+			return lb.ring[i].hash >= h
+		})
+		capacity := lb.capacity()
+		u = lb.ring[start%n].url
+		for i := 0; i < n; i++ {
+			node := lb.ring[(start+i)%n]
+			if lb.actives[node.url.String()] < capacity {
+				u = node.url
+				break
+			}
+		}
+	}
+	lb.actives[u.String()]++
+	lb.total++
+	return u
+}
+
+func (lb *ConsistentHashLoadBalance) release(u *url.URL) {
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+	s := u.String()
+	if lb.actives[s]--; lb.actives[s] <= 0 {
+		delete(lb.actives, s)
+	}
+	lb.total--
+}
+
+// IOHandler for ConsistentHashLoadBalance.
+func (lb *ConsistentHashLoadBalance) IOHandler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
+	clientContext := core.GetClientContext(ctx)
+	u := lb.selectURL(clientContext.Items().GetString(hashKeyItem), clientContext.URLs())
+	if u == nil {
+		return nil, core.ErrNoAvailableURL
+	}
+	clientContext.URL = u
+	defer lb.release(u)
+	return next(ctx, request)
+}
+
+// InvokeHandler for ConsistentHashLoadBalance.
+func (lb *ConsistentHashLoadBalance) InvokeHandler(ctx context.Context, name string, args []interface{}, next core.NextInvokeHandler) (result []interface{}, err error) {
+	core.GetClientContext(ctx).Items().Set(hashKeyItem, lb.key(ctx, name, args))
+	return next(ctx, name, args)
+}
+
+// VirtualNodes property of ConsistentHashLoadBalance.
+func (lb *ConsistentHashLoadBalance) VirtualNodes() int {
+	return lb.virtualNodes
+}
+
+// LoadFactor property of ConsistentHashLoadBalance.
+func (lb *ConsistentHashLoadBalance) LoadFactor() float64 {
+	return lb.loadFactor
+}
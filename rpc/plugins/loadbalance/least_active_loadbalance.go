@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/plugins/loadbalance/least_active_loadbalance.go      |
 |                                                          |
-| LastModified: Aug 24, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -35,7 +35,7 @@ func NewLeastActiveLoadBalance() *LeastActiveLoadBalance {
 // Handler for LeastActiveLoadBalance.
 func (lb *LeastActiveLoadBalance) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
 	clientContext := core.GetClientContext(ctx)
-	urls := clientContext.Client().URLs
+	urls := clientContext.URLs()
 	n := len(urls)
 	leastActiveIndexes := make([]int, 0, n)
 
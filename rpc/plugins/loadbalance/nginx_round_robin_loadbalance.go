@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/plugins/loadbalance/nginx_round_robin_loadbalance.go |
 |                                                          |
-| LastModified: Aug 24, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -17,6 +17,7 @@ import (
 	"context"
 	"math"
 	"math/rand"
+	"net/url"
 	"sync"
 
 	"github.com/hprose/hprose-golang/v3/rpc/core"
@@ -42,10 +43,22 @@ func NewNginxRoundRobinLoadBalance(uris map[string]int) *NginxRoundRobinLoadBala
 	return lb
 }
 
-func (lb *NginxRoundRobinLoadBalance) getIndex() int {
-	n := len(lb.URLs)
+// SetEndpoints replaces the URLs and the weights by endpoints.
+func (lb *NginxRoundRobinLoadBalance) SetEndpoints(endpoints []core.Endpoint) {
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+	olds := lb.update(endpoints)
+	lb.effectiveWeights = lb.inherit(lb.effectiveWeights, olds, true)
+	lb.currentWeights = lb.inherit(lb.currentWeights, olds, false)
+}
+
+func (lb *NginxRoundRobinLoadBalance) getIndex() (int, *url.URL) {
 	lb.lock.Lock()
 	defer lb.lock.Unlock()
+	n := len(lb.URLs)
+	if n == 0 {
+		return -1, nil
+	}
 	totalWeight := lb.effectiveWeights.Sum()
 	if totalWeight > 0 {
 		var index int
@@ -59,20 +72,28 @@ func (lb *NginxRoundRobinLoadBalance) getIndex() int {
 			}
 		}
 		lb.currentWeights[index] = currentWeight - totalWeight
-		return index
+		return index, lb.URLs[index]
 	}
-	return rand.Intn(n)
+	index := rand.Intn(n)
+	return index, lb.URLs[index]
 }
 
 // Handler for NginxRoundRobinLoadBalance.
 func (lb *NginxRoundRobinLoadBalance) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
-	index := lb.getIndex()
-	core.GetClientContext(ctx).URL = lb.URLs[index]
+	index, u := lb.getIndex()
+	if u == nil {
+		return next(ctx, request)
+	}
+	core.GetClientContext(ctx).URL = u
 	defer func() {
 		if e := recover(); e != nil {
 			err = core.NewPanicError(e)
 		}
 		lb.lock.Lock()
+		if index = lb.find(index, u); index < 0 {
+			lb.lock.Unlock()
+			return
+		}
 		if err == nil {
 			if lb.effectiveWeights[index] < lb.Weights[index] {
 				lb.effectiveWeights[index]++
@@ -0,0 +1,198 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/loadbalance/peak_ewma_loadbalance.go         |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package loadbalance
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+type endpointStats struct {
+	ewma    float64 // nanoseconds
+	pending int
+	updated time.Time
+}
+
+// decayed returns the ewma decayed to now.
+func (s *endpointStats) decayed(now time.Time, decayTime time.Duration) float64 {
+	elapsed := now.Sub(s.updated)
+	if elapsed <= 0 {
+		return s.ewma
+	}
+	// less than a nanosecond means fully decayed.
+	if ewma := s.ewma * math.Exp(-float64(elapsed)/float64(decayTime)); ewma >= 1 {
+		return ewma
+	}
+	return 0
+}
+
+// PeakEWMALoadBalance plugin for hprose.
+//
+// PeakEWMALoadBalance picks two urls randomly (power of two choices), and
+// sends the request to the one with the lower score. The score of a url is
+// the peak EWMA (exponentially weighted moving average) of its response
+// time multiplied by its in-flight requests plus one. The peak EWMA jumps to
+// a response time higher than it, and decays to 0 over decayTime when the
+// url has no response, so an idle url is tried again. A failed request is
+// counted as a response time of penalty, and so is a url which has in-flight
+// requests but no response time, that is a new or fully decayed one.
+type PeakEWMALoadBalance struct {
+	decayTime time.Duration
+	penalty   time.Duration
+	stats     map[string]*endpointStats
+	lock      sync.Mutex
+}
+
+// PeakEWMAOption for PeakEWMALoadBalance.
+type PeakEWMAOption func(*PeakEWMALoadBalance)
+
+// WithDecayTime returns a decayTime Option for PeakEWMALoadBalance.
+func WithDecayTime(decayTime time.Duration) PeakEWMAOption {
+	return func(lb *PeakEWMALoadBalance) {
+		lb.decayTime = decayTime
+	}
+}
+
+// WithPenalty returns a penalty Option for PeakEWMALoadBalance.
+func WithPenalty(penalty time.Duration) PeakEWMAOption {
+	return func(lb *PeakEWMALoadBalance) {
+		lb.penalty = penalty
+	}
+}
+
+// NewPeakEWMALoadBalance returns a PeakEWMALoadBalance instance, its
+// decayTime is 10 seconds and penalty is 5 seconds by default.
+func NewPeakEWMALoadBalance(options ...PeakEWMAOption) *PeakEWMALoadBalance {
+	lb := &PeakEWMALoadBalance{
+		decayTime: time.Second * 10,
+		penalty:   time.Second * 5,
+		stats:     make(map[string]*endpointStats),
+	}
+	for _, option := range options {
+		option(lb)
+	}
+	return lb
+}
+
+func (lb *PeakEWMALoadBalance) endpoint(key string, now time.Time) *endpointStats {
+	s, ok := lb.stats[key]
+	if !ok {
+		s = &endpointStats{updated: now}
+		lb.stats[key] = s
+	}
+	return s
+}
+
+func (lb *PeakEWMALoadBalance) score(key string, now time.Time) float64 {
+	s := lb.endpoint(key, now)
+	ewma := s.decayed(now, lb.decayTime)
+	if ewma == 0 && s.pending > 0 {
+		ewma = float64(lb.penalty)
+	}
+	return ewma * float64(s.pending+1)
+}
+
+// prune removes the stats of the urls which are not in urls.
+func (lb *PeakEWMALoadBalance) prune(urls []*url.URL) {
+	if len(lb.stats) <= len(urls) {
+		return
+	}
+	keys := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		keys[u.String()] = true
+	}
+	for key, s := range lb.stats {
+		if !keys[key] && s.pending == 0 {
+			delete(lb.stats, key)
+		}
+	}
+}
+
+func (lb *PeakEWMALoadBalance) observe(key string, rtt time.Duration, failed bool) {
+	if failed && rtt < lb.penalty {
+		rtt = lb.penalty
+	}
+	now := time.Now()
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+	s := lb.endpoint(key, now)
+	s.pending--
+	if ewma := s.decayed(now, lb.decayTime); float64(rtt) > ewma {
+		s.ewma = float64(rtt)
+	} else {
+		w := math.Exp(-float64(now.Sub(s.updated)) / float64(lb.decayTime))
+		s.ewma = s.ewma*w + float64(rtt)*(1-w)
+	}
+	s.updated = now
+}
+
+// Handler for PeakEWMALoadBalance.
+func (lb *PeakEWMALoadBalance) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
+	clientContext := core.GetClientContext(ctx)
+	urls := clientContext.URLs()
+	if len(urls) == 0 {
+		return nil, core.ErrNoAvailableURL
+	}
+	index := 0
+	now := time.Now()
+	lb.lock.Lock()
+	lb.prune(urls)
+	if n := len(urls); n > 1 {
+		i := rand.Intn(n)
+		j := rand.Intn(n - 1)
+		if j >= i {
+			j++
+		}
+		index = i
+		if lb.score(urls[j].String(), now) < lb.score(urls[i].String(), now) {
+			index = j
+		}
+	}
+	key := urls[index].String()
+	lb.endpoint(key, now).pending++
+	lb.lock.Unlock()
+	clientContext.URL = urls[index]
+	defer func() {
+		lb.observe(key, time.Since(now), err != nil)
+	}()
+	return next(ctx, request)
+}
+
+// Scores returns the current scores of the urls, the lower the better.
+func (lb *PeakEWMALoadBalance) Scores() map[string]float64 {
+	now := time.Now()
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+	scores := make(map[string]float64, len(lb.stats))
+	for key := range lb.stats {
+		scores[key] = lb.score(key, now)
+	}
+	return scores
+}
+
+// DecayTime property of PeakEWMALoadBalance.
+func (lb *PeakEWMALoadBalance) DecayTime() time.Duration {
+	return lb.decayTime
+}
+
+// Penalty property of PeakEWMALoadBalance.
+func (lb *PeakEWMALoadBalance) Penalty() time.Duration {
+	return lb.penalty
+}
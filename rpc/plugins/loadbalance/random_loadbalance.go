@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/plugins/loadbalance/random_loadbalance.go            |
 |                                                          |
-| LastModified: Mar 12, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -32,7 +32,7 @@ func NewRandomLoadBalance() *RandomLoadBalance {
 // Handler for RandomLoadBalance.
 func (lb *RandomLoadBalance) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
 	clientContext := core.GetClientContext(ctx)
-	urls := clientContext.Client().URLs
+	urls := clientContext.URLs()
 	clientContext.URL = urls[rand.Intn(len(urls))]
 	return next(ctx, request)
 }
@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/plugins/loadbalance/round_robin_loadbalance.go       |
 |                                                          |
-| LastModified: Mar 12, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -43,7 +43,7 @@ func (lb *RoundRobinLoadBalance) getIndex(n int64) int64 {
 // Handler for RoundRobinLoadBalance.
 func (lb *RoundRobinLoadBalance) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
 	clientContext := core.GetClientContext(ctx)
-	urls := clientContext.Client().URLs
+	urls := clientContext.URLs()
 	clientContext.URL = urls[lb.getIndex(int64(len(urls)))]
 	return next(ctx, request)
 }
@@ -0,0 +1,69 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/loadbalance/skip_unavailable.go              |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package loadbalance
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+// Available returns whether the endpoint u is available for the call.
+type Available func(ctx context.Context, u *url.URL) bool
+
+// LoadBalance is the load balance plugin which selects the url in Handler.
+type LoadBalance interface {
+	Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error)
+}
+
+// SkipUnavailableLoadBalance plugin for hprose.
+type SkipUnavailableLoadBalance struct {
+	lb        LoadBalance
+	available Available
+}
+
+// SkipUnavailable returns a load balance plugin which selects the url by lb
+// again while the selected url is unavailable, at most len(URLs) times. It
+// passes the endpoints to lb if lb is a core.EndpointsListener. For example:
+//
+//	breaker := circuitbreaker.NewBreaker()
+//	lb := loadbalance.NewRoundRobinLoadBalance()
+//	client.Use(loadbalance.SkipUnavailable(lb, breaker.Available), breaker)
+func SkipUnavailable(lb LoadBalance, available Available) *SkipUnavailableLoadBalance {
+	return &SkipUnavailableLoadBalance{lb, available}
+}
+
+// SetEndpoints passes the endpoints to the wrapped load balance if it is a
+// core.EndpointsListener.
+func (s *SkipUnavailableLoadBalance) SetEndpoints(endpoints []core.Endpoint) {
+	if listener, ok := s.lb.(core.EndpointsListener); ok {
+		listener.SetEndpoints(endpoints)
+	}
+}
+
+// Handler for SkipUnavailableLoadBalance.
+func (s *SkipUnavailableLoadBalance) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
+	clientContext := core.GetClientContext(ctx)
+	n := len(clientContext.URLs())
+	selections := 0
+	var selected core.NextIOHandler
+	selected = func(ctx context.Context, request []byte) (response []byte, err error) {
+		if selections++; selections < n && !s.available(ctx, clientContext.URL) {
+			return s.lb.Handler(ctx, request, selected)
+		}
+		return next(ctx, request)
+	}
+	return s.lb.Handler(ctx, request, selected)
+}
@@ -6,7 +6,7 @@
 |                                                              |
 | rpc/plugins/loadbalance/weighted_least_active_loadbalance.go |
 |                                                              |
-| LastModified: Aug 24, 2021                                   |
+| LastModified: Oct 19, 2026                                   |
 | Author: Ma Bingyao <andot@hprose.com>                        |
 |                                                              |
 \*____________________________________________________________*/
@@ -15,8 +15,8 @@ package loadbalance
 
 import (
 	"context"
-	"fmt"
 	"math/rand"
+	"net/url"
 	"sync"
 
 	"github.com/hprose/hprose-golang/v3/rpc/core"
@@ -42,11 +42,23 @@ func NewWeightedLeastActiveLoadBalance(uris map[string]int) *WeightedLeastActive
 	return lb
 }
 
-func (lb *WeightedLeastActiveLoadBalance) getIndex() int {
-	n := len(lb.URLs)
-	leastActiveIndexes := make([]int, 0, n)
+// SetEndpoints replaces the URLs and the weights by endpoints.
+func (lb *WeightedLeastActiveLoadBalance) SetEndpoints(endpoints []core.Endpoint) {
+	lb.rwlock.Lock()
+	defer lb.rwlock.Unlock()
+	olds := lb.update(endpoints)
+	lb.actives = lb.inherit(lb.actives, olds, false)
+	lb.effectiveWeights = lb.inherit(lb.effectiveWeights, olds, true)
+}
 
+func (lb *WeightedLeastActiveLoadBalance) getIndex() (int, *url.URL) {
 	lb.rwlock.RLock()
+	defer lb.rwlock.RUnlock()
+	n := len(lb.URLs)
+	if n == 0 {
+		return -1, nil
+	}
+	leastActiveIndexes := make([]int, 0, n)
 	leastActive := lb.actives.Min()
 	var totalWeight int64
 	for i := 0; i < n; i++ {
@@ -55,18 +67,17 @@ func (lb *WeightedLeastActiveLoadBalance) getIndex() int {
 			totalWeight += lb.effectiveWeights[i]
 		}
 	}
-	lb.rwlock.RUnlock()
 
 	index := leastActiveIndexes[0]
 	count := len(leastActiveIndexes)
 	if count <= 1 {
-		return index
+		return index, lb.URLs[index]
 	}
 	if totalWeight <= 0 {
-		return leastActiveIndexes[rand.Intn(count)]
+		index = leastActiveIndexes[rand.Intn(count)]
+		return index, lb.URLs[index]
 	}
 	currentWeight := rand.Int63n(totalWeight)
-	lb.rwlock.RLock()
 	for i := 0; i < count; i++ {
 		currentWeight -= lb.effectiveWeights[leastActiveIndexes[i]]
 		if currentWeight < 0 {
@@ -74,17 +85,20 @@ func (lb *WeightedLeastActiveLoadBalance) getIndex() int {
 			break
 		}
 	}
-	lb.rwlock.RUnlock()
-	return index
+	return index, lb.URLs[index]
 }
 
 // Handler for WeightedLeastActiveLoadBalance.
 func (lb *WeightedLeastActiveLoadBalance) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
-	index := lb.getIndex()
-	core.GetClientContext(ctx).URL = lb.URLs[index]
-	fmt.Println(lb.URLs[index])
+	index, u := lb.getIndex()
+	if u == nil {
+		return next(ctx, request)
+	}
+	core.GetClientContext(ctx).URL = u
 	lb.rwlock.Lock()
-	lb.actives[index]++
+	if index = lb.find(index, u); index >= 0 {
+		lb.actives[index]++
+	}
 	lb.rwlock.Unlock()
 
 	defer func() {
@@ -92,6 +106,10 @@ func (lb *WeightedLeastActiveLoadBalance) Handler(ctx context.Context, request [
 			err = core.NewPanicError(e)
 		}
 		lb.rwlock.Lock()
+		if index = lb.find(index, u); index < 0 {
+			lb.rwlock.Unlock()
+			return
+		}
 		lb.actives[index]--
 		if err == nil {
 			if lb.effectiveWeights[index] < lb.Weights[index] {
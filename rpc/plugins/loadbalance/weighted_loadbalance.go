@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/plugins/loadbalance/weighted_loadbalance.go          |
 |                                                          |
-| LastModified: Aug 24, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -15,6 +15,8 @@ package loadbalance
 
 import (
 	"net/url"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
 )
 
 // WeightedLoadBalance plugin for hprose.
@@ -39,3 +41,51 @@ func MakeWeightedLoadBalance(uris map[string]int) (lb WeightedLoadBalance) {
 	}
 	return
 }
+
+func (lb *WeightedLoadBalance) update(endpoints []core.Endpoint) (olds []int) {
+	indexes := make(map[string]int, len(lb.URLs))
+	for i, u := range lb.URLs {
+		indexes[u.String()] = i
+	}
+	n := len(endpoints)
+	urls := make([]*url.URL, n)
+	weights := make(int64Slice, n)
+	olds = make([]int, n)
+	for i, endpoint := range endpoints {
+		urls[i], olds[i] = endpoint.URL, -1
+		if j, ok := indexes[endpoint.URL.String()]; ok {
+			urls[i], olds[i] = lb.URLs[j], j
+		}
+		weights[i] = int64(endpoint.Weight)
+		if weights[i] <= 0 {
+			weights[i] = 1
+		}
+	}
+	lb.URLs, lb.Weights = urls, weights
+	return
+}
+
+func (lb *WeightedLoadBalance) find(index int, u *url.URL) int {
+	if index >= 0 && index < len(lb.URLs) && lb.URLs[index] == u {
+		return index
+	}
+	for i, v := range lb.URLs {
+		if v == u {
+			return i
+		}
+	}
+	return -1
+}
+
+func (lb *WeightedLoadBalance) inherit(values int64Slice, olds []int, limit bool) int64Slice {
+	result := make(int64Slice, len(olds))
+	for i, j := range olds {
+		switch {
+		case j >= 0 && (!limit || values[j] < lb.Weights[i]):
+			result[i] = values[j]
+		case limit:
+			result[i] = lb.Weights[i]
+		}
+	}
+	return result
+}
@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/plugins/loadbalance/weighted_random_loadbalance.go   |
 |                                                          |
-| LastModified: Aug 24, 2021                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -16,6 +16,7 @@ package loadbalance
 import (
 	"context"
 	"math/rand"
+	"net/url"
 	"sync"
 
 	"github.com/hprose/hprose-golang/v3/rpc/core"
@@ -38,14 +39,26 @@ func NewWeightedRandomLoadBalance(uris map[string]int) *WeightedRandomLoadBalanc
 	return lb
 }
 
-func (lb *WeightedRandomLoadBalance) getIndex() int {
-	n := len(lb.URLs)
-	index := n - 1
+// SetEndpoints replaces the URLs and the weights by endpoints.
+func (lb *WeightedRandomLoadBalance) SetEndpoints(endpoints []core.Endpoint) {
+	lb.rwlock.Lock()
+	defer lb.rwlock.Unlock()
+	olds := lb.update(endpoints)
+	lb.effectiveWeights = lb.inherit(lb.effectiveWeights, olds, true)
+}
+
+func (lb *WeightedRandomLoadBalance) getIndex() (int, *url.URL) {
 	lb.rwlock.RLock()
 	defer lb.rwlock.RUnlock()
+	n := len(lb.URLs)
+	if n == 0 {
+		return -1, nil
+	}
+	index := n - 1
 	totalWeight := lb.effectiveWeights.Sum()
 	if totalWeight <= 0 {
-		return rand.Intn(n)
+		index = rand.Intn(n)
+		return index, lb.URLs[index]
 	}
 	currentWeight := rand.Int63n(totalWeight)
 	for i := 0; i < n; i++ {
@@ -55,18 +68,25 @@ func (lb *WeightedRandomLoadBalance) getIndex() int {
 			break
 		}
 	}
-	return index
+	return index, lb.URLs[index]
 }
 
 // Handler for WeightedRandomLoadBalance.
 func (lb *WeightedRandomLoadBalance) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
-	index := lb.getIndex()
-	core.GetClientContext(ctx).URL = lb.URLs[index]
+	index, u := lb.getIndex()
+	if u == nil {
+		return next(ctx, request)
+	}
+	core.GetClientContext(ctx).URL = u
 	defer func() {
 		if e := recover(); e != nil {
 			err = core.NewPanicError(e)
 		}
 		lb.rwlock.Lock()
+		if index = lb.find(index, u); index < 0 {
+			lb.rwlock.Unlock()
+			return
+		}
 		if err == nil {
 			if lb.effectiveWeights[index] < lb.Weights[index] {
 				lb.effectiveWeights[index]++
@@ -6,7 +6,7 @@
 |                                                             |
 | rpc/plugins/loadbalance/weighted_round_robin_loadbalance.go |
 |                                                             |
-| LastModified: Aug 24, 2021                                  |
+| LastModified: Oct 19, 2026                                  |
 | Author: Ma Bingyao <andot@hprose.com>                       |
 |                                                             |
 \*___________________________________________________________*/
@@ -15,6 +15,7 @@ package loadbalance
 
 import (
 	"context"
+	"net/url"
 	"sync"
 
 	"github.com/hprose/hprose-golang/v3/rpc/core"
@@ -42,10 +43,24 @@ func NewWeightedRoundRobinLoadBalance(uris map[string]int) *WeightedRoundRobinLo
 	return lb
 }
 
-func (lb *WeightedRoundRobinLoadBalance) getIndex() int {
-	n := len(lb.URLs)
+// SetEndpoints replaces the URLs and the weights by endpoints.
+func (lb *WeightedRoundRobinLoadBalance) SetEndpoints(endpoints []core.Endpoint) {
+	lb.lock.Lock()
+	defer lb.lock.Unlock()
+	lb.update(endpoints)
+	lb.index = -1
+	lb.currentWeight = 0
+	lb.maxWeight = lb.Weights.Max()
+	lb.gcdWeight = lb.Weights.GCD()
+}
+
+func (lb *WeightedRoundRobinLoadBalance) getURL() *url.URL {
 	lb.lock.Lock()
 	defer lb.lock.Unlock()
+	n := len(lb.URLs)
+	if n == 0 {
+		return nil
+	}
 	for {
 		lb.index = (lb.index + 1) % n
 		if lb.index == 0 {
@@ -55,14 +70,15 @@ func (lb *WeightedRoundRobinLoadBalance) getIndex() int {
 			}
 		}
 		if lb.Weights[lb.index] >= lb.currentWeight {
-			return lb.index
+			return lb.URLs[lb.index]
 		}
 	}
 }
 
 // Handler for WeightedRoundRobinLoadBalance.
 func (lb *WeightedRoundRobinLoadBalance) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
-	index := lb.getIndex()
-	core.GetClientContext(ctx).URL = lb.URLs[index]
+	if u := lb.getURL(); u != nil {
+		core.GetClientContext(ctx).URL = u
+	}
 	return next(ctx, request)
 }
@@ -6,7 +6,7 @@
 |                                                          |
 | rpc/plugins/log/log.go                                   |
 |                                                          |
-| LastModified: Mar 18, 2022                               |
+| LastModified: Oct 19, 2026                               |
 | Author: Ma Bingyao <andot@hprose.com>                    |
 |                                                          |
 \*________________________________________________________*/
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"unsafe"
 
+	"github.com/hprose/hprose-golang/v3/io"
 	"github.com/hprose/hprose-golang/v3/rpc/core"
 	jsoniter "github.com/json-iterator/go"
 )
@@ -26,6 +27,12 @@ import (
 type Log struct {
 	Println func(v ...interface{})
 	Enabled bool
+	// Pretty prints the request and response by io.Format instead of the raw bytes.
+	Pretty bool
+	// Redact replaces the values of the struct fields with the redact option
+	// by io.Redacted in the logs, it is true by default. The other values
+	// are logged as they are without Redact.
+	Redact bool
 }
 
 // New returns a Log instance.
@@ -37,6 +44,7 @@ func New(f ...func(v ...interface{})) *Log {
 	return &Log{
 		Println: p,
 		Enabled: true,
+		Redact:  true,
 	}
 }
 
@@ -44,6 +52,63 @@ func unsafeString(bytes []byte) string {
 	return *(*string)(unsafe.Pointer(&bytes))
 }
 
+func (log *Log) format(data []byte) string {
+	if log.Redact {
+		redacted, err := io.Redact(data)
+		if err != nil {
+			return fmt.Sprintf("<%d bytes>", len(data))
+		}
+		data = redacted
+	}
+	if log.Pretty {
+		if s, err := io.Format(data); err == nil {
+			return "\n" + s
+		}
+	}
+	return unsafeString(data)
+}
+
+type redactExtension struct {
+	jsoniter.DummyExtension
+}
+
+func (*redactExtension) UpdateStructDescriptor(structDescriptor *jsoniter.StructDescriptor) {
+	for _, binding := range structDescriptor.Fields {
+		if io.HasRedactOption(binding.Field.Tag()) {
+			binding.Encoder = redactedEncoder{}
+		}
+	}
+}
+
+type redactedEncoder struct{}
+
+func (redactedEncoder) IsEmpty(ptr unsafe.Pointer) bool {
+	return false
+}
+
+func (redactedEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
+	stream.WriteString(io.Redacted)
+}
+
+// redactJSON is the same as jsoniter.ConfigDefault except that the fields
+// with the redact option are written as io.Redacted.
+var redactJSON = func() jsoniter.API {
+	api := jsoniter.Config{EscapeHTML: true}.Froze()
+	api.RegisterExtension(&redactExtension{})
+	return api
+}()
+
+func (log *Log) marshal(v interface{}) string {
+	api := jsoniter.ConfigDefault
+	if log.Redact {
+		api = redactJSON
+	}
+	if data, err := api.Marshal(v); err == nil {
+		return unsafeString(data)
+	}
+	return fmt.Sprint(v)
+}
+
 func (log *Log) isEnabled(ctx context.Context) (enabled bool) {
 	enabled = log.Enabled
 	if context, ok := core.FromContext(ctx); ok {
@@ -64,10 +129,10 @@ func (log *Log) IOHandler(ctx context.Context, request []byte, next core.NextIOH
 		if err != nil {
 			log.Println("error:", err)
 		} else {
-			log.Println("response:", unsafeString(response))
+			log.Println("response:", log.format(response))
 		}
 	}()
-	log.Println("request:", unsafeString(request))
+	log.Println("request:", log.format(request))
 	return next(ctx, request)
 }
 
@@ -82,18 +147,12 @@ func (log *Log) InvokeHandler(ctx context.Context, name string, args []interface
 		}
 		if err != nil {
 			log.Println("error:", err)
-		} else if data, e := jsoniter.Marshal(result); e == nil {
-			log.Println("result:", unsafeString(data))
 		} else {
-			log.Println("result:", result)
+			log.Println("result:", log.marshal(result))
 		}
 	}()
 	log.Println("name:", name)
-	if data, e := jsoniter.Marshal(args); e == nil {
-		log.Println("args:", unsafeString(data))
-	} else {
-		log.Println("args:", args)
-	}
+	log.Println("args:", log.marshal(args))
 	return next(ctx, name, args)
 }
 
@@ -0,0 +1,411 @@
+/*--------------------------------------------------------*\
+|                                                          |
+|                          hprose                          |
+|                                                          |
+| Official WebSite: https://hprose.com                     |
+|                                                          |
+| rpc/plugins/outlier/outlier.go                           |
+|                                                          |
+| LastModified: Oct 19, 2026                               |
+| Author: Ma Bingyao <andot@hprose.com>                    |
+|                                                          |
+\*________________________________________________________*/
+
+package outlier
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hprose/hprose-golang/v3/rpc/core"
+)
+
+// minHosts is the minimum number of endpoints with enough requests to detect
+// the error rate anomalies.
+const minHosts = 3
+
+// Probe checks the health of the endpoint u, it returns nil if u is healthy.
+type Probe func(ctx context.Context, u *url.URL) error
+
+// MethodProbe returns a Probe which calls method on the endpoint by the
+// transport of client without plugins, the method list function ~ is called
+// if method is empty.
+func MethodProbe(client *core.Client, method string) Probe {
+	if method == "" {
+		method = "~"
+	}
+	return func(ctx context.Context, u *url.URL) error {
+		clientContext := core.NewClientContext()
+		clientContext.Init(client)
+		clientContext.URL = u
+		if deadline, ok := ctx.Deadline(); ok {
+			clientContext.Timeout = time.Until(deadline)
+		}
+		ctx = core.WithContext(ctx, clientContext)
+		request, err := client.Codec.Encode(method, nil, clientContext)
+		if err != nil {
+			return err
+		}
+		response, err := client.Transport(ctx, request)
+		if err != nil {
+			return err
+		}
+		_, err = client.Codec.Decode(response, clientContext)
+		return err
+	}
+}
+
+type endpoint struct {
+	url         *url.URL
+	consecutive int
+	requests    int
+	failures    int
+	ejected     bool
+	ejections   int
+	until       time.Time
+	generation  uint64
+}
+
+type change struct {
+	key     string
+	ejected bool
+}
+
+// Detector plugin for hprose.
+//
+// Detector ejects the endpoints which fail consecutiveFailures times in a
+// row, or whose error rate over an interval is higher than the mean error
+// rate of the other endpoints by errorRateFactor standard deviations. An
+// endpoint is ejected for baseEjectionTime multiplied by the times it has
+// been ejected, at most maxEjectionTime, the multiplier is decreased for
+// every interval the endpoint is not ejected. At most maxEjectionRatio of
+// the endpoints are ejected at the same time, but one of them can always be
+// ejected if there are two or more, the only endpoint is never ejected.
+//
+// If a probe is set, the ejected endpoints are probed every probeInterval,
+// and are restored as soon as the probe succeeds.
+//
+// Detector only records the results, use Detector.Available with
+// loadbalance.SkipUnavailable or cluster.WithAvailable to skip the ejected
+// endpoints. For example:
+//
+//	detector := outlier.NewDetector(outlier.WithProbe(outlier.MethodProbe(client, ""), time.Second))
+//	lb := loadbalance.NewRoundRobinLoadBalance()
+//	client.Use(loadbalance.SkipUnavailable(lb, detector.Available), detector)
+type Detector struct {
+	consecutiveFailures int
+	errorRateFactor     float64
+	minRequests         int
+	interval            time.Duration
+	baseEjectionTime    time.Duration
+	maxEjectionTime     time.Duration
+	maxEjectionRatio    float64
+	probe               Probe
+	probeInterval       time.Duration
+	onEjectionChange    func(u string, ejected bool)
+	endpoints           map[string]*endpoint
+	total               int
+	analyzedAt          time.Time
+	lock                sync.Mutex
+}
+
+// Option for Detector.
+type Option func(*Detector)
+
+// WithConsecutiveFailures returns a consecutiveFailures Option for Detector,
+// 0 disables the consecutive failures detection.
+func WithConsecutiveFailures(consecutiveFailures int) Option {
+	return func(d *Detector) {
+		d.consecutiveFailures = consecutiveFailures
+	}
+}
+
+// WithErrorRate returns an Option for Detector that enables the error rate
+// anomaly detection. Only the endpoints with at least minRequests requests in
+// an interval are compared.
+func WithErrorRate(errorRateFactor float64, minRequests int) Option {
+	return func(d *Detector) {
+		d.errorRateFactor = errorRateFactor
+		d.minRequests = minRequests
+	}
+}
+
+// WithInterval returns an interval Option for Detector.
+func WithInterval(interval time.Duration) Option {
+	return func(d *Detector) {
+		d.interval = interval
+	}
+}
+
+// WithEjectionTime returns an Option for Detector that sets baseEjectionTime
+// and maxEjectionTime.
+func WithEjectionTime(baseEjectionTime, maxEjectionTime time.Duration) Option {
+	return func(d *Detector) {
+		d.baseEjectionTime = baseEjectionTime
+		d.maxEjectionTime = maxEjectionTime
+	}
+}
+
+// WithMaxEjectionRatio returns a maxEjectionRatio Option for Detector.
+func WithMaxEjectionRatio(maxEjectionRatio float64) Option {
+	return func(d *Detector) {
+		d.maxEjectionRatio = maxEjectionRatio
+	}
+}
+
+// WithProbe returns an Option for Detector that enables the active health
+// probes.
+func WithProbe(probe Probe, probeInterval time.Duration) Option {
+	return func(d *Detector) {
+		d.probe = probe
+		d.probeInterval = probeInterval
+	}
+}
+
+// WithEjectionChange returns an Option for Detector that sets the function
+// called when an endpoint is ejected or restored.
+func WithEjectionChange(onEjectionChange func(u string, ejected bool)) Option {
+	return func(d *Detector) {
+		d.onEjectionChange = onEjectionChange
+	}
+}
+
+// NewDetector returns a Detector instance.
+func NewDetector(options ...Option) *Detector {
+	d := &Detector{
+		consecutiveFailures: 5,
+		errorRateFactor:     0,
+		minRequests:         0,
+		interval:            time.Second * 10,
+		baseEjectionTime:    time.Second * 30,
+		maxEjectionTime:     time.Second * 300,
+		maxEjectionRatio:    0.1,
+		endpoints:           make(map[string]*endpoint),
+		analyzedAt:          time.Now(),
+	}
+	for _, option := range options {
+		option(d)
+	}
+	return d
+}
+
+func (d *Detector) report(changes []change) {
+	if d.onEjectionChange != nil {
+		for _, c := range changes {
+			d.onEjectionChange(c.key, c.ejected)
+		}
+	}
+}
+
+func (d *Detector) endpoint(u *url.URL) (string, *endpoint) {
+	key := u.String()
+	e, ok := d.endpoints[key]
+	if !ok {
+		e = &endpoint{url: u}
+		d.endpoints[key] = e
+	}
+	return key, e
+}
+
+func (d *Detector) restore(key string, e *endpoint, changes []change) []change {
+	e.ejected = false
+	e.consecutive = 0
+	e.requests = 0
+	e.failures = 0
+	return append(changes, change{key, false})
+}
+
+func (d *Detector) expire(now time.Time, changes []change) []change {
+	for key, e := range d.endpoints {
+		if e.ejected && !now.Before(e.until) {
+			changes = d.restore(key, e, changes)
+		}
+	}
+	return changes
+}
+
+func (d *Detector) eject(key string, e *endpoint, now time.Time, changes []change) []change {
+	ejected := 0
+	for _, e := range d.endpoints {
+		if e.ejected {
+			ejected++
+		}
+	}
+	allowed := int(d.maxEjectionRatio * float64(d.total))
+	if allowed < 1 && d.total > 1 {
+		allowed = 1
+	}
+	if ejected >= allowed {
+		return changes
+	}
+	e.ejected = true
+	e.ejections++
+	ejectionTime := d.baseEjectionTime * time.Duration(e.ejections)
+	if ejectionTime > d.maxEjectionTime || ejectionTime <= 0 {
+		ejectionTime = d.maxEjectionTime
+	}
+	e.until = now.Add(ejectionTime)
+	e.generation++
+	if d.probe != nil {
+		go d.probing(key, e.url, e.generation)
+	}
+	return append(changes, change{key, true})
+}
+
+func (d *Detector) analyze(now time.Time, changes []change) []change {
+	if now.Sub(d.analyzedAt) < d.interval {
+		return changes
+	}
+	d.analyzedAt = now
+	if d.errorRateFactor > 0 {
+		var rates []float64
+		var mean float64
+		for _, e := range d.endpoints {
+			if !e.ejected && e.requests > 0 && e.requests >= d.minRequests {
+				rate := float64(e.failures) / float64(e.requests)
+				rates = append(rates, rate)
+				mean += rate
+			}
+		}
+		if n := len(rates); n >= minHosts {
+			mean /= float64(n)
+			var variance float64
+			for _, rate := range rates {
+				variance += (rate - mean) * (rate - mean)
+			}
+			threshold := mean + d.errorRateFactor*math.Sqrt(variance/float64(n))
+			for key, e := range d.endpoints {
+				if !e.ejected && e.requests > 0 && e.requests >= d.minRequests &&
+					float64(e.failures)/float64(e.requests) > threshold {
+					changes = d.eject(key, e, now, changes)
+				}
+			}
+		}
+	}
+	for _, e := range d.endpoints {
+		if !e.ejected && e.ejections > 0 {
+			e.ejections--
+		}
+		e.requests = 0
+		e.failures = 0
+	}
+	return changes
+}
+
+func (d *Detector) record(u *url.URL, total int, failed bool, now time.Time) {
+	var changes []change
+	d.lock.Lock()
+	d.total = total
+	changes = d.expire(now, changes)
+	key, e := d.endpoint(u)
+	if !e.ejected {
+		e.requests++
+		if failed {
+			e.failures++
+			e.consecutive++
+			if d.consecutiveFailures > 0 && e.consecutive >= d.consecutiveFailures {
+				changes = d.eject(key, e, now, changes)
+			}
+		} else {
+			e.consecutive = 0
+		}
+	}
+	changes = d.analyze(now, changes)
+	d.lock.Unlock()
+	d.report(changes)
+}
+
+func (d *Detector) probing(key string, u *url.URL, generation uint64) {
+	ticker := time.NewTicker(d.probeInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		d.lock.Lock()
+		e := d.endpoints[key]
+		ejected := e != nil && e.ejected && e.generation == generation && time.Now().Before(e.until)
+		d.lock.Unlock()
+		if !ejected {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), d.probeInterval)
+		err := d.probe(ctx, u)
+		cancel()
+		if err == nil {
+			var changes []change
+			d.lock.Lock()
+			if d.endpoints[key] == e && e.ejected && e.generation == generation {
+				changes = d.restore(key, e, changes)
+			}
+			d.lock.Unlock()
+			d.report(changes)
+			return
+		}
+	}
+}
+
+// Available returns false if the endpoint u is ejected.
+func (d *Detector) Available(ctx context.Context, u *url.URL) bool {
+	key := u.String()
+	var changes []change
+	d.lock.Lock()
+	e, ok := d.endpoints[key]
+	available := !ok || !e.ejected
+	if !available && !time.Now().Before(e.until) {
+		changes = d.restore(key, e, changes)
+		available = true
+	}
+	d.lock.Unlock()
+	d.report(changes)
+	return available
+}
+
+// Ejected returns the ejected endpoints.
+func (d *Detector) Ejected() (ejected []string) {
+	var changes []change
+	d.lock.Lock()
+	changes = d.expire(time.Now(), changes)
+	for key, e := range d.endpoints {
+		if e.ejected {
+			ejected = append(ejected, key)
+		}
+	}
+	d.lock.Unlock()
+	d.report(changes)
+	sort.Strings(ejected)
+	return
+}
+
+// SetEndpoints drops the states of the endpoints which are removed, it is
+// called by the client when Detector is used by client.Use(detector).
+func (d *Detector) SetEndpoints(endpoints []core.Endpoint) {
+	keys := make(map[string]bool, len(endpoints))
+	for _, endpoint := range endpoints {
+		keys[endpoint.URL.String()] = true
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for key := range d.endpoints {
+		if !keys[key] {
+			delete(d.endpoints, key)
+		}
+	}
+	d.total = len(endpoints)
+}
+
+// Handler for Detector.
+func (d *Detector) Handler(ctx context.Context, request []byte, next core.NextIOHandler) (response []byte, err error) {
+	clientContext := core.GetClientContext(ctx)
+	u, total := clientContext.URL, len(clientContext.URLs())
+	defer func() {
+		if e := recover(); e != nil {
+			err = core.NewPanicError(e)
+		}
+		if u != nil {
+			d.record(u, total, err != nil, time.Now())
+		}
+	}()
+	return next(ctx, request)
+}